@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var accessReportCmd = &cobra.Command{
+	Use:   "access-report",
+	Short: "Audit which documents users and groups can see",
+	Long: `Report who can see documents, from their owner and view and change
+permissions. Meant for admins of shared instances; the token needs to see
+all documents, users and groups for a complete report.
+
+Without --user or --group, documents visible to more than their owner are
+listed: unowned documents, which every user with document permissions can
+see and change, and documents shared with users or groups.
+
+With --user, all documents the user can see are listed with the reason:
+superuser, owner, unowned, shared with the user, or through a group. With
+--group, the documents shared with the group are listed.
+
+Example:
+  paperless access-report
+  paperless access-report --user kid
+  paperless access-report --group family --json`,
+	Args: cobra.NoArgs,
+	RunE: runAccessReport,
+}
+
+var (
+	accessReportUser          string
+	accessReportGroup         string
+	accessReportQuery         string
+	accessReportTags          []string
+	accessReportCorrespondent string
+	accessReportDocType       string
+)
+
+func init() {
+	rootCmd.AddCommand(accessReportCmd)
+
+	accessReportCmd.Flags().StringVar(&accessReportUser, "user", "", "list the documents this user can see (username or ID)")
+	accessReportCmd.Flags().StringVar(&accessReportGroup, "group", "", "list the documents shared with this group (name or ID)")
+	accessReportCmd.Flags().StringVar(&accessReportQuery, "query", "", "search query")
+	accessReportCmd.RegisterFlagCompletionFunc("query", completeQuery)
+	accessReportCmd.Flags().StringArrayVar(&accessReportTags, "tag", nil, "filter by tag (repeatable)")
+	accessReportCmd.Flags().StringVar(&accessReportCorrespondent, "correspondent", "", "filter by correspondent")
+	accessReportCmd.Flags().StringVar(&accessReportDocType, "type", "", "filter by document type")
+	accessReportCmd.MarkFlagsMutuallyExclusive("user", "group")
+}
+
+// accessEntry is a document in an access report
+type accessEntry struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Owner string `json:"owner"`
+	// Access is why the document is visible: "superuser", "owner",
+	// "unowned", "shared", "group <name>", or "view"/"change" for groups
+	Access     string   `json:"access,omitempty"`
+	CanChange  bool     `json:"can_change"`
+	SharedWith []string `json:"shared_with,omitempty"`
+}
+
+// accessNames maps user and group IDs to names for the report
+type accessNames struct {
+	users  map[int]string
+	groups map[int]string
+}
+
+// loadAccessNames looks up user and group names best effort; tokens
+// without user management permission only see IDs
+func loadAccessNames(client *api.Client) accessNames {
+	names := accessNames{users: map[int]string{}, groups: map[int]string{}}
+	if users, err := client.ListUsers(api.ListParams{}); err == nil {
+		for _, u := range users.Results {
+			names.users[u.ID] = u.Username
+		}
+	}
+	if groups, err := client.ListGroups(api.ListParams{}); err == nil {
+		for _, g := range groups.Results {
+			names.groups[g.ID] = g.Name
+		}
+	}
+	return names
+}
+
+func (n accessNames) user(id int) string {
+	if name, ok := n.users[id]; ok {
+		return name
+	}
+	return strconv.Itoa(id)
+}
+
+func (n accessNames) group(id int) string {
+	if name, ok := n.groups[id]; ok {
+		return name
+	}
+	return strconv.Itoa(id)
+}
+
+func (n accessNames) owner(doc api.Document) string {
+	if doc.Owner == nil {
+		return ""
+	}
+	return n.user(*doc.Owner)
+}
+
+// sharedWith lists who a document is shared with besides its owner, like
+// "anna", "group:family (change)"
+func (n accessNames) sharedWith(doc api.Document) []string {
+	if doc.Permissions == nil {
+		return nil
+	}
+	p := doc.Permissions
+
+	var shares []string
+	label := func(name string, change bool) string {
+		if change {
+			return name + " (change)"
+		}
+		return name
+	}
+	all := mergePermissionSet(p.View, p.Change)
+	for _, id := range all.Users {
+		if doc.Owner == nil || id != *doc.Owner {
+			shares = append(shares, label(n.user(id), slices.Contains(p.Change.Users, id)))
+		}
+	}
+	for _, id := range all.Groups {
+		shares = append(shares, label("group:"+n.group(id), slices.Contains(p.Change.Groups, id)))
+	}
+	return shares
+}
+
+// hasModelPermission reports whether a user has a permission like
+// "view_document", directly or through a group
+func hasModelPermission(u *api.User, codename string) bool {
+	if slices.Contains(u.UserPermissions, codename) {
+		return true
+	}
+	return slices.ContainsFunc(u.InheritedPermissions, func(p string) bool {
+		return p == codename || strings.HasSuffix(p, "."+codename)
+	})
+}
+
+// userAccess returns why a user can see a document and whether they can
+// change it. Change permission implies view permission.
+func userAccess(u *api.User, doc api.Document, names accessNames) (access string, canChange, ok bool) {
+	var p api.ObjectPermissions
+	if doc.Permissions != nil {
+		p = *doc.Permissions
+	}
+	inGroup := func(set api.PermissionSet) (int, bool) {
+		for _, id := range set.Groups {
+			if slices.Contains(u.Groups, id) {
+				return id, true
+			}
+		}
+		return 0, false
+	}
+	_, groupChange := inGroup(p.Change)
+	canChange = slices.Contains(p.Change.Users, u.ID) || groupChange
+
+	switch {
+	case u.IsSuperuser:
+		return "superuser", true, true
+	case doc.Owner != nil && *doc.Owner == u.ID:
+		return "owner", true, true
+	case doc.Owner == nil:
+		return "unowned", hasModelPermission(u, "change_document"), true
+	case slices.Contains(p.View.Users, u.ID) || slices.Contains(p.Change.Users, u.ID):
+		return "shared", canChange, true
+	}
+	if id, found := inGroup(mergePermissionSet(p.View, p.Change)); found {
+		return "group " + names.group(id), canChange, true
+	}
+	return "", false, false
+}
+
+func runAccessReport(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	var user *api.User
+	var group *api.Group
+	if accessReportUser != "" {
+		if user, err = findUser(client, accessReportUser); err != nil {
+			return err
+		}
+	}
+	if accessReportGroup != "" {
+		if group, err = findGroup(client, accessReportGroup); err != nil {
+			return err
+		}
+	}
+
+	// Without document permissions nothing is visible, not even owned or
+	// unowned documents
+	if user != nil && !user.IsSuperuser && !hasModelPermission(user, "view_document") {
+		if isJSON() {
+			return printJSON([]accessEntry{})
+		}
+		fmt.Printf("%s has no permission to view documents\n", user.Username)
+		return nil
+	}
+
+	docs, err := listAllDocuments(client, api.DocumentListParams{
+		Query:         accessReportQuery,
+		Tags:          accessReportTags,
+		Correspondent: accessReportCorrespondent,
+		DocumentType:  accessReportDocType,
+		FullPerms:     true,
+	})
+	if err != nil {
+		return err
+	}
+	names := loadAccessNames(client)
+
+	entries := []accessEntry{}
+	unowned, shared := 0, 0
+	for _, doc := range docs {
+		e := accessEntry{ID: doc.ID, Title: doc.Title, Owner: names.owner(doc), SharedWith: names.sharedWith(doc)}
+
+		switch {
+		case user != nil:
+			access, canChange, ok := userAccess(user, doc, names)
+			if !ok {
+				continue
+			}
+			e.Access, e.CanChange = access, canChange
+		case group != nil:
+			if doc.Permissions == nil {
+				continue
+			}
+			if slices.Contains(doc.Permissions.Change.Groups, group.ID) {
+				e.Access, e.CanChange = "change", true
+			} else if slices.Contains(doc.Permissions.View.Groups, group.ID) {
+				e.Access = "view"
+			} else {
+				continue
+			}
+		default:
+			if doc.Owner == nil {
+				e.Access, e.CanChange = "unowned", true
+			} else if len(e.SharedWith) > 0 {
+				e.Access = "shared"
+			} else {
+				continue
+			}
+		}
+
+		if doc.Owner == nil {
+			unowned++
+		} else if len(e.SharedWith) > 0 {
+			shared++
+		}
+		entries = append(entries, e)
+	}
+
+	if isJSON() {
+		return printJSON(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No documents found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	switch {
+	case user != nil:
+		fmt.Fprintln(w, "ID\tTITLE\tOWNER\tACCESS\tCHANGE")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", e.ID, truncate(e.Title, 40), ownerLabel(e.Owner), e.Access, yesNo(e.CanChange))
+		}
+	case group != nil:
+		fmt.Fprintln(w, "ID\tTITLE\tOWNER\tACCESS")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", e.ID, truncate(e.Title, 40), ownerLabel(e.Owner), e.Access)
+		}
+	default:
+		fmt.Fprintln(w, "ID\tTITLE\tOWNER\tVISIBLE TO")
+		for _, e := range entries {
+			visible := strings.Join(e.SharedWith, ", ")
+			if e.Access == "unowned" {
+				visible = "EVERYONE"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", e.ID, truncate(e.Title, 40), ownerLabel(e.Owner), visible)
+		}
+	}
+	w.Flush()
+
+	if !isQuiet() {
+		switch {
+		case user != nil:
+			fmt.Fprintf(os.Stderr, "\n%s can see %d document(s), %d of them unowned\n", user.Username, len(entries), unowned)
+		case group != nil:
+			fmt.Fprintf(os.Stderr, "\n%d document(s) shared with %s\n", len(entries), group.Name)
+		default:
+			fmt.Fprintf(os.Stderr, "\n%d unowned document(s) visible to every user, %d shared document(s)\n", unowned, shared)
+		}
+	}
+
+	return nil
+}
+
+// ownerLabel shows a missing owner in tables
+func ownerLabel(owner string) string {
+	if owner == "" {
+		return "-"
+	}
+	return owner
+}
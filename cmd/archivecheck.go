@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var docsArchiveCheckCmd = &cobra.Command{
+	Use:   "archive-check",
+	Short: "Find documents with a missing or outdated archived version",
+	Long: `Check each matching document's metadata for archive problems:
+
+  missing   the original is a PDF or image but there is no archived version
+  lost      an archived version is recorded but its file is gone or empty
+  outdated  the archived file is older than the original (by XMP ModifyDate)
+
+Paperless doesn't archive PDFs that already have text when OCR is set to
+skip_noarchive, so "missing" can be expected there.
+
+With --reprocess, the affected documents are sent through the consumer again,
+which regenerates the archived version. This also replaces their content with
+fresh OCR text, so manual content edits are lost. Documents on legal hold
+are left alone unless --override-hold is given.
+
+Example:
+  paperless documents archive-check
+  paperless documents archive-check --tag scans --json
+  paperless documents archive-check --reprocess`,
+	Args: cobra.NoArgs,
+	RunE: runDocsArchiveCheck,
+}
+
+var (
+	archiveCheckQuery         string
+	archiveCheckTags          []string
+	archiveCheckCorrespondent string
+	archiveCheckDocType       string
+	archiveCheckReprocess     bool
+	archiveCheckForce         bool
+)
+
+func init() {
+	documentsCmd.AddCommand(docsArchiveCheckCmd)
+
+	docsArchiveCheckCmd.Flags().StringVar(&archiveCheckQuery, "query", "", "search query")
+	docsArchiveCheckCmd.RegisterFlagCompletionFunc("query", completeQuery)
+	docsArchiveCheckCmd.Flags().StringArrayVar(&archiveCheckTags, "tag", nil, "filter by tag (repeatable)")
+	docsArchiveCheckCmd.Flags().StringVar(&archiveCheckCorrespondent, "correspondent", "", "filter by correspondent")
+	docsArchiveCheckCmd.Flags().StringVar(&archiveCheckDocType, "type", "", "filter by document type")
+	docsArchiveCheckCmd.Flags().BoolVar(&archiveCheckReprocess, "reprocess", false, "regenerate the archived version of the affected documents")
+	docsArchiveCheckCmd.Flags().BoolVarP(&archiveCheckForce, "force", "f", false, "skip confirmation")
+	addOverrideHoldFlag(docsArchiveCheckCmd)
+}
+
+// archiveProblem is a document whose archived version needs attention
+type archiveProblem struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	// Problem is "missing", "lost" or "outdated"
+	Problem string `json:"problem"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// checkArchive returns the archive problem of a document, if any
+func checkArchive(meta *api.DocumentMetadata) (problem, detail string) {
+	archivable := strings.HasPrefix(meta.OriginalMimeType, "image/") || meta.OriginalMimeType == "application/pdf"
+
+	switch {
+	case !meta.HasArchiveVersion:
+		if archivable {
+			return "missing", "no archived version of " + meta.OriginalMimeType
+		}
+	case meta.ArchiveSize == 0:
+		return "lost", "archive file " + meta.ArchiveMediaFileName + " is missing or empty"
+	default:
+		original, archive := xmpModifyDate(meta.OriginalMetadata), xmpModifyDate(meta.ArchiveMetadata)
+		if !original.IsZero() && !archive.IsZero() && archive.Before(original) {
+			return "outdated", fmt.Sprintf("archive modified %s, original %s",
+				archive.Format("2006-01-02 15:04"), original.Format("2006-01-02 15:04"))
+		}
+	}
+	return "", ""
+}
+
+// xmpModifyDate returns the XMP modification date of file metadata
+func xmpModifyDate(entries []api.MetadataEntry) time.Time {
+	for _, key := range []string{"ModifyDate", "MetadataDate"} {
+		for _, e := range entries {
+			if e.Prefix != "xmp" || e.Key != key {
+				continue
+			}
+			for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04Z07:00", "2006-01-02"} {
+				if t, err := time.Parse(layout, e.Value); err == nil {
+					return t
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func runDocsArchiveCheck(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	params := api.DocumentListParams{
+		Query:         archiveCheckQuery,
+		Tags:          archiveCheckTags,
+		Correspondent: archiveCheckCorrespondent,
+		DocumentType:  archiveCheckDocType,
+		Limit:         100,
+		Ordering:      "id",
+	}
+
+	sd := handleShutdown()
+	defer sd.release()
+
+	problems := []archiveProblem{}
+	checked, failed := 0, 0
+	stopped := false
+	for page := 1; ; page++ {
+		if sd.requested() {
+			stopped = true
+			break
+		}
+
+		params.Page = page
+		result, err := client.ListDocuments(params)
+		if err != nil {
+			return err
+		}
+
+		found := make([]archiveProblem, len(result.Results))
+		errs := forEachParallel(result.Results, maxPar, func(i int, doc api.Document) error {
+			meta, err := client.GetDocumentMetadata(doc.ID)
+			if err != nil {
+				return err
+			}
+			problem, detail := checkArchive(meta)
+			found[i] = archiveProblem{ID: doc.ID, Title: doc.Title, Problem: problem, Detail: detail}
+			return nil
+		})
+
+		for i, p := range found {
+			if errs[i] != nil {
+				failed++
+				fmt.Fprintf(os.Stderr, "document %d: %v\n", result.Results[i].ID, errs[i])
+				continue
+			}
+			checked++
+			if p.Problem != "" {
+				problems = append(problems, p)
+			}
+		}
+
+		if result.Next == "" {
+			break
+		}
+	}
+
+	if isJSON() {
+		if err := printJSON(problems); err != nil {
+			return err
+		}
+	} else if len(problems) > 0 {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTITLE\tPROBLEM\tDETAIL")
+		for _, p := range problems {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", p.ID, truncate(p.Title, 40), p.Problem, p.Detail)
+		}
+		w.Flush()
+	}
+
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Checked %d document(s), %d with archive problems\n", checked, len(problems))
+	}
+	if stopped {
+		return interrupted(cmd, "Interrupted, the results are partial")
+	}
+	sd.release()
+
+	if archiveCheckReprocess && len(problems) > 0 {
+		ids := make([]int, len(problems))
+		for i, p := range problems {
+			ids[i] = p.ID
+		}
+		hold, err := loadLegalHold(client)
+		if err != nil {
+			return err
+		}
+		if ids, err = hold.exclude(client, ids); err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if !archiveCheckForce {
+			msg := fmt.Sprintf("Reprocess %d document(s)? Their content is replaced with fresh OCR text.", len(ids))
+			if !confirmAction(msg) {
+				fmt.Println("Cancelled")
+				return nil
+			}
+		}
+
+		if err := client.BulkEditDocuments(ids, api.BulkReprocess()); err != nil {
+			return err
+		}
+		if !isQuiet() {
+			fmt.Fprintf(os.Stderr, "Queued %d document(s) for reprocessing\n", len(ids))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to read metadata for %d document(s)", failed)
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var docsASNCmd = &cobra.Command{
+	Use:   "asn [number]",
+	Short: "Look up and manage archive serial numbers",
+	Long: `Show the document with an archive serial number (ASN), e.g. the label on
+a paper original's folder, show the next free ASN and assign ASNs to
+documents.
+
+Example:
+  paperless documents asn 1042
+  paperless documents asn next
+  paperless documents asn assign 412`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDocsASN,
+}
+
+var docsASNNextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Show the next free archive serial number",
+	Args:  cobra.NoArgs,
+	RunE:  runDocsASNNext,
+}
+
+var docsASNAssignCmd = &cobra.Command{
+	Use:   "assign [id...]",
+	Short: "Assign the next free archive serial numbers",
+	Long: `Give documents consecutive archive serial numbers, starting at the next free
+one (or --start). Documents are numbered in the order their IDs are given,
+or by ID when selected with filters. Documents that already have an ASN are
+left alone, as are documents on legal hold (see 'config set hold') unless
+--override-hold is given.
+
+Assigning stops at the first failure, so the numbers stay without gaps.
+
+Example:
+  paperless documents asn assign 412
+  paperless documents asn assign 412 415 420
+  paperless documents asn assign --tag inbox --dry-run
+  paperless documents asn assign --query "contract" --start 2000 --yes`,
+	RunE: runDocsASNAssign,
+}
+
+var (
+	asnQuery         string
+	asnTags          []string
+	asnCorrespondent string
+	asnDocType       string
+	asnStart         int
+	asnDryRun        bool
+	asnYes           bool
+)
+
+func init() {
+	documentsCmd.AddCommand(docsASNCmd)
+	docsASNCmd.AddCommand(docsASNNextCmd)
+	docsASNCmd.AddCommand(docsASNAssignCmd)
+
+	docsASNAssignCmd.Flags().StringVar(&asnQuery, "query", "", "search query")
+	docsASNAssignCmd.RegisterFlagCompletionFunc("query", completeQuery)
+	docsASNAssignCmd.Flags().StringArrayVar(&asnTags, "tag", nil, "filter by tag (repeatable)")
+	docsASNAssignCmd.Flags().StringVar(&asnCorrespondent, "correspondent", "", "filter by correspondent")
+	docsASNAssignCmd.Flags().StringVar(&asnDocType, "type", "", "filter by document type")
+	docsASNAssignCmd.Flags().IntVar(&asnStart, "start", 0, "first ASN to assign (default: the next free one)")
+	docsASNAssignCmd.Flags().BoolVar(&asnDryRun, "dry-run", false, "show the numbers without assigning them")
+	docsASNAssignCmd.Flags().BoolVarP(&asnYes, "yes", "y", false, "skip confirmation")
+	addOverrideHoldFlag(docsASNAssignCmd)
+}
+
+func runDocsASN(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return cmd.Help()
+	}
+	asn, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid archive serial number: %s", args[0])
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	result, err := client.ListDocuments(api.DocumentListParams{ASN: &asn, Limit: 1})
+	if err != nil {
+		return err
+	}
+	if len(result.Results) == 0 {
+		return fmt.Errorf("no document with archive serial number %d", asn)
+	}
+	doc := &result.Results[0]
+
+	if isJSON() {
+		return printJSON(doc)
+	}
+	return printDocument(client, doc)
+}
+
+func runDocsASNNext(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	asn, err := client.GetNextASN()
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(map[string]int{"next_asn": asn})
+	}
+	fmt.Println(asn)
+	return nil
+}
+
+// asnAssignment is an archive serial number given to a document
+type asnAssignment struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	ASN   int    `json:"asn"`
+}
+
+// asnCandidates returns the documents given by ID or matching the filters,
+// in the order they are numbered
+func asnCandidates(client *api.Client, args []string) ([]api.Document, error) {
+	if len(args) == 0 {
+		return listAllDocuments(client, api.DocumentListParams{
+			Query:         asnQuery,
+			Tags:          asnTags,
+			Correspondent: asnCorrespondent,
+			DocumentType:  asnDocType,
+		})
+	}
+
+	ids, err := parseDocumentIDs(args)
+	if err != nil {
+		return nil, err
+	}
+	var docs []api.Document
+	for _, id := range ids {
+		doc, err := client.GetDocument(id)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, *doc)
+	}
+	return docs, nil
+}
+
+func runDocsASNAssign(cmd *cobra.Command, args []string) error {
+	filtered := asnQuery != "" || len(asnTags) > 0 || asnCorrespondent != "" || asnDocType != ""
+	if len(args) > 0 && filtered {
+		return fmt.Errorf("give document IDs or filters, not both")
+	}
+	if len(args) == 0 && !filtered {
+		return fmt.Errorf("no documents given; pass IDs or use --query, --tag, --correspondent or --type")
+	}
+	if cmd.Flags().Changed("start") && asnStart < 1 {
+		return fmt.Errorf("--start must be positive")
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	docs, err := asnCandidates(client, args)
+	if err != nil {
+		return err
+	}
+
+	hold, err := loadLegalHold(client)
+	if err != nil {
+		return err
+	}
+
+	var pending []api.Document
+	numbered, onHold := 0, 0
+	for _, doc := range docs {
+		switch {
+		case doc.ArchiveSerialNumber != nil:
+			numbered++
+		case !overrideHold && hold.holds(doc):
+			onHold++
+		default:
+			pending = append(pending, doc)
+		}
+	}
+	hold.reportSkipped(onHold)
+	if numbered > 0 && !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Skipping %d document(s) that already have an ASN\n", numbered)
+	}
+
+	if len(pending) == 0 {
+		if isJSON() {
+			return printJSON([]asnAssignment{})
+		}
+		fmt.Println("No documents without an ASN found")
+		return nil
+	}
+
+	next := asnStart
+	if next == 0 {
+		if next, err = client.GetNextASN(); err != nil {
+			return err
+		}
+	}
+
+	assignments := make([]asnAssignment, len(pending))
+	for i, doc := range pending {
+		assignments[i] = asnAssignment{ID: doc.ID, Title: doc.Title, ASN: next + i}
+	}
+
+	if asnDryRun {
+		if isJSON() {
+			return printJSON(assignments)
+		}
+		printASNAssignments(assignments)
+		if !isQuiet() {
+			fmt.Fprintf(os.Stderr, "\nDry run: would assign ASNs %d-%d to %d document(s)\n",
+				next, next+len(assignments)-1, len(assignments))
+		}
+		return nil
+	}
+
+	if !asnYes && len(assignments) > 1 {
+		msg := fmt.Sprintf("Assign ASNs %d-%d to %d document(s)?", next, next+len(assignments)-1, len(assignments))
+		if !confirmAction(msg) {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	for i, a := range assignments {
+		if _, err := client.UpdateDocument(a.ID, map[string]interface{}{"archive_serial_number": a.ASN}); err != nil {
+			return fmt.Errorf("assigning ASN %d to document %d: %w (%d of %d assigned)", a.ASN, a.ID, err, i, len(assignments))
+		}
+		if !isQuiet() && !isJSON() {
+			fmt.Printf("Assigned ASN %d to document %d (%s)\n", a.ASN, a.ID, truncate(a.Title, 60))
+		}
+	}
+
+	if isJSON() {
+		return printJSON(assignments)
+	}
+	return nil
+}
+
+// printASNAssignments lists the numbers a run would assign
+func printASNAssignments(assignments []asnAssignment) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ASN\tID\tTITLE")
+	for _, a := range assignments {
+		fmt.Fprintf(w, "%d\t%d\t%s\n", a.ASN, a.ID, truncate(a.Title, 60))
+	}
+	w.Flush()
+}
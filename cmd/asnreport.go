@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var docsASNReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report the used ASN range, gaps and duplicates",
+	Long: `Check that the archive serial numbers match a consistent physical archive:
+show the range of ASNs in use, the numbers missing from it and any ASN
+given to more than one document.
+
+Example:
+  paperless documents asn report
+  paperless documents asn report --json`,
+	Args: cobra.NoArgs,
+	RunE: runDocsASNReport,
+}
+
+func init() {
+	docsASNCmd.AddCommand(docsASNReportCmd)
+}
+
+// asnRange is a run of consecutive archive serial numbers
+type asnRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+func (r asnRange) String() string {
+	if r.From == r.To {
+		return fmt.Sprint(r.From)
+	}
+	return fmt.Sprintf("%d-%d", r.From, r.To)
+}
+
+// asnDuplicate is an archive serial number shared by several documents
+type asnDuplicate struct {
+	ASN       int   `json:"asn"`
+	Documents []int `json:"documents"`
+}
+
+// asnReport summarizes the archive serial numbers in use
+type asnReport struct {
+	Documents  int            `json:"documents"`
+	Lowest     int            `json:"lowest,omitempty"`
+	Highest    int            `json:"highest,omitempty"`
+	Missing    int            `json:"missing"`
+	Gaps       []asnRange     `json:"gaps"`
+	Duplicates []asnDuplicate `json:"duplicates"`
+}
+
+func runDocsASNReport(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	docs, err := listAllDocuments(client, api.DocumentListParams{
+		Fields: []string{"id", "archive_serial_number"},
+		Filter: url.Values{"archive_serial_number__isnull": {"false"}},
+	})
+	if err != nil {
+		return err
+	}
+
+	report := buildASNReport(docs)
+	if isJSON() {
+		return printJSON(report)
+	}
+
+	if report.Documents == 0 {
+		fmt.Println("No documents have an archive serial number")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Documents with ASN:\t%d\n", report.Documents)
+	fmt.Fprintf(w, "Range:\t%d-%d\n", report.Lowest, report.Highest)
+	fmt.Fprintf(w, "Missing numbers:\t%d\n", report.Missing)
+	fmt.Fprintf(w, "Duplicate ASNs:\t%d\n", len(report.Duplicates))
+	w.Flush()
+
+	if len(report.Gaps) > 0 {
+		fmt.Println("\nGaps:")
+		for _, gap := range report.Gaps {
+			fmt.Printf("  %s\n", gap)
+		}
+	}
+	if len(report.Duplicates) > 0 {
+		fmt.Println("\nDuplicates:")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  ASN\tDOCUMENTS")
+		for _, d := range report.Duplicates {
+			fmt.Fprintf(w, "  %d\t%s\n", d.ASN, joinInts(d.Documents, ", "))
+		}
+		w.Flush()
+	}
+	return nil
+}
+
+// buildASNReport finds the range, gaps and duplicates of the documents'
+// archive serial numbers
+func buildASNReport(docs []api.Document) asnReport {
+	report := asnReport{Gaps: []asnRange{}, Duplicates: []asnDuplicate{}}
+
+	byASN := make(map[int][]int)
+	var asns []int
+	for _, doc := range docs {
+		if doc.ArchiveSerialNumber == nil {
+			continue
+		}
+		asn := *doc.ArchiveSerialNumber
+		if byASN[asn] == nil {
+			asns = append(asns, asn)
+		}
+		byASN[asn] = append(byASN[asn], doc.ID)
+		report.Documents++
+	}
+	if len(asns) == 0 {
+		return report
+	}
+	slices.Sort(asns)
+
+	report.Lowest, report.Highest = asns[0], asns[len(asns)-1]
+	for i, asn := range asns {
+		if i > 0 && asn > asns[i-1]+1 {
+			gap := asnRange{asns[i-1] + 1, asn - 1}
+			report.Gaps = append(report.Gaps, gap)
+			report.Missing += gap.To - gap.From + 1
+		}
+		if ids := byASN[asn]; len(ids) > 1 {
+			report.Duplicates = append(report.Duplicates, asnDuplicate{asn, ids})
+		}
+	}
+	return report
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/julianfbeck/paperless-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var autotagCmd = &cobra.Command{
+	Use:   "autotag",
+	Short: "Add tags to documents by rule",
+	Long: `Apply client-side tagging rules across the archive.
+
+--implications adds the tags implied by the rules in the config file (see
+"paperless config implications") to every document missing them. Rules
+chain, so with "insurance implies finance" and "finance implies money" an
+insurance document gets both. Uploads apply the rules automatically.
+
+Example:
+  paperless config implications add "tag:insurance implies tag:finance"
+  paperless autotag --implications --dry-run
+  paperless autotag --implications`,
+	Args: cobra.NoArgs,
+	RunE: runAutotag,
+}
+
+var (
+	autotagImplications bool
+	autotagDryRun       bool
+)
+
+func init() {
+	rootCmd.AddCommand(autotagCmd)
+
+	autotagCmd.Flags().BoolVar(&autotagImplications, "implications", false, "add tags implied by the configured implication rules")
+	autotagCmd.Flags().BoolVar(&autotagDryRun, "dry-run", false, "show how many documents would be tagged without changing them")
+}
+
+// tagImplications maps a tag ID to every tag it implies, directly or
+// through other rules
+type tagImplications map[int][]int
+
+// loadTagImplications resolves the configured rules to tag IDs. It also
+// returns the names of all tags involved.
+func loadTagImplications(client *api.Client) (tagImplications, map[int]string, error) {
+	rules, err := config.GetImplications()
+	if err != nil || len(rules) == 0 {
+		return nil, nil, err
+	}
+
+	names := make(map[int]string)
+	ids := make(map[string]int)
+	resolve := func(name string) (int, error) {
+		if id, ok := ids[name]; ok {
+			return id, nil
+		}
+		tag, err := client.FindTagByName(name)
+		if err != nil {
+			return 0, err
+		}
+		ids[name] = tag.ID
+		names[tag.ID] = tag.Name
+		return tag.ID, nil
+	}
+
+	direct := make(map[int][]int)
+	for _, rule := range rules {
+		from, err := resolve(rule.Tag)
+		if err != nil {
+			return nil, nil, fmt.Errorf("implication %q: %w", rule, err)
+		}
+		to, err := resolve(rule.Implies)
+		if err != nil {
+			return nil, nil, fmt.Errorf("implication %q: %w", rule, err)
+		}
+		direct[from] = append(direct[from], to)
+	}
+
+	implications := make(tagImplications, len(direct))
+	for from := range direct {
+		seen := map[int]bool{from: true}
+		queue := append([]int(nil), direct[from]...)
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			implications[from] = append(implications[from], id)
+			queue = append(queue, direct[id]...)
+		}
+		sort.Ints(implications[from])
+	}
+
+	return implications, names, nil
+}
+
+// apply returns tags plus the implied tags missing from it
+func (ti tagImplications) apply(tags []int) []int {
+	have := make(map[int]bool, len(tags))
+	for _, id := range tags {
+		have[id] = true
+	}
+
+	result := tags
+	for _, id := range tags {
+		for _, implied := range ti[id] {
+			if !have[implied] {
+				have[implied] = true
+				result = append(result, implied)
+			}
+		}
+	}
+	return result
+}
+
+// sources returns the tags implying target, in ID order
+func (ti tagImplications) sources(target int) []int {
+	var ids []int
+	for from, implied := range ti {
+		for _, id := range implied {
+			if id == target {
+				ids = append(ids, from)
+				break
+			}
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// targets returns all implied tags, in ID order
+func (ti tagImplications) targets() []int {
+	seen := make(map[int]bool)
+	var ids []int
+	for _, implied := range ti {
+		for _, id := range implied {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func runAutotag(cmd *cobra.Command, args []string) error {
+	if !autotagImplications {
+		return fmt.Errorf("nothing to do; use --implications")
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	implications, names, err := loadTagImplications(client)
+	if err != nil {
+		return err
+	}
+	if len(implications) == 0 {
+		return fmt.Errorf("no implications configured; add one with: paperless config implications add \"tag:A implies tag:B\"")
+	}
+
+	type tagResult struct {
+		Tag       string `json:"tag"`
+		Documents []int  `json:"documents"`
+	}
+	var results []tagResult
+	tagged := 0
+
+	for _, target := range implications.targets() {
+		// Find documents carrying an implying tag but not the implied one
+		result, err := client.ListDocuments(api.DocumentListParams{
+			TagIDsAny:  implications.sources(target),
+			TagIDsNone: []int{target},
+			Limit:      1,
+		})
+		if err != nil {
+			return err
+		}
+		ids := result.All
+		results = append(results, tagResult{Tag: names[target], Documents: ids})
+
+		if len(ids) > 0 && !autotagDryRun {
+			if err := client.BulkEditDocuments(ids, api.BulkAddTag(target)); err != nil {
+				return fmt.Errorf("adding tag %s: %w", names[target], err)
+			}
+			tagged += len(ids)
+		}
+
+		if !isJSON() && !isQuiet() {
+			fmt.Printf("%s: %d document(s)\n", names[target], len(ids))
+		}
+	}
+
+	if isJSON() {
+		return printJSON(map[string]interface{}{
+			"dry_run": autotagDryRun,
+			"tags":    results,
+		})
+	}
+
+	if !isQuiet() {
+		if autotagDryRun {
+			fmt.Println("Dry run: no documents changed")
+		} else {
+			fmt.Printf("Added %d implied tag(s)\n", tagged)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Measure server latency and throughput",
+	Long: `Measure list, download and (optionally) upload performance against the
+configured server.
+
+The benchmark first times connection setup (DNS, TCP, TLS) and checks that
+keep-alive connections are reused, then sweeps concurrency levels up to
+--max-parallel for list and download requests. The findings point at the
+likely bottleneck and suggest a --max-parallel value for bulk operations.
+
+Uploading creates a real document, so it only runs with --upload.
+
+Example:
+  paperless bench
+  paperless bench --requests 20 --max-parallel 16
+  paperless bench --document 123 --upload testdata/test_upload.pdf`,
+	RunE: runBench,
+}
+
+var (
+	benchRequests int
+	benchDocument int
+	benchUpload   string
+)
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+
+	benchCmd.Flags().IntVar(&benchRequests, "requests", 10, "requests per concurrency level")
+	benchCmd.Flags().IntVar(&benchDocument, "document", 0, "document ID used for download tests (default: most recent)")
+	benchCmd.Flags().StringVar(&benchUpload, "upload", "", "also time uploading this file (creates a document)")
+}
+
+// benchLevel summarizes the requests run at one concurrency level
+type benchLevel struct {
+	Concurrency int           `json:"concurrency"`
+	Requests    int           `json:"requests"`
+	Errors      int           `json:"errors"`
+	Reused      int           `json:"reused_connections"`
+	PerSecond   float64       `json:"requests_per_second"`
+	MBPerSecond float64       `json:"mb_per_second"`
+	P50         time.Duration `json:"p50"`
+	FirstByte   time.Duration `json:"p50_first_byte"`
+}
+
+// benchReport is the full benchmark output
+type benchReport struct {
+	Cold     *api.Timing   `json:"cold"`
+	Warm     *api.Timing   `json:"warm"`
+	List     []benchLevel  `json:"list"`
+	Download []benchLevel  `json:"download,omitempty"`
+	Upload   time.Duration `json:"upload,omitempty"`
+	Findings []string      `json:"findings"`
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	listPath := "/api/documents/?page_size=25&truncate_content=true"
+	report := &benchReport{}
+
+	// The first request of the process pays for connection setup
+	report.Cold, err = client.TimedGet(listPath)
+	if err != nil {
+		return err
+	}
+	report.Warm, err = client.TimedGet(listPath)
+	if err != nil {
+		return err
+	}
+
+	levels := benchLevels(maxPar)
+	for _, c := range levels {
+		benchProgress("list documents at concurrency %d", c)
+		report.List = append(report.List, runBenchLevel(client, listPath, c, benchRequests))
+	}
+
+	docID := benchDocument
+	if docID == 0 {
+		result, err := client.ListDocuments(api.DocumentListParams{Limit: 1, Ordering: "-added"})
+		if err != nil {
+			return err
+		}
+		if len(result.Results) > 0 {
+			docID = result.Results[0].ID
+		}
+	}
+	if docID != 0 {
+		downloadPath := fmt.Sprintf("/api/documents/%d/download/", docID)
+		for _, c := range levels {
+			benchProgress("download document %d at concurrency %d", docID, c)
+			report.Download = append(report.Download, runBenchLevel(client, downloadPath, c, benchRequests))
+		}
+	}
+
+	if benchUpload != "" {
+		benchProgress("upload %s", filepath.Base(benchUpload))
+		start := time.Now()
+		if _, err := client.UploadDocument(benchUpload, "paperless-cli bench", nil, nil, nil); err != nil {
+			return fmt.Errorf("upload failed: %w", err)
+		}
+		report.Upload = time.Since(start)
+	}
+
+	report.Findings = benchFindings(report)
+
+	if isJSON() {
+		return printJSON(report)
+	}
+
+	printBenchReport(report, docID)
+	return nil
+}
+
+// benchLevels returns the concurrency levels to sweep: powers of two up to max
+func benchLevels(max int) []int {
+	levels := []int{1}
+	for c := 2; c < max; c *= 2 {
+		levels = append(levels, c)
+	}
+	if max > 1 {
+		levels = append(levels, max)
+	}
+	return levels
+}
+
+// runBenchLevel issues n GET requests to path with the given concurrency
+func runBenchLevel(client *api.Client, path string, concurrency, n int) benchLevel {
+	level := benchLevel{Concurrency: concurrency, Requests: n}
+
+	var mu sync.Mutex
+	var timings []*api.Timing
+	jobs := make(chan struct{})
+	var wg sync.WaitGroup
+
+	start := time.Now()
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				t, err := client.TimedGet(path)
+				mu.Lock()
+				if err != nil {
+					level.Errors++
+				} else {
+					timings = append(timings, t)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- struct{}{}
+	}
+	close(jobs)
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	if len(timings) == 0 {
+		return level
+	}
+
+	var bytes int64
+	totals := make([]time.Duration, len(timings))
+	firstBytes := make([]time.Duration, len(timings))
+	for i, t := range timings {
+		bytes += t.Bytes
+		totals[i] = t.Total
+		firstBytes[i] = t.FirstByte
+		if t.Reused {
+			level.Reused++
+		}
+	}
+
+	level.PerSecond = float64(len(timings)) / elapsed.Seconds()
+	level.MBPerSecond = float64(bytes) / (1 << 20) / elapsed.Seconds()
+	level.P50 = median(totals)
+	level.FirstByte = median(firstBytes)
+	return level
+}
+
+func median(values []time.Duration) time.Duration {
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	return values[len(values)/2]
+}
+
+// bestLevel returns the level with the highest value of metric
+func bestLevel(levels []benchLevel, metric func(benchLevel) float64) benchLevel {
+	best := levels[0]
+	for _, l := range levels[1:] {
+		if metric(l) > metric(best) {
+			best = l
+		}
+	}
+	return best
+}
+
+// benchFindings interprets the measurements into human readable hints
+func benchFindings(r *benchReport) []string {
+	var findings []string
+
+	setup := r.Cold.DNS + r.Cold.Connect + r.Cold.TLS
+	if setup > r.Cold.FirstByte/2 {
+		findings = append(findings, fmt.Sprintf("connection setup (DNS/TCP/TLS) takes %s of the first request's %s; keep-alive reuse matters for scripts running many short commands", round(setup), round(r.Cold.FirstByte)))
+	}
+	if !r.Warm.Reused {
+		findings = append(findings, "the second request opened a new connection; a proxy in front of Paperless may be disabling keep-alive")
+	}
+
+	perSecond := func(l benchLevel) float64 { return l.PerSecond }
+	listBest := bestLevel(r.List, perSecond)
+	listScales := listBest.PerSecond >= 1.5*r.List[0].PerSecond
+	if !listScales {
+		findings = append(findings, fmt.Sprintf("server CPU: list throughput does not improve with concurrency (%.1f req/s at 1, %.1f req/s at best); keep --max-parallel at 1-2", r.List[0].PerSecond, listBest.PerSecond))
+	} else {
+		findings = append(findings, fmt.Sprintf("list throughput peaks at concurrency %d (%.1f req/s); --max-parallel %d is a good setting", listBest.Concurrency, listBest.PerSecond, listBest.Concurrency))
+	}
+
+	if len(r.Download) > 0 {
+		mbPerSecond := func(l benchLevel) float64 { return l.MBPerSecond }
+		dlBest := bestLevel(r.Download, mbPerSecond)
+		if listScales && dlBest.MBPerSecond < 1.3*r.Download[0].MBPerSecond {
+			findings = append(findings, fmt.Sprintf("bandwidth: downloads stay at about %.1f MB/s regardless of concurrency, so the network link is the limit", dlBest.MBPerSecond))
+		}
+	}
+
+	return findings
+}
+
+func printBenchReport(r *benchReport, docID int) {
+	fmt.Println("Connection")
+	fmt.Printf("  DNS %s  Connect %s  TLS %s  First byte %s  Reused on 2nd request: %t\n\n",
+		round(r.Cold.DNS), round(r.Cold.Connect), round(r.Cold.TLS), round(r.Cold.FirstByte), r.Warm.Reused)
+
+	fmt.Println("List documents (25 per page)")
+	printBenchLevels(r.List, false)
+
+	if len(r.Download) > 0 {
+		fmt.Printf("\nDownload document %d\n", docID)
+		printBenchLevels(r.Download, true)
+	}
+
+	if r.Upload > 0 {
+		fmt.Printf("\nUpload: %s\n", round(r.Upload))
+	}
+
+	fmt.Println("\nFindings")
+	for _, f := range r.Findings {
+		fmt.Printf("  - %s\n", f)
+	}
+}
+
+func printBenchLevels(levels []benchLevel, bytes bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  CONCURRENCY\tREQ/S\tMB/S\tP50\tFIRST BYTE\tREUSED\tERRORS")
+	for _, l := range levels {
+		mb := "-"
+		if bytes {
+			mb = fmt.Sprintf("%.2f", l.MBPerSecond)
+		}
+		fmt.Fprintf(w, "  %d\t%.1f\t%s\t%s\t%s\t%d/%d\t%d\n", l.Concurrency, l.PerSecond, mb, round(l.P50), round(l.FirstByte), l.Reused, l.Requests-l.Errors, l.Errors)
+	}
+	w.Flush()
+}
+
+func benchProgress(format string, args ...interface{}) {
+	if !isQuiet() && !isJSON() {
+		fmt.Fprintf(os.Stderr, "Benchmarking "+format+"...\n", args...)
+	}
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Millisecond)
+}
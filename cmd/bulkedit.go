@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"text/tabwriter"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var docsBulkEditCmd = &cobra.Command{
+	Use:   "bulk-edit [id...]",
+	Short: "Change tags, correspondent, type or storage path of many documents",
+	Long: `Change the tags, correspondent, document type or storage path of the given
+documents, or of every document matching the filters, with bulk edits of up
+to 100 documents.
+
+The number of affected documents is shown for confirmation first; --dry-run
+lists them without changing anything. "-" or "none" clears a correspondent,
+type or storage path. Documents on legal hold (see 'config set hold') are
+skipped unless --override-hold is given.
+
+Example:
+  paperless documents bulk-edit --query "statement" --add-tag archived --set-correspondent "My Bank"
+  paperless documents bulk-edit 12 13 14 --remove-tag inbox --force
+  paperless documents bulk-edit --tag scans --set-type none --dry-run`,
+	RunE: runDocsBulkEdit,
+}
+
+var (
+	bulkQuery            string
+	bulkTags             []string
+	bulkCorrespondent    string
+	bulkDocType          string
+	bulkAll              bool
+	bulkAddTags          []string
+	bulkRemoveTags       []string
+	bulkSetCorrespondent string
+	bulkSetDocType       string
+	bulkSetStoragePath   string
+	bulkDryRun           bool
+	bulkForce            bool
+)
+
+// bulkEditBatchSize is the number of documents per bulk edit request
+const bulkEditBatchSize = 100
+
+func init() {
+	documentsCmd.AddCommand(docsBulkEditCmd)
+
+	f := docsBulkEditCmd.Flags()
+	f.StringVar(&bulkQuery, "query", "", "search query")
+	docsBulkEditCmd.RegisterFlagCompletionFunc("query", completeQuery)
+	f.StringArrayVar(&bulkTags, "tag", nil, "filter by tag (repeatable)")
+	f.StringVar(&bulkCorrespondent, "correspondent", "", "filter by correspondent")
+	f.StringVar(&bulkDocType, "type", "", "filter by document type")
+	f.BoolVar(&bulkAll, "all", false, "apply to all documents when no IDs or filters are given")
+	f.StringArrayVar(&bulkAddTags, "add-tag", nil, "add tag (repeatable)")
+	f.StringArrayVar(&bulkRemoveTags, "remove-tag", nil, "remove tag (repeatable)")
+	f.StringVar(&bulkSetCorrespondent, "set-correspondent", "", `set correspondent by name or ID, "none" to clear`)
+	f.StringVar(&bulkSetDocType, "set-type", "", `set document type by name or ID, "none" to clear`)
+	f.StringVar(&bulkSetStoragePath, "set-storage-path", "", `set storage path by name or ID, "none" to clear`)
+	f.BoolVar(&bulkDryRun, "dry-run", false, "list the documents that would change without changing them")
+	f.BoolVarP(&bulkForce, "force", "f", false, "skip confirmation")
+	createMissing.register(docsBulkEditCmd)
+	addOverrideHoldFlag(docsBulkEditCmd)
+}
+
+// bulkChange is a bulk operation with a description for the confirmation
+type bulkChange struct {
+	op   api.BulkOperation
+	desc string
+}
+
+func runDocsBulkEdit(cmd *cobra.Command, args []string) error {
+	filtered := bulkQuery != "" || len(bulkTags) > 0 || bulkCorrespondent != "" || bulkDocType != ""
+	switch {
+	case len(args) > 0 && (filtered || bulkAll):
+		return fmt.Errorf("give either document IDs or filters, not both")
+	case len(args) == 0 && !filtered && !bulkAll:
+		return fmt.Errorf("no documents given; pass IDs or use --query, --tag, --correspondent, --type or --all")
+	}
+	ids, err := parseDocumentIDs(args)
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	changes, err := resolveBulkChanges(client)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return fmt.Errorf("nothing to change; use --add-tag, --remove-tag, --set-correspondent, --set-type or --set-storage-path")
+	}
+	descs := make([]string, len(changes))
+	for i, c := range changes {
+		descs[i] = c.desc
+	}
+	summary := strings.Join(descs, ", ")
+
+	params := api.DocumentListParams{
+		Query:         bulkQuery,
+		Tags:          bulkTags,
+		Correspondent: bulkCorrespondent,
+		DocumentType:  bulkDocType,
+	}
+	if len(ids) > 0 {
+		params.Filter = url.Values{"id__in": {joinInts(ids, ",")}}
+	}
+
+	hold, err := loadLegalHold(client)
+	if err != nil {
+		return err
+	}
+
+	if bulkDryRun {
+		return previewBulkEdit(client, params, summary, hold)
+	}
+
+	if len(ids) == 0 {
+		params.Limit = 1
+		result, err := client.ListDocuments(params)
+		if err != nil {
+			return err
+		}
+		ids = result.All
+	}
+	if ids, err = hold.exclude(client, ids); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		fmt.Println("No documents found")
+		return nil
+	}
+
+	if !bulkForce && !confirmAction(fmt.Sprintf("Bulk edit %d document(s): %s?", len(ids), summary)) {
+		fmt.Println("Cancelled")
+		return nil
+	}
+
+	ops := make([]api.BulkOperation, len(changes))
+	for i, c := range changes {
+		ops[i] = c.op
+	}
+	return reportBulkUpdate(len(ids), bulkEditBatches(client, ids, ops...))
+}
+
+// resolveBulkChanges turns the change flags into bulk operations
+func resolveBulkChanges(client *api.Client) ([]bulkChange, error) {
+	var changes []bulkChange
+
+	if len(bulkAddTags) > 0 || len(bulkRemoveTags) > 0 {
+		var add, remove []int
+		for _, name := range bulkAddTags {
+			id, err := resolveTag(client, name)
+			if err != nil {
+				return nil, err
+			}
+			add = append(add, id)
+		}
+		for _, name := range bulkRemoveTags {
+			id, err := resolveTag(client, name)
+			if err != nil {
+				return nil, err
+			}
+			remove = append(remove, id)
+		}
+		var descs []string
+		if len(add) > 0 {
+			descs = append(descs, "add tag "+strings.Join(bulkAddTags, ", "))
+		}
+		if len(remove) > 0 {
+			descs = append(descs, "remove tag "+strings.Join(bulkRemoveTags, ", "))
+		}
+		changes = append(changes, bulkChange{api.BulkModifyTags(add, remove), strings.Join(descs, ", ")})
+	}
+
+	for _, s := range []struct {
+		arg, kind string
+		resolve   func(*api.Client, string) (int, error)
+		op        func(*int) api.BulkOperation
+	}{
+		{bulkSetCorrespondent, "correspondent", resolveCorrespondent, api.BulkSetCorrespondent},
+		{bulkSetDocType, "document type", resolveDocumentType, api.BulkSetDocumentType},
+		{bulkSetStoragePath, "storage path", resolveStoragePath, api.BulkSetStoragePath},
+	} {
+		switch s.arg {
+		case "":
+		case "-", "none":
+			changes = append(changes, bulkChange{s.op(nil), "clear " + s.kind})
+		default:
+			id, err := s.resolve(client, s.arg)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, bulkChange{s.op(&id), fmt.Sprintf("set %s %s", s.kind, s.arg)})
+		}
+	}
+
+	return changes, nil
+}
+
+// previewBulkEdit lists the documents a bulk edit would change
+func previewBulkEdit(client *api.Client, params api.DocumentListParams, summary string, hold *legalHold) error {
+	docs, err := listAllDocuments(client, params)
+	if err != nil {
+		return err
+	}
+	if !overrideHold {
+		before := len(docs)
+		docs = slices.DeleteFunc(docs, hold.holds)
+		hold.reportSkipped(before - len(docs))
+	}
+
+	if isJSON() {
+		return printJSON(map[string]interface{}{
+			"dry_run":   true,
+			"changes":   summary,
+			"documents": docs,
+		})
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE")
+	for _, doc := range docs {
+		fmt.Fprintf(w, "%d\t%s\n", doc.ID, truncate(doc.Title, 60))
+	}
+	w.Flush()
+
+	fmt.Fprintf(os.Stderr, "\nDry run: would %s on %d document(s)\n", summary, len(docs))
+	return nil
+}
+
+// bulkEditBatches applies the operations to ids in bulk edit requests of
+// up to bulkEditBatchSize documents, and returns the documents that failed
+func bulkEditBatches(client *api.Client, ids []int, ops ...api.BulkOperation) map[int]error {
+	var batches [][]int
+	for start := 0; start < len(ids); start += bulkEditBatchSize {
+		batches = append(batches, ids[start:min(start+bulkEditBatchSize, len(ids))])
+	}
+
+	progress, done := newCountProgressBar("Updating")
+	defer done()
+
+	var updated atomic.Int64
+	errs := forEachParallel(batches, maxPar, func(i int, batch []int) error {
+		for _, op := range ops {
+			if err := client.BulkEditDocuments(batch, op); err != nil {
+				return err
+			}
+		}
+		if progress != nil {
+			progress(updated.Add(int64(len(batch))), int64(len(ids)))
+		}
+		return nil
+	})
+
+	failures := make(map[int]error)
+	for i, err := range errs {
+		if err != nil {
+			for _, id := range batches[i] {
+				failures[id] = err
+			}
+		}
+	}
+	return failures
+}
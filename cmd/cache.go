@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/julianfbeck/paperless-cli/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local metadata cache",
+	Long: `Tags, correspondents, document types, storage paths and custom fields are
+cached in ~/.cache/paperless-cli so that resolving names doesn't refetch them
+on every command. Entries are used without asking the server for the
+configured TTL (see 'paperless config set cache-ttl', default 5m) and
+revalidated afterwards. Changes made through this CLI invalidate the cache
+immediately; use --no-cache or 'cache clear' to pick up changes made
+elsewhere right away.
+
+The document titles 'paperless o' matches against are kept there too; they
+are only refreshed with 'paperless o --refresh'.`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached data",
+	Long: `Remove all cached metadata lists.
+
+Example:
+  paperless cache clear`,
+	Args: cobra.NoArgs,
+	RunE: runCacheClear,
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return err
+	}
+
+	n, err := cache.New(dir).Clear()
+	if err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(map[string]interface{}{"path": dir, "removed": n})
+	}
+	if !isQuiet() {
+		fmt.Printf("Removed %d cached entries from %s\n", n, dir)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/julianfbeck/paperless-cli/internal/cache"
+	"github.com/julianfbeck/paperless-cli/internal/config"
+)
+
+// checkpoint records how far a long-running command got, so that --resume
+// can continue after Ctrl-C or a crash instead of starting over. Commands
+// using it process documents in ID order and save after every page, keeping
+// the documents that failed so --resume retries them.
+type checkpoint struct {
+	store *cache.Dir
+	key   string
+
+	// LastID is the highest document ID that was fully processed
+	LastID int `json:"last_id"`
+	// Failed lists documents up to LastID that failed and are to be retried
+	Failed []int `json:"failed,omitempty"`
+	// Done counts the documents processed so far, over all runs
+	Done    int       `json:"done"`
+	Updated time.Time `json:"updated"`
+}
+
+// openCheckpoint returns the checkpoint of a command run. Runs with the same
+// command, profile and options share one; with resume, its saved progress
+// is loaded, otherwise it starts empty.
+func openCheckpoint(command string, options []string, resume bool) *checkpoint {
+	sum := sha256.Sum256([]byte(config.ActiveProfile() + "\x00" + strings.Join(options, "\x00")))
+	cp := &checkpoint{key: command + "-" + hex.EncodeToString(sum[:8])}
+
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return cp
+	}
+	cp.store = cache.New(filepath.Join(dir, "state"))
+
+	if resume {
+		if data, ok := cp.store.Get(cp.key); ok {
+			json.Unmarshal(data, cp)
+		}
+	}
+	return cp
+}
+
+// resumed reports whether saved progress was loaded
+func (cp *checkpoint) resumed() bool {
+	return cp.LastID > 0
+}
+
+// retry returns the documents that failed before. They stay recorded until
+// succeeded is called for them, so an interrupted retry isn't lost.
+func (cp *checkpoint) retry() []int {
+	return slices.Clone(cp.Failed)
+}
+
+// fail records that a document failed, so the next --resume retries it
+func (cp *checkpoint) fail(id int) {
+	if !slices.Contains(cp.Failed, id) {
+		cp.Failed = append(cp.Failed, id)
+	}
+}
+
+// succeeded forgets that a document failed once it was processed
+func (cp *checkpoint) succeeded(id int) {
+	cp.Failed = slices.DeleteFunc(cp.Failed, func(failed int) bool { return failed == id })
+}
+
+// save records that all documents up to lastID are processed, apart from
+// the failed ones
+func (cp *checkpoint) save(lastID, processed int) error {
+	cp.LastID = lastID
+	cp.Done += processed
+	cp.Updated = time.Now()
+	if cp.store == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	return cp.store.Put(cp.key, data)
+}
+
+// clear removes the saved progress once the run completed without failures
+func (cp *checkpoint) clear() error {
+	if cp.store == nil {
+		return nil
+	}
+	return cp.store.Delete(cp.key)
+}
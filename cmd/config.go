@@ -2,7 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
 
+	"github.com/julianfbeck/paperless-cli/internal/api"
 	"github.com/julianfbeck/paperless-cli/internal/config"
 	"github.com/spf13/cobra"
 )
@@ -35,6 +39,34 @@ Example:
 	RunE: runConfigSetToken,
 }
 
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a configuration value",
+	Long: `Set a configuration value by key.
+
+Keys:
+  url          Paperless server URL
+  token        API token
+  ca-file      PEM file with additional trusted CA certificates
+  fingerprint  SHA-256 fingerprint of a pinned server certificate
+  insecure     skip TLS certificate verification (true/false)
+  client-cert  PEM client certificate for mutual TLS
+  client-key   PEM private key for the client certificate
+  cache-ttl    how long cached tags/correspondents/types are used (e.g. 10m, 0)
+  filter       comma separated filters ANDed into every document query:
+               owner:me, tag:NAME, correspondent:NAME, type:NAME,
+               storage_path:NAME (empty to remove)
+  hold         name of the tag and boolean custom field putting documents
+               on legal hold (default legal-hold)
+
+Example:
+  paperless config set ca-file ~/certs/home-ca.pem
+  paperless config set fingerprint AB:CD:...:EF
+  paperless --profile work config set filter "owner:me,tag:business"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runConfigSet,
+}
+
 var configShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show current configuration",
@@ -45,11 +77,76 @@ Example:
 	RunE: runConfigShow,
 }
 
+var configImplicationsCmd = &cobra.Command{
+	Use:   "implications",
+	Short: "List tag implication rules",
+	Long: `List the tag implication rules. A rule like "tag:insurance implies
+tag:finance" makes uploads and "paperless autotag --implications" add the
+finance tag to every document tagged insurance.
+
+Example:
+  paperless config implications
+  paperless config implications add "tag:insurance implies tag:finance"
+  paperless config implications remove "tag:insurance implies tag:finance"`,
+	Args: cobra.NoArgs,
+	RunE: runConfigImplications,
+}
+
+var configImplicationsAddCmd = &cobra.Command{
+	Use:   "add <rule>",
+	Short: "Add a tag implication rule",
+	Long: `Add a rule of the form "tag:A implies tag:B".
+
+Example:
+  paperless config implications add "tag:insurance implies tag:finance"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigImplicationsAdd,
+}
+
+var configImplicationsRemoveCmd = &cobra.Command{
+	Use:   "remove <rule>",
+	Short: "Remove a tag implication rule",
+	Long: `Remove a tag implication rule.
+
+Example:
+  paperless config implications remove "tag:insurance implies tag:finance"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigImplicationsRemove,
+}
+
+var configProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "List configured profiles",
+	Long: `List the configured Paperless instances. The top-level settings are the
+"default" profile; others are created by saving settings with --profile.
+
+Example:
+  paperless --profile business config set-url https://paperless.example.com
+  paperless --profile business config set-token abc123def456
+  paperless config profiles
+  paperless config profiles remove business`,
+	Args: cobra.NoArgs,
+	RunE: runConfigProfiles,
+}
+
+var configProfilesRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigProfilesRemove,
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configSetURLCmd)
 	configCmd.AddCommand(configSetTokenCmd)
+	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configImplicationsCmd)
+	configImplicationsCmd.AddCommand(configImplicationsAddCmd)
+	configImplicationsCmd.AddCommand(configImplicationsRemoveCmd)
+	configCmd.AddCommand(configProfilesCmd)
+	configProfilesCmd.AddCommand(configProfilesRemoveCmd)
 }
 
 func runConfigSetURL(cmd *cobra.Command, args []string) error {
@@ -76,21 +173,89 @@ func runConfigSetToken(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	if args[0] == "fingerprint" {
+		if _, err := api.ParseFingerprint(args[1]); err != nil {
+			return err
+		}
+	}
+
+	if err := config.Set(args[0], args[1]); err != nil {
+		return err
+	}
+
+	if !isQuiet() {
+		if args[0] == "token" {
+			fmt.Println("Token saved")
+		} else {
+			fmt.Printf("%s set to: %s\n", args[0], args[1])
+		}
+	}
+
+	return nil
+}
+
 func runConfigShow(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
+	loaded, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	cfg := loaded.Current()
+	ws, err := currentWorkspace()
+	if err != nil {
+		return err
+	}
 
 	if isJSON() {
-		return printJSON(map[string]string{
-			"url":   cfg.URL,
-			"token": maskToken(cfg.Token),
+		return printJSON(map[string]interface{}{
+			"profile":     config.ActiveProfile(),
+			"url":         cfg.URL,
+			"token":       maskToken(cfg.Token),
+			"ca_file":     cfg.CAFile,
+			"fingerprint": cfg.Fingerprint,
+			"insecure":    cfg.Insecure,
+			"client_cert": cfg.ClientCert,
+			"client_key":  cfg.ClientKey,
+			"filter":      cfg.Filter,
+			"cache_ttl":   config.GetCacheTTL().String(),
+			"hold":        config.GetHold(),
+			"workspace":   ws,
 		})
 	}
 
+	if name := config.ActiveProfile(); name != config.DefaultProfile {
+		fmt.Printf("Profile: %s\n", name)
+	}
 	fmt.Printf("URL:   %s\n", cfg.URL)
 	fmt.Printf("Token: %s\n", maskToken(cfg.Token))
+	if cfg.CAFile != "" {
+		fmt.Printf("CA:    %s\n", cfg.CAFile)
+	}
+	if cfg.Fingerprint != "" {
+		fmt.Printf("Pin:   %s\n", cfg.Fingerprint)
+	}
+	if cfg.Insecure {
+		fmt.Println("TLS:   verification disabled (insecure)")
+	}
+	if cfg.ClientCert != "" {
+		fmt.Printf("Cert:  %s (key: %s)\n", cfg.ClientCert, cfg.ClientKey)
+	}
+
+	if len(cfg.Filter) > 0 {
+		fmt.Printf("Filter: %s\n", strings.Join(cfg.Filter, ", "))
+	}
+
+	if cfg.Hold != "" {
+		fmt.Printf("Hold:  %s\n", cfg.Hold)
+	}
+
+	if loaded.CacheTTL != "" {
+		fmt.Printf("Cache: %s\n", loaded.CacheTTL)
+	}
+
+	if ws != nil {
+		fmt.Printf("Workspace: %s\n", ws.Path)
+	}
 
 	// Show env overrides
 	if envURL := config.GetURL(); envURL != cfg.URL && envURL != "" {
@@ -103,6 +268,110 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runConfigImplications(cmd *cobra.Command, args []string) error {
+	rules, err := config.GetImplications()
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		out := make([]string, len(rules))
+		for i, rule := range rules {
+			out[i] = rule.String()
+		}
+		return printJSON(out)
+	}
+
+	if len(rules) == 0 {
+		fmt.Println("No implications configured")
+		return nil
+	}
+	for _, rule := range rules {
+		fmt.Println(rule)
+	}
+
+	return nil
+}
+
+func runConfigImplicationsAdd(cmd *cobra.Command, args []string) error {
+	rule, err := config.AddImplication(args[0])
+	if err != nil {
+		return err
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Added: %s\n", rule)
+	}
+
+	return nil
+}
+
+func runConfigImplicationsRemove(cmd *cobra.Command, args []string) error {
+	if err := config.RemoveImplication(args[0]); err != nil {
+		return err
+	}
+
+	if !isQuiet() {
+		fmt.Println("Removed implication")
+	}
+
+	return nil
+}
+
+func runConfigProfiles(cmd *cobra.Command, args []string) error {
+	names, err := config.ProfileNames()
+	if err != nil {
+		return err
+	}
+
+	type profileInfo struct {
+		Name   string `json:"name"`
+		URL    string `json:"url"`
+		Active bool   `json:"active"`
+	}
+	profiles := make([]profileInfo, 0, len(names))
+	for _, name := range names {
+		settings, err := config.Settings(name)
+		if err != nil {
+			return err
+		}
+		profiles = append(profiles, profileInfo{Name: name, URL: settings.URL, Active: name == config.ActiveProfile()})
+	}
+
+	if isJSON() {
+		return printJSON(profiles)
+	}
+
+	if len(profiles) == 0 {
+		fmt.Println("No profiles configured")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tNAME\tURL")
+	for _, p := range profiles {
+		marker := ""
+		if p.Active {
+			marker = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", marker, p.Name, p.URL)
+	}
+	w.Flush()
+
+	return nil
+}
+
+func runConfigProfilesRemove(cmd *cobra.Command, args []string) error {
+	if err := config.RemoveProfile(args[0]); err != nil {
+		return err
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Removed profile %s\n", args[0])
+	}
+
+	return nil
+}
+
 func maskToken(token string) string {
 	if token == "" {
 		return "(not set)"
@@ -2,10 +2,11 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"maps"
 	"strconv"
-	"text/tabwriter"
+	"strings"
 
+	"github.com/julianfbeck/paperless-cli/internal/api"
 	"github.com/spf13/cobra"
 )
 
@@ -41,10 +42,12 @@ Example:
 var corrCreateCmd = &cobra.Command{
 	Use:   "create <name>",
 	Short: "Create a new correspondent",
-	Long: `Create a new correspondent.
+	Long: `Create a new correspondent. With --match and --matching-algorithm, new
+documents matching the text are assigned to it automatically.
 
 Example:
-  paperless correspondents create "ACME Corp"`,
+  paperless correspondents create "ACME Corp"
+  paperless correspondents create "ACME Corp" --match "ACME" --matching-algorithm literal`,
 	Args: cobra.ExactArgs(1),
 	RunE: runCorrCreate,
 }
@@ -55,7 +58,8 @@ var corrEditCmd = &cobra.Command{
 	Long: `Edit a correspondent's properties.
 
 Example:
-  paperless correspondents edit 5 --name "New Name"`,
+  paperless correspondents edit 5 --name "New Name"
+  paperless correspondents edit 5 --matching-algorithm auto`,
 	Args: cobra.ExactArgs(1),
 	RunE: runCorrEdit,
 }
@@ -73,8 +77,12 @@ Example:
 }
 
 var (
-	corrName  string
-	corrForce bool
+	corrName      string
+	corrForce     bool
+	corrOwnership ownershipFlags
+	corrMatching  matchingFlags
+
+	corrListName string
 )
 
 func init() {
@@ -85,17 +93,40 @@ func init() {
 	correspondentsCmd.AddCommand(corrEditCmd)
 	correspondentsCmd.AddCommand(corrDeleteCmd)
 
+	corrListCmd.Flags().StringVar(&corrListName, "name", "", "only correspondents whose name contains this text")
+	addTableFlags(corrListCmd, corrColumns)
 	corrEditCmd.Flags().StringVar(&corrName, "name", "", "new name")
+	corrMatching.register(corrCreateCmd)
+	corrMatching.register(corrEditCmd)
+	corrOwnership.register(corrCreateCmd)
+	corrOwnership.register(corrEditCmd)
 	corrDeleteCmd.Flags().BoolVarP(&corrForce, "force", "f", false, "skip confirmation")
 }
 
+// corrColumns are the columns of the correspondents list
+var corrColumns = []tableColumn[api.Correspondent]{
+	{name: "id", value: func(c api.Correspondent) string { return strconv.Itoa(c.ID) }},
+	{name: "name", value: func(c api.Correspondent) string { return c.Name }},
+	{name: "docs", value: func(c api.Correspondent) string { return strconv.Itoa(c.DocumentCount) }},
+	{name: "match", value: func(c api.Correspondent) string { return fit(c.Match, 30) }},
+	{name: "last", value: func(c api.Correspondent) string {
+		date, _, _ := strings.Cut(c.LastCorrespond, "T")
+		return date
+	}},
+}
+
 func runCorrList(cmd *cobra.Command, args []string) error {
 	client, err := getClient()
 	if err != nil {
 		return err
 	}
 
-	result, err := client.ListCorrespondents()
+	columns, err := selectColumns(corrColumns, "id,name,docs")
+	if err != nil {
+		return err
+	}
+
+	result, err := client.ListCorrespondents(api.ListParams{NameContains: corrListName})
 	if err != nil {
 		return err
 	}
@@ -109,12 +140,7 @@ func runCorrList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tNAME\tDOCS")
-	for _, corr := range result.Results {
-		fmt.Fprintf(w, "%d\t%s\t%d\n", corr.ID, corr.Name, corr.DocumentCount)
-	}
-	w.Flush()
+	printTable(columns, result.Results)
 
 	return nil
 }
@@ -142,6 +168,7 @@ func runCorrGet(cmd *cobra.Command, args []string) error {
 	fmt.Printf("ID:        %d\n", corr.ID)
 	fmt.Printf("Name:      %s\n", corr.Name)
 	fmt.Printf("Slug:      %s\n", corr.Slug)
+	fmt.Printf("Matching:  %s\n", matchingLabel(corr.MatchingAlgo, corr.Match, corr.IsInsensitive))
 	fmt.Printf("Documents: %d\n", corr.DocumentCount)
 
 	return nil
@@ -153,10 +180,24 @@ func runCorrCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	corr, err := client.CreateCorrespondent(args[0])
+	fields, err := corrMatching.updates(cmd)
+	if err != nil {
+		return err
+	}
+	owner, err := corrOwnership.resolve(client)
+	if err != nil {
+		return err
+	}
+
+	corr, err := client.CreateCorrespondent(args[0], fields)
 	if err != nil {
 		return err
 	}
+	if updates := owner.updates(nil, false); len(updates) > 0 {
+		if corr, err = client.UpdateCorrespondent(corr.ID, updates); err != nil {
+			return err
+		}
+	}
 
 	if isJSON() {
 		return printJSON(corr)
@@ -180,11 +221,28 @@ func runCorrEdit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid correspondent ID: %s", args[0])
 	}
 
-	updates := make(map[string]interface{})
+	updates, err := corrMatching.updates(cmd)
+	if err != nil {
+		return err
+	}
 	if corrName != "" {
 		updates["name"] = corrName
 	}
 
+	owner, err := corrOwnership.resolve(client)
+	if err != nil {
+		return err
+	}
+	var current *api.ObjectPermissions
+	if owner.shares != nil {
+		obj, err := client.GetCorrespondent(id)
+		if err != nil {
+			return err
+		}
+		current = obj.Permissions
+	}
+	maps.Copy(updates, owner.updates(current, false))
+
 	if len(updates) == 0 {
 		return fmt.Errorf("no changes specified")
 	}
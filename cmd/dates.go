@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateFilterHelp lists the forms parseDateFilter accepts, for flag errors
+const dateFilterHelp = "use YYYY-MM-DD, today, yesterday, 30d, 6m, \"2 weeks ago\", last month, this year, 2024, 2024-06 or 2024-Q1"
+
+var (
+	relativeDateRe = regexp.MustCompile(`^(\d+)\s*(d|days?|w|weeks?|m|months?|y|years?)(\s+ago)?$`)
+	periodRe       = regexp.MustCompile(`^(this|last)\s+(week|month|quarter|year)$`)
+	quarterRe      = regexp.MustCompile(`^(\d{4})-?q([1-4])$`)
+	monthRe        = regexp.MustCompile(`^(\d{4})-(\d{2})$`)
+	yearRe         = regexp.MustCompile(`^\d{4}$`)
+)
+
+// parseDateFilter turns the value of a --*-after or --*-before flag into a
+// YYYY-MM-DD date for the API, whose date filters exclude the date itself.
+//
+// Days, like "2024-06-01", "yesterday" or "30d" (30 days ago), are used as
+// they are. Periods, like "last month", "2024" or "2024-Q1", are included:
+// for --*-after the date is the day before the period, for --*-before
+// (before is true) the day after it. Giving a period to both flags selects
+// exactly that period.
+func parseDateFilter(value string, before bool) (string, error) {
+	y, m, d := time.Now().Date()
+	return parseDateFilterOn(value, before, time.Date(y, m, d, 0, 0, 0, 0, time.Local))
+}
+
+// parseDateFilterOn is parseDateFilter with relative dates counted from
+// today
+func parseDateFilterOn(value string, before bool, today time.Time) (string, error) {
+	s := strings.ToLower(strings.TrimSpace(value))
+	if s == "" {
+		return "", nil
+	}
+	if _, err := time.Parse("2006-01-02", s); err == nil {
+		return s, nil
+	}
+
+	switch s {
+	case "today":
+		return today.Format("2006-01-02"), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1).Format("2006-01-02"), nil
+	}
+
+	if match := relativeDateRe.FindStringSubmatch(s); match != nil {
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			return "", fmt.Errorf("invalid date: %s (%s)", value, dateFilterHelp)
+		}
+		var day time.Time
+		switch match[2][0] {
+		case 'd':
+			day = today.AddDate(0, 0, -n)
+		case 'w':
+			day = today.AddDate(0, 0, -7*n)
+		case 'm':
+			day = today.AddDate(0, -n, 0)
+		case 'y':
+			day = today.AddDate(-n, 0, 0)
+		}
+		return day.Format("2006-01-02"), nil
+	}
+
+	start, end, ok := parsePeriod(s, today)
+	if !ok {
+		return "", fmt.Errorf("invalid date: %s (%s)", value, dateFilterHelp)
+	}
+	if before {
+		return end.Format("2006-01-02"), nil
+	}
+	return start.AddDate(0, 0, -1).Format("2006-01-02"), nil
+}
+
+// parsePeriod returns the first day of a period and the day after its last
+func parsePeriod(s string, today time.Time) (start, end time.Time, ok bool) {
+	if match := periodRe.FindStringSubmatch(s); match != nil {
+		var months int
+		switch match[2] {
+		case "week":
+			// Weeks start on Monday
+			offset := (int(today.Weekday()) + 6) % 7
+			start = today.AddDate(0, 0, -offset)
+			if match[1] == "last" {
+				start = start.AddDate(0, 0, -7)
+			}
+			return start, start.AddDate(0, 0, 7), true
+		case "month":
+			months = 1
+			start = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.Local)
+		case "quarter":
+			months = 3
+			start = time.Date(today.Year(), (today.Month()-1)/3*3+1, 1, 0, 0, 0, 0, time.Local)
+		case "year":
+			months = 12
+			start = time.Date(today.Year(), 1, 1, 0, 0, 0, 0, time.Local)
+		}
+		if match[1] == "last" {
+			start = start.AddDate(0, -months, 0)
+		}
+		return start, start.AddDate(0, months, 0), true
+	}
+
+	if match := quarterRe.FindStringSubmatch(s); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		quarter, _ := strconv.Atoi(match[2])
+		start = time.Date(year, time.Month(quarter*3-2), 1, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(0, 3, 0), true
+	}
+	if match := monthRe.FindStringSubmatch(s); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		month, _ := strconv.Atoi(match[2])
+		if month < 1 || month > 12 {
+			return start, end, false
+		}
+		start = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(0, 1, 0), true
+	}
+	if yearRe.MatchString(s) {
+		year, _ := strconv.Atoi(s)
+		start = time.Date(year, 1, 1, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(1, 0, 0), true
+	}
+	return start, end, false
+}
+
+// resolveDateFilters parses pairs of --*-after and --*-before values in
+// place
+func resolveDateFilters(after, before *string) error {
+	var err error
+	if *after, err = parseDateFilter(*after, false); err != nil {
+		return err
+	}
+	*before, err = parseDateFilter(*before, true)
+	return err
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseDateFilter(t *testing.T) {
+	// A Wednesday
+	today := day("2024-05-15")
+
+	tests := []struct {
+		in      string
+		before  bool
+		want    string
+		wantErr bool
+	}{
+		{in: "", want: ""},
+		{in: "2024-06-01", want: "2024-06-01"},
+		{in: "2024-06-01", before: true, want: "2024-06-01"},
+
+		// Days
+		{in: "today", want: "2024-05-15"},
+		{in: " Today ", before: true, want: "2024-05-15"},
+		{in: "yesterday", want: "2024-05-14"},
+		{in: "30d", want: "2024-04-15"},
+		{in: "3 days ago", want: "2024-05-12"},
+		{in: "2 weeks ago", want: "2024-05-01"},
+		{in: "1w", before: true, want: "2024-05-08"},
+		{in: "6m", want: "2023-11-15"},
+		{in: "1 year", want: "2023-05-15"},
+
+		// Periods: after gives the day before, before the day after
+		{in: "this week", want: "2024-05-12"},
+		{in: "this week", before: true, want: "2024-05-20"},
+		{in: "last week", want: "2024-05-05"},
+		{in: "last week", before: true, want: "2024-05-13"},
+		{in: "this month", want: "2024-04-30"},
+		{in: "this month", before: true, want: "2024-06-01"},
+		{in: "last month", want: "2024-03-31"},
+		{in: "last month", before: true, want: "2024-05-01"},
+		{in: "this quarter", want: "2024-03-31"},
+		{in: "this quarter", before: true, want: "2024-07-01"},
+		{in: "last quarter", want: "2023-12-31"},
+		{in: "last quarter", before: true, want: "2024-04-01"},
+		{in: "this year", want: "2023-12-31"},
+		{in: "last year", before: true, want: "2024-01-01"},
+		{in: "2023", want: "2022-12-31"},
+		{in: "2023", before: true, want: "2024-01-01"},
+		{in: "2024-02", want: "2024-01-31"},
+		{in: "2024-02", before: true, want: "2024-03-01"},
+		{in: "2024-Q1", want: "2023-12-31"},
+		{in: "2023q4", before: true, want: "2024-01-01"},
+
+		// Invalid
+		{in: "tomorrow", wantErr: true},
+		{in: "next month", wantErr: true},
+		{in: "2024-13", wantErr: true},
+		{in: "2024-Q5", wantErr: true},
+		{in: "2024-02-30", wantErr: true},
+		{in: "d", wantErr: true},
+		{in: "-3d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDateFilterOn(tt.in, tt.before, today)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseDateFilter(%q, %v) = %q, want an error", tt.in, tt.before, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseDateFilter(%q, %v): %v", tt.in, tt.before, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseDateFilter(%q, %v) = %q, want %q", tt.in, tt.before, got, tt.want)
+		}
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		in         string
+		today      string
+		start, end string
+		ok         bool
+	}{
+		// Weeks start on Monday
+		{in: "this week", today: "2024-05-13", start: "2024-05-13", end: "2024-05-20", ok: true},
+		{in: "this week", today: "2024-05-19", start: "2024-05-13", end: "2024-05-20", ok: true},
+		{in: "last week", today: "2024-01-03", start: "2023-12-25", end: "2024-01-01", ok: true},
+
+		// Across the turn of the year
+		{in: "last month", today: "2024-01-10", start: "2023-12-01", end: "2024-01-01", ok: true},
+		{in: "last quarter", today: "2024-02-29", start: "2023-10-01", end: "2024-01-01", ok: true},
+		{in: "this quarter", today: "2024-12-31", start: "2024-10-01", end: "2025-01-01", ok: true},
+		{in: "last year", today: "2024-01-01", start: "2023-01-01", end: "2024-01-01", ok: true},
+
+		// Absolute periods don't depend on today
+		{in: "2024-q4", today: "2020-06-01", start: "2024-10-01", end: "2025-01-01", ok: true},
+		{in: "2024-12", today: "2020-06-01", start: "2024-12-01", end: "2025-01-01", ok: true},
+		{in: "1999", today: "2020-06-01", start: "1999-01-01", end: "2000-01-01", ok: true},
+
+		{in: "2024-00", today: "2024-05-15"},
+		{in: "next year", today: "2024-05-15"},
+		{in: "30d", today: "2024-05-15"},
+	}
+
+	for _, tt := range tests {
+		start, end, ok := parsePeriod(tt.in, day(tt.today))
+		if ok != tt.ok {
+			t.Errorf("parsePeriod(%q) on %s: ok = %v, want %v", tt.in, tt.today, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if got := start.Format("2006-01-02"); got != tt.start {
+			t.Errorf("parsePeriod(%q) on %s: start = %s, want %s", tt.in, tt.today, got, tt.start)
+		}
+		if got := end.Format("2006-01-02"); got != tt.end {
+			t.Errorf("parsePeriod(%q) on %s: end = %s, want %s", tt.in, tt.today, got, tt.end)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var docsDedupeTitlesCmd = &cobra.Command{
+	Use:   "dedupe-titles",
+	Short: "Report documents with the same title and correspondent",
+	Long: `List groups of documents that share the same normalized title and
+correspondent but have different file checksums. These are often double
+scans or several versions of one document that may need merging.
+
+Titles are compared ignoring case, punctuation, extra whitespace and
+suffixes like "(2)" or "copy". Checksums are only fetched for documents
+whose titles collide.
+
+Example:
+  paperless documents dedupe-titles
+  paperless documents dedupe-titles --correspondent ACME --json`,
+	Args: cobra.NoArgs,
+	RunE: runDocsDedupeTitles,
+}
+
+var (
+	dedupeQuery         string
+	dedupeTags          []string
+	dedupeCorrespondent string
+	dedupeDocType       string
+)
+
+func init() {
+	documentsCmd.AddCommand(docsDedupeTitlesCmd)
+
+	docsDedupeTitlesCmd.Flags().StringVar(&dedupeQuery, "query", "", "search query")
+	docsDedupeTitlesCmd.RegisterFlagCompletionFunc("query", completeQuery)
+	docsDedupeTitlesCmd.Flags().StringArrayVar(&dedupeTags, "tag", nil, "filter by tag (repeatable)")
+	docsDedupeTitlesCmd.Flags().StringVar(&dedupeCorrespondent, "correspondent", "", "filter by correspondent")
+	docsDedupeTitlesCmd.Flags().StringVar(&dedupeDocType, "type", "", "filter by document type")
+}
+
+// titleCopySuffix matches markers that file managers and scanners append
+// to repeated names
+var titleCopySuffix = lazyRegexp(`(\s*\(\d+\)|\s+(copy|kopie)(\s*\d+)?)+$`)
+
+// normalizeTitle reduces a title to lower case words for comparison
+func normalizeTitle(title string) string {
+	title = titleCopySuffix().ReplaceAllString(strings.ToLower(strings.TrimSpace(title)), "")
+	return strings.Join(strings.FieldsFunc(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+type dedupeDocument struct {
+	ID       int    `json:"id"`
+	Title    string `json:"title"`
+	Created  string `json:"created"`
+	Checksum string `json:"checksum"`
+}
+
+type dedupeGroup struct {
+	Title         string           `json:"title"`
+	Correspondent string           `json:"correspondent,omitempty"`
+	Documents     []dedupeDocument `json:"documents"`
+}
+
+func runDocsDedupeTitles(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	params := api.DocumentListParams{
+		Query:         dedupeQuery,
+		Tags:          dedupeTags,
+		Correspondent: dedupeCorrespondent,
+		DocumentType:  dedupeDocType,
+		Limit:         100,
+		Ordering:      "id",
+	}
+
+	type groupKey struct {
+		title         string
+		correspondent int
+	}
+	groups := make(map[groupKey][]api.Document)
+	for page := 1; ; page++ {
+		params.Page = page
+		result, err := client.ListDocuments(params)
+		if err != nil {
+			return err
+		}
+		for _, doc := range result.Results {
+			key := groupKey{title: normalizeTitle(doc.Title)}
+			if doc.Correspondent != nil {
+				key.correspondent = *doc.Correspondent
+			}
+			groups[key] = append(groups[key], doc)
+		}
+		if result.Next == "" {
+			break
+		}
+	}
+
+	// Only colliding titles need their checksums
+	var candidates []api.Document
+	for _, docs := range groups {
+		if len(docs) > 1 {
+			candidates = append(candidates, docs...)
+		}
+	}
+
+	checksums := make([]string, len(candidates))
+	errs := forEachParallel(candidates, maxPar, func(i int, doc api.Document) error {
+		meta, err := client.GetDocumentMetadata(doc.ID)
+		if err != nil {
+			return err
+		}
+		checksums[i] = meta.OriginalChecksum
+		return nil
+	})
+	checksumOf := make(map[int]string, len(candidates))
+	failed := 0
+	for i, doc := range candidates {
+		if errs[i] != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "document %d: %v\n", doc.ID, errs[i])
+			continue
+		}
+		checksumOf[doc.ID] = checksums[i]
+	}
+
+	names := make(map[int]string)
+	if len(candidates) > 0 {
+		corrs, err := client.ListCorrespondents(api.ListParams{})
+		if err != nil {
+			return err
+		}
+		for _, c := range corrs.Results {
+			names[c.ID] = c.Name
+		}
+	}
+
+	var report []dedupeGroup
+	for key, docs := range groups {
+		distinct := make(map[string]bool)
+		for _, doc := range docs {
+			if sum, ok := checksumOf[doc.ID]; ok {
+				distinct[sum] = true
+			}
+		}
+		if len(distinct) < 2 {
+			continue
+		}
+
+		group := dedupeGroup{Title: docs[0].Title, Correspondent: names[key.correspondent]}
+		for _, doc := range docs {
+			group.Documents = append(group.Documents, dedupeDocument{
+				ID:       doc.ID,
+				Title:    doc.Title,
+				Created:  doc.CreatedDate,
+				Checksum: checksumOf[doc.ID],
+			})
+		}
+		report = append(report, group)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Documents[0].ID < report[j].Documents[0].ID
+	})
+
+	if isJSON() {
+		if report == nil {
+			report = []dedupeGroup{}
+		}
+		if err := printJSON(report); err != nil {
+			return err
+		}
+	} else if len(report) == 0 {
+		fmt.Println("No documents with matching titles found")
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "GROUP\tID\tTITLE\tCORRESPONDENT\tCREATED\tCHECKSUM")
+		for i, group := range report {
+			for _, doc := range group.Documents {
+				fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\n", i+1, doc.ID, truncate(doc.Title, 50), group.Correspondent, doc.Created, doc.Checksum)
+			}
+		}
+		w.Flush()
+
+		if !isQuiet() {
+			fmt.Fprintf(os.Stderr, "\n%d group(s) of possibly duplicated documents\n", len(report))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to read metadata for %d document(s)", failed)
+	}
+	return nil
+}
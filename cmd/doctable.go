@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+)
+
+// defaultDocumentColumns are the columns of document tables without
+// --columns
+const defaultDocumentColumns = "id,title,created,tags"
+
+// documentColumns are the columns of document tables. Tags, correspondents,
+// types and storage paths are shown by name, or by ID if names is nil.
+func documentColumns(names *metadataNames) []tableColumn[api.Document] {
+	ref := func(id *int, name func(int) string) string {
+		if id == nil {
+			return ""
+		}
+		return name(*id)
+	}
+	number := func(n *int) string {
+		if n == nil {
+			return ""
+		}
+		return strconv.Itoa(*n)
+	}
+
+	return []tableColumn[api.Document]{
+		{name: "id", width: 6, value: func(d api.Document) string { return strconv.Itoa(d.ID) }},
+		{name: "title", width: 40, value: func(d api.Document) string { return fit(d.Title, 40) }},
+		{name: "created", width: 10, value: func(d api.Document) string { return d.CreatedDate }},
+		{name: "added", width: 10, value: func(d api.Document) string { return d.Added.Format("2006-01-02") }},
+		{name: "modified", width: 10, value: func(d api.Document) string { return d.Modified.Format("2006-01-02") }},
+		{name: "correspondent", width: 20, value: func(d api.Document) string { return fit(ref(d.Correspondent, names.correspondent), 20) }},
+		{name: "type", width: 20, value: func(d api.Document) string { return fit(ref(d.DocumentType, names.documentType), 20) }},
+		{name: "storage-path", width: 20, value: func(d api.Document) string { return fit(ref(d.StoragePath, names.storagePath), 20) }},
+		{name: "tags", width: 30, value: func(d api.Document) string { return names.tagList(d.Tags) }},
+		{name: "asn", width: 6, value: func(d api.Document) string { return number(d.ArchiveSerialNumber) }},
+		{name: "pages", width: 5, value: func(d api.Document) string { return number(d.PageCount) }},
+		{name: "original", width: 30, value: func(d api.Document) string { return fit(d.OriginalFileName, 30) }},
+		{name: "score", width: 6, value: func(d api.Document) string {
+			if d.SearchHit == nil {
+				return ""
+			}
+			return strconv.FormatFloat(d.SearchHit.Score, 'f', 2, 64)
+		}},
+	}
+}
+
+// documentColumnFields are the document fields each column shows
+var documentColumnFields = map[string][]string{
+	"id":            {"id"},
+	"title":         {"title"},
+	"created":       {"created", "created_date"},
+	"added":         {"added"},
+	"modified":      {"modified"},
+	"correspondent": {"correspondent"},
+	"type":          {"document_type"},
+	"storage-path":  {"storage_path"},
+	"tags":          {"tags"},
+	"asn":           {"archive_serial_number"},
+	"pages":         {"page_count"},
+	"original":      {"original_file_name"},
+	// Search results always come with their hit
+	"score": nil,
+}
+
+// selectDocumentColumns picks the columns of a document table and makes
+// tables fetch only the fields they show
+func selectDocumentColumns(names *metadataNames, defaults string) ([]tableColumn[api.Document], error) {
+	columns, err := selectColumns(documentColumns(names), defaults)
+	if err != nil {
+		return nil, err
+	}
+	tableFields = []string{"id"}
+	for _, col := range columns {
+		for _, field := range documentColumnFields[col.name] {
+			if !slices.Contains(tableFields, field) {
+				tableFields = append(tableFields, field)
+			}
+		}
+	}
+	return columns, nil
+}
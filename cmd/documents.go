@@ -1,12 +1,20 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/julianfbeck/paperless-cli/internal/api"
 	"github.com/spf13/cobra"
@@ -24,10 +32,36 @@ var docsListCmd = &cobra.Command{
 	Short: "List documents",
 	Long: `List documents with optional filters.
 
+Newest documents come first; --sort orders them by created, added, modified,
+title, asn or correspondent instead, descending with a "-" prefix or
+--reverse.
+
+Date filters take YYYY-MM-DD, today, yesterday, days, weeks, months or years
+ago (30d, 2w, 6m, "1 year ago"), or a period: this/last week, month, quarter
+or year, 2024, 2024-06 or 2024-Q1. A period counts as a whole, so giving it
+to both --created-after and --created-before selects exactly that period.
+
+One page of --limit documents is shown, followed by the --page to continue
+with. --all lists every matching document instead, printing each page as it
+arrives; with --json as one array. --ids prints only the IDs, one per
+line, to pipe into other commands.
+
 Example:
   paperless documents list
   paperless documents list --query "invoice"
-  paperless documents list --tag bills --limit 10`,
+  paperless documents list --tag bills --limit 10
+  paperless documents list --tag tax --all
+  paperless documents list --sort title
+  paperless documents list --sort asn --reverse
+  paperless documents list --asn-from 100 --asn-to 199
+  paperless documents list --asn 1042
+  paperless documents list --added-after 2024-06-01 --owner me
+  paperless documents list --created-after 30d
+  paperless documents list --created-after 2024-Q1 --created-before 2024-Q1
+  paperless documents list --untagged --no-correspondent
+  paperless documents list --columns id,title,correspondent,tags,asn,added --wide
+  paperless documents list --tag old --all --ids | xargs paperless documents delete --force
+  paperless documents list --json --fields id,title,tags --auto`,
 	RunE: runDocsList,
 }
 
@@ -36,72 +70,191 @@ var docsSearchCmd = &cobra.Command{
 	Short: "Search documents",
 	Long: `Full-text search across all documents.
 
+Each hit is shown with its relevance score and the passages of its content
+and notes that match. With --json the passages are plain text, without the
+HTML marking the matches.
+
 Example:
   paperless documents search "invoice 2024"
-  paperless documents search "contract" --limit 5`,
+  paperless documents search "contract" --limit 5
+  paperless documents search "contract" --json | jq '.results[].__search_hit__.highlights'`,
 	Args: cobra.ExactArgs(1),
 	RunE: runDocsSearch,
 }
 
 var docsGetCmd = &cobra.Command{
-	Use:   "get <id>",
+	Use:   "get <id>...",
 	Short: "Get document details",
-	Long: `Get detailed information about a document.
+	Long: `Get detailed information about documents. IDs can be given as ranges
+like 100-120 and comma-separated lists; documents of a range that don't
+exist are skipped with a warning.
+
+Tags, correspondent and document type are shown by name; --raw shows their
+IDs.
 
 Example:
-  paperless documents get 123`,
-	Args: cobra.ExactArgs(1),
+  paperless documents get 123
+  paperless documents get 123 --raw
+  paperless documents get 100-105,110 --json`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: runDocsGet,
 }
 
 var docsUploadCmd = &cobra.Command{
-	Use:   "upload <file>...",
+	Use:   "upload <file|->...",
 	Short: "Upload document(s)",
 	Long: `Upload one or more documents to Paperless.
 
+Tags implied by the configured implication rules are added automatically
+(see "paperless config implications").
+
+Inside a project directory, the nearest .paperless.yaml provides defaults:
+its tags are added, and its correspondent, document_type, storage_path and
+title template apply unless given as flags. Titles and --title-sequence
+replace {name} with the file name, {n} with its position and {date} with
+today's date.
+
+With --interactive, a text preview of each file is shown and you are asked
+for its title, correspondent, type and tags. Names are matched fuzzily
+against the existing ones; the other flags provide the defaults.
+
+Several files are uploaded at once, up to --concurrency (backing off if the
+server is overloaded). A file that fails doesn't stop the others; the
+failures are listed at the end.
+
+For large migrations, --manifest records each file's checksum, task ID and
+upload status in a JSON file as the batch goes. After failures or Ctrl-C,
+--resume with that file uploads only the files that failed or were never
+attempted; give it the same metadata flags as the first run. A file whose
+upload worked but a later step didn't, like --attachments-note or
+--on-success, is recorded as uploaded with a warning.
+
+--on-success and --on-failure delete uploaded files or move them to a
+directory, adding a number to the name if it's taken there. --delete-after
+and --move-to do the same, but only once the server has consumed the file
+successfully; files it rejects, e.g. as duplicates, are kept.
+
+Tags, correspondents and types that don't exist make the upload fail, unless
+--create-missing creates them first. --ask-create asks before each one.
+
+A file named - is read from stdin, e.g. at the end of a scanning pipeline.
+--filename names it, which also sets its type and default title.
+
+--progress waits for the files to be consumed and shows each one's stages
+live (see "paperless tasks follow"). --wait waits for them too, then prints
+each file's document ID and title, or why it failed; with --json as one
+object per file for scripts.
+
 Example:
   paperless documents upload invoice.pdf
   paperless documents upload *.pdf --title "January Invoices"
-  paperless documents upload doc.pdf --tag bills --correspondent "ACME"`,
-	Args: cobra.MinimumNArgs(1),
+  paperless documents upload doc.pdf --tag bills --correspondent "ACME"
+  paperless documents upload doc.pdf --correspondent "New Corp" --create-missing
+  paperless documents upload scans/*.pdf --interactive
+  paperless documents upload archive/*.pdf --concurrency 8
+  paperless documents upload archive/*.pdf --manifest migration.json
+  paperless documents upload --resume migration.json
+  paperless documents upload binder/*.pdf --title-sequence "Contract p{n}" --asn-start 1000
+  paperless documents upload inbox/*.pdf --on-success move:done --on-failure move:failed
+  paperless documents upload scans/*.pdf --move-to ~/scans/archived
+  paperless documents upload scans/*.pdf --progress
+  paperless documents upload invoice.pdf --wait --json | jq .document_id
+  scanimage --format=tiff | img2pdf | paperless documents upload - --filename scan.pdf`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if uploadResume != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("--resume takes the files from the manifest")
+			}
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	RunE: runDocsUpload,
 }
 
 var docsDownloadCmd = &cobra.Command{
-	Use:   "download <id>",
+	Use:   "download [id...]",
 	Short: "Download document",
-	Long: `Download a document file.
+	Long: `Download document files. IDs can be given as ranges like 100-120 and
+comma-separated lists; -o only works for a single document. Documents of a
+range that don't exist are skipped with a warning.
+
+With --zip, several documents are fetched as one zip archive in a single
+request; --original and --both select what goes into the archive.
+
+--name-template names the file after the document's metadata, given as a Go
+template with the fields .ID, .Title, .Correspondent, .DocumentType,
+.StoragePath, .Tags, .Created (YYYY-MM-DD), .Year, .ASN and .OriginalName,
+and the functions join, lower and upper. Slashes create directories.
+
+With --stamp, the text is laid diagonally across every page of the saved PDF,
+e.g. to mark a copy before forwarding it. This needs qpdf or pdftk.
+
+With --dir, every document matching --query, --tag, --correspondent, --type,
+--created-after and --created-before is downloaded into a directory tree,
+by default correspondent/year/"date title", with its metadata in a JSON
+file next to it. --name-template changes the layout. Progress is saved after
+every document; --resume continues an interrupted run with the same options
+and retries the documents that failed.
 
 Example:
   paperless documents download 123
   paperless documents download 123 -o ~/Downloads/doc.pdf
-  paperless documents download 123 --original`,
-	Args: cobra.ExactArgs(1),
+  paperless documents download 123 --original
+  paperless documents download 123 --both --with-metadata
+  paperless documents download 123 --name-template "{{.Created}}_{{.Correspondent}}_{{.Title}}.pdf"
+  paperless documents download 123 --both --name-template "{{.Year}}/{{.Title}}"
+  paperless documents download 123 --stamp "Copy for accountant {date}"
+  paperless documents download 100-120 --name-template "{{.Year}}/{{.Title}}"
+  paperless documents download --ids 1,2,3 --zip out.zip
+  paperless documents download --query tax --created-after 2024-01-01 --dir ./export
+  paperless documents download --query tax --created-after 2024-01-01 --dir ./export --resume`,
 	RunE: runDocsDownload,
 }
 
 var docsEditCmd = &cobra.Command{
-	Use:   "edit <id>",
+	Use:   "edit <id>...",
 	Short: "Edit document metadata",
-	Long: `Edit a document's metadata.
+	Long: `Edit the metadata of documents. IDs can be given as ranges like 100-120
+and comma-separated lists; each document gets the same changes.
+
+With --create-missing, tags, correspondents and types that don't exist yet
+are created instead of failing. --ask-create asks before each one.
+
+"-" or "none" clears a correspondent, type or storage path. The text of
+--content-from-file replaces the document's content, e.g. to fix bad OCR;
+"-" reads it from stdin.
 
 Example:
   paperless documents edit 123 --title "New Title"
   paperless documents edit 123 --add-tag important
-  paperless documents edit 123 --correspondent "New Corp"`,
-	Args: cobra.ExactArgs(1),
+  paperless documents edit 123 --add-tag new-project --create-missing
+  paperless documents edit 123 --correspondent "New Corp"
+  paperless documents edit 123 --field "Due date=2024-03-31" --field Paid=yes
+  paperless documents edit 123 --remove-field Paid
+  paperless documents edit 123 --created 2024-03-01 --storage-path Taxes
+  paperless documents edit 123 --owner alice --content-from-file fixed.txt`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: runDocsEdit,
 }
 
 var docsDeleteCmd = &cobra.Command{
-	Use:   "delete <id>...",
+	Use:   "delete [id...]",
 	Short: "Delete document(s)",
-	Long: `Delete one or more documents.
+	Long: `Delete one or more documents, given by ID or by filters. IDs can be
+given as ranges like 100-120, whose documents that don't exist are skipped.
+
+Documents on legal hold, tagged legal-hold or with a "legal-hold" boolean
+custom field set (see 'config set hold'), are refused unless --override-hold
+is given; with filters they are skipped instead.
+
+With filters, the matching documents are listed first and the deletion must
+be confirmed by typing their number, unless --force is given.
 
 Example:
   paperless documents delete 123
-  paperless documents delete 123 456 789 --force`,
-	Args: cobra.MinimumNArgs(1),
+  paperless documents delete 123 456 789 --force
+  paperless documents delete --query "draft" --tag scans`,
 	RunE: runDocsDelete,
 }
 
@@ -147,16 +300,59 @@ var (
 	listDocType       string
 	listCreatedAfter  string
 	listCreatedBefore string
+	listStoragePath   string
+	listASN           int
+	listASNFrom       int
+	listASNTo         int
+	listAddedAfter    string
+	listAddedBefore   string
+	listOwner         string
+	listUntagged      bool
+	listNoCorr        bool
+	listNoType        bool
+	listRaw           bool
+	listIDs           bool
+	getRaw            bool
 	listLimit         int
 	listPage          int
+	listAll           bool
+	listSort          string
+	listReverse       bool
 
 	uploadTitle         string
 	uploadCorrespondent string
 	uploadDocType       string
 	uploadTags          []string
 
+	uploadAttachmentsNote bool
+	uploadInteractive     bool
+	uploadASNStart        int
+	uploadTitleSequence   string
+	uploadOnSuccess       string
+	uploadOnFailure       string
+	uploadProgress        bool
+	uploadWait            bool
+	uploadWaitTimeout     time.Duration
+	uploadWaitInterval    time.Duration
+	uploadStdinName       string
+	uploadConcurrency     int
+	uploadManifestPath    string
+	uploadResume          string
+	uploadDeleteAfter     bool
+	uploadMoveTo          string
+
+	// uploadImplications are the configured tag implications, applied to
+	// every upload
+	uploadImplications tagImplications
+
 	downloadOutput   string
 	downloadOriginal bool
+	downloadBoth     bool
+	downloadMetadata bool
+	downloadIDs      []int
+	downloadZip      string
+	downloadStamp    string
+	downloadNameTmpl string
 
 	editTitle            string
 	editCorrespondent    string
@@ -164,8 +360,19 @@ var (
 	editAddTags          []string
 	editRemoveTags       []string
 	editASN              int
-
-	deleteForce bool
+	editFields           []string
+	editSetFields        []string
+	editRemoveFields     []string
+	editOwnership        ownershipFlags
+	editCreated          string
+	editStoragePath      string
+	editContentFile      string
+
+	deleteForce         bool
+	deleteQuery         string
+	deleteTags          []string
+	deleteCorrespondent string
+	deleteDocType       string
 
 	similarLimit int
 	thumbOutput  string
@@ -186,26 +393,100 @@ func init() {
 
 	// List flags
 	docsListCmd.Flags().StringVar(&listQuery, "query", "", "search query")
+	docsListCmd.RegisterFlagCompletionFunc("query", completeQuery)
 	docsListCmd.Flags().StringArrayVar(&listTags, "tag", nil, "filter by tag (repeatable)")
 	docsListCmd.Flags().StringVar(&listCorrespondent, "correspondent", "", "filter by correspondent")
 	docsListCmd.Flags().StringVar(&listDocType, "type", "", "filter by document type")
-	docsListCmd.Flags().StringVar(&listCreatedAfter, "created-after", "", "filter by creation date (YYYY-MM-DD)")
-	docsListCmd.Flags().StringVar(&listCreatedBefore, "created-before", "", "filter by creation date (YYYY-MM-DD)")
+	docsListCmd.Flags().StringVar(&listCreatedAfter, "created-after", "", "filter by creation date (YYYY-MM-DD, 30d, last month, 2024-Q1, ...)")
+	docsListCmd.Flags().StringVar(&listCreatedBefore, "created-before", "", "filter by creation date (YYYY-MM-DD, 30d, last month, 2024-Q1, ...)")
+	docsListCmd.Flags().StringVar(&listStoragePath, "storage-path", "", "filter by storage path")
+	docsListCmd.Flags().IntVar(&listASN, "asn", 0, "filter by archive serial number")
+	docsListCmd.Flags().IntVar(&listASNFrom, "asn-from", 0, "filter by archive serial number, from this one on")
+	docsListCmd.Flags().IntVar(&listASNTo, "asn-to", 0, "filter by archive serial number, up to this one")
+	docsListCmd.MarkFlagsMutuallyExclusive("asn", "asn-from")
+	docsListCmd.MarkFlagsMutuallyExclusive("asn", "asn-to")
+	docsListCmd.Flags().StringVar(&listAddedAfter, "added-after", "", "filter by date added (YYYY-MM-DD, 30d, last month, 2024-Q1, ...)")
+	docsListCmd.Flags().StringVar(&listAddedBefore, "added-before", "", "filter by date added (YYYY-MM-DD, 30d, last month, 2024-Q1, ...)")
+	docsListCmd.Flags().StringVar(&listOwner, "owner", "", `filter by owner: username or ID, "me" or "none"`)
+	docsListCmd.Flags().BoolVar(&listUntagged, "untagged", false, "only documents without tags")
+	docsListCmd.Flags().BoolVar(&listNoCorr, "no-correspondent", false, "only documents without correspondent")
+	docsListCmd.Flags().BoolVar(&listNoType, "no-type", false, "only documents without document type")
+	docsListCmd.MarkFlagsMutuallyExclusive("untagged", "tag")
+	docsListCmd.MarkFlagsMutuallyExclusive("no-correspondent", "correspondent")
+	docsListCmd.MarkFlagsMutuallyExclusive("no-type", "type")
+	docsListCmd.Flags().BoolVar(&listRaw, "raw", false, "show tag IDs instead of names")
+	docsListCmd.Flags().BoolVar(&listIDs, "ids", false, "print only the IDs of matching documents, one per line")
+	docsListCmd.MarkFlagsMutuallyExclusive("ids", "raw")
 	docsListCmd.Flags().IntVar(&listLimit, "limit", 25, "max results")
 	docsListCmd.Flags().IntVar(&listPage, "page", 1, "page number")
+	docsListCmd.Flags().BoolVar(&listAll, "all", false, "list all matching documents, page by page")
+	docsListCmd.MarkFlagsMutuallyExclusive("all", "page")
+	docsListCmd.Flags().StringVar(&listSort, "sort", "-created", "order by created, added, modified, title, asn or correspondent; prefix - for descending")
+	docsListCmd.Flags().BoolVar(&listReverse, "reverse", false, "reverse the --sort order")
+	docsListCmd.RegisterFlagCompletionFunc("sort", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var keys []string
+		for key := range documentSortFields {
+			keys = append(keys, key, "-"+key)
+		}
+		slices.Sort(keys)
+		return keys, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	// Get flags
+	docsGetCmd.Flags().BoolVar(&getRaw, "raw", false, "show tag, correspondent and type IDs instead of names")
 
 	// Search flags
 	docsSearchCmd.Flags().IntVar(&listLimit, "limit", 25, "max results")
+	addFetchFlags(docsListCmd)
+	addFetchFlags(docsSearchCmd)
+	addTableFlags(docsListCmd, documentColumns(nil))
+	docsListCmd.MarkFlagsMutuallyExclusive("ids", "columns")
+	addTableFlags(docsSearchCmd, documentColumns(nil))
 
 	// Upload flags
 	docsUploadCmd.Flags().StringVar(&uploadTitle, "title", "", "document title")
 	docsUploadCmd.Flags().StringVar(&uploadCorrespondent, "correspondent", "", "correspondent name or ID")
 	docsUploadCmd.Flags().StringVar(&uploadDocType, "type", "", "document type name or ID")
 	docsUploadCmd.Flags().StringArrayVar(&uploadTags, "tag", nil, "tag name or ID (repeatable)")
+	createMissing.register(docsUploadCmd)
+	docsUploadCmd.Flags().BoolVarP(&uploadInteractive, "interactive", "i", false, "preview each file and prompt for its metadata before uploading")
+	docsUploadCmd.Flags().IntVar(&uploadASNStart, "asn-start", 0, "assign consecutive ASNs from this number once the files are consumed")
+	docsUploadCmd.Flags().StringVar(&uploadTitleSequence, "title-sequence", "", `title with {n} replaced by the file's position, e.g. "Contract p{n}"; {name} and {date} work too`)
+	docsUploadCmd.MarkFlagsMutuallyExclusive("title", "title-sequence")
+	docsUploadCmd.MarkFlagsMutuallyExclusive("interactive", "title-sequence")
+	docsUploadCmd.MarkFlagsMutuallyExclusive("interactive", "asn-start")
+	docsUploadCmd.Flags().BoolVar(&uploadProgress, "progress", false, "wait for consumption and show its stages live")
+	docsUploadCmd.MarkFlagsMutuallyExclusive("interactive", "progress")
+	docsUploadCmd.Flags().BoolVar(&uploadWait, "wait", false, "wait for consumption and print the document ID and title")
+	docsUploadCmd.Flags().DurationVar(&uploadWaitTimeout, "wait-timeout", 5*time.Minute, "how long --wait, --delete-after, --move-to, --asn-start and --attachments-note wait for each file")
+	docsUploadCmd.Flags().DurationVar(&uploadWaitInterval, "wait-interval", 2*time.Second, "how often --wait, --delete-after, --move-to, --asn-start and --attachments-note check the tasks")
+	docsUploadCmd.MarkFlagsMutuallyExclusive("interactive", "wait")
+	docsUploadCmd.Flags().StringVar(&uploadStdinName, "filename", "stdin.pdf", "file name for the document read from stdin with -")
+	docsUploadCmd.Flags().IntVar(&uploadConcurrency, "concurrency", 4, "number of files uploaded at once")
+	docsUploadCmd.Flags().StringVar(&uploadManifestPath, "manifest", "", "record each file's checksum, task ID and upload status in this JSON file")
+	docsUploadCmd.Flags().StringVar(&uploadResume, "resume", "", "upload the failed and never attempted files of a manifest again")
+	docsUploadCmd.MarkFlagsMutuallyExclusive("manifest", "resume")
+	docsUploadCmd.Flags().BoolVar(&uploadAttachmentsNote, "attachments-note", false, "add embedded XML attachments (e.g. ZUGFeRD) as a note once consumed")
+	docsUploadCmd.Flags().StringVar(&uploadOnSuccess, "on-success", "keep", "what to do with uploaded files: keep, delete or move:DIR")
+	docsUploadCmd.Flags().StringVar(&uploadOnFailure, "on-failure", "keep", "what to do with files that failed to upload: keep or move:DIR")
+	docsUploadCmd.Flags().BoolVar(&uploadDeleteAfter, "delete-after", false, "delete each file once it has been consumed successfully")
+	docsUploadCmd.Flags().StringVar(&uploadMoveTo, "move-to", "", "move each file to this directory once it has been consumed successfully")
+	docsUploadCmd.MarkFlagsMutuallyExclusive("delete-after", "move-to", "on-success")
 
 	// Download flags
 	docsDownloadCmd.Flags().StringVarP(&downloadOutput, "output", "o", "", "output path")
 	docsDownloadCmd.Flags().BoolVar(&downloadOriginal, "original", false, "download original file")
+	docsDownloadCmd.Flags().BoolVar(&downloadBoth, "both", false, "download original and archived file with suffixes")
+	docsDownloadCmd.Flags().BoolVar(&downloadMetadata, "with-metadata", false, "also write document metadata as JSON")
+	docsDownloadCmd.Flags().IntSliceVar(&downloadIDs, "ids", nil, "document IDs to put into the --zip archive (comma separated)")
+	docsDownloadCmd.Flags().StringVar(&downloadZip, "zip", "", "download as a zip archive to this path")
+	docsDownloadCmd.Flags().StringVar(&downloadStamp, "stamp", "", "text to stamp across each page of PDFs, {date} and {id} are filled in")
+	docsDownloadCmd.MarkFlagsMutuallyExclusive("zip", "output")
+	docsDownloadCmd.MarkFlagsMutuallyExclusive("zip", "with-metadata")
+	docsDownloadCmd.MarkFlagsMutuallyExclusive("zip", "stamp")
+	docsDownloadCmd.Flags().StringVar(&downloadNameTmpl, "name-template", "", `file name from the document's metadata, e.g. "{{.Created}}_{{.Correspondent}}_{{.Title}}.pdf"`)
+	docsDownloadCmd.MarkFlagsMutuallyExclusive("both", "original")
+	docsDownloadCmd.MarkFlagsMutuallyExclusive("name-template", "output", "zip")
 
 	// Edit flags
 	docsEditCmd.Flags().StringVar(&editTitle, "title", "", "new title")
@@ -213,10 +494,24 @@ func init() {
 	docsEditCmd.Flags().StringVar(&editDocType, "type", "", "set document type")
 	docsEditCmd.Flags().StringArrayVar(&editAddTags, "add-tag", nil, "add tag (repeatable)")
 	docsEditCmd.Flags().StringArrayVar(&editRemoveTags, "remove-tag", nil, "remove tag (repeatable)")
+	createMissing.register(docsEditCmd)
 	docsEditCmd.Flags().IntVar(&editASN, "asn", 0, "archive serial number")
+	docsEditCmd.Flags().StringArrayVar(&editFields, "field", nil, "set custom field: <name|id>=<value> (repeatable)")
+	docsEditCmd.Flags().StringArrayVar(&editSetFields, "set-field", nil, "same as --field")
+	docsEditCmd.Flags().StringArrayVar(&editRemoveFields, "remove-field", nil, "remove custom field from the document (repeatable)")
+	editOwnership.register(docsEditCmd)
+	docsEditCmd.Flags().StringVar(&editCreated, "created", "", "created date (YYYY-MM-DD)")
+	docsEditCmd.Flags().StringVar(&editStoragePath, "storage-path", "", `set storage path by name or ID, "none" to clear`)
+	docsEditCmd.Flags().StringVar(&editContentFile, "content-from-file", "", `replace the content with the text of a file, "-" for stdin`)
 
 	// Delete flags
 	docsDeleteCmd.Flags().BoolVarP(&deleteForce, "force", "f", false, "skip confirmation")
+	docsDeleteCmd.Flags().StringVar(&deleteQuery, "query", "", "delete documents matching a search query")
+	docsDeleteCmd.RegisterFlagCompletionFunc("query", completeQuery)
+	docsDeleteCmd.Flags().StringArrayVar(&deleteTags, "tag", nil, "delete documents with tag (repeatable)")
+	docsDeleteCmd.Flags().StringVar(&deleteCorrespondent, "correspondent", "", "delete documents from correspondent")
+	docsDeleteCmd.Flags().StringVar(&deleteDocType, "type", "", "delete documents of document type")
+	addOverrideHoldFlag(docsDeleteCmd)
 
 	// Similar flags
 	docsSimilarCmd.Flags().IntVar(&similarLimit, "limit", 10, "max results")
@@ -231,23 +526,208 @@ func runDocsList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	ordering, err := documentOrdering(listSort, listReverse)
+	if err != nil {
+		return err
+	}
+	columns, err := selectDocumentColumns(listNames(client), defaultDocumentColumns)
+	if err != nil {
+		return err
+	}
+
 	params := api.DocumentListParams{
-		Query:         listQuery,
-		Tags:          listTags,
-		Correspondent: listCorrespondent,
-		DocumentType:  listDocType,
-		CreatedAfter:  listCreatedAfter,
-		CreatedBefore: listCreatedBefore,
-		Limit:         listLimit,
-		Page:          listPage,
-		Ordering:      "-created",
+		Query:           listQuery,
+		Tags:            listTags,
+		Correspondent:   listCorrespondent,
+		DocumentType:    listDocType,
+		CreatedAfter:    listCreatedAfter,
+		CreatedBefore:   listCreatedBefore,
+		StoragePath:     listStoragePath,
+		AddedAfter:      listAddedAfter,
+		AddedBefore:     listAddedBefore,
+		Untagged:        listUntagged,
+		NoCorrespondent: listNoCorr,
+		NoDocumentType:  listNoType,
+		Limit:           listLimit,
+		Page:            listPage,
+		Ordering:        ordering,
+	}
+	if cmd.Flags().Changed("asn") {
+		params.ASN = &listASN
+	}
+	if cmd.Flags().Changed("asn-from") {
+		params.ASNFrom = &listASNFrom
+	}
+	if cmd.Flags().Changed("asn-to") {
+		params.ASNTo = &listASNTo
+	}
+	if err := resolveDateFilters(&params.CreatedAfter, &params.CreatedBefore); err != nil {
+		return err
+	}
+	if err := resolveDateFilters(&params.AddedAfter, &params.AddedBefore); err != nil {
+		return err
+	}
+	switch listOwner {
+	case "":
+	case "none":
+		params.NoOwner = true
+	case "me":
+		id, err := client.CurrentUserID()
+		if err != nil {
+			return err
+		}
+		params.OwnerID = &id
+	default:
+		u, err := findUser(client, listOwner)
+		if err != nil {
+			return err
+		}
+		params.OwnerID = &u.ID
+	}
+
+	if listAll && !cmd.Flags().Changed("limit") {
+		params.Limit = listAllPageSize
+	}
+	if listIDs {
+		return printDocumentIDs(client, params, listAll)
+	}
+	if listAll {
+		return listAllPages(client, params, columns)
 	}
 
-	result, err := client.ListDocuments(params)
+	result, err := fetchDocuments(client, params)
 	if err != nil {
 		return err
 	}
 
+	if err := printDocumentList(result, columns); err != nil {
+		return err
+	}
+	if result.Next != "" && !isQuiet() && !isJSON() {
+		fmt.Fprintf(os.Stderr, "More on the next page: --page %d, or --all for all of them\n", listPage+1)
+	}
+	return nil
+}
+
+// documentSortFields maps --sort keys to the API's ordering fields
+var documentSortFields = map[string]string{
+	"created":       "created",
+	"added":         "added",
+	"modified":      "modified",
+	"title":         "title",
+	"asn":           "archive_serial_number",
+	"correspondent": "correspondent__name",
+}
+
+// documentOrdering turns a --sort key like "-title" into the API ordering,
+// reversed if asked
+func documentOrdering(key string, reverse bool) (string, error) {
+	desc := strings.HasPrefix(key, "-")
+	field, ok := documentSortFields[strings.ToLower(strings.TrimPrefix(key, "-"))]
+	if !ok {
+		return "", fmt.Errorf("invalid --sort %q: use created, added, modified, title, asn or correspondent", key)
+	}
+	if desc != reverse {
+		field = "-" + field
+	}
+	return field, nil
+}
+
+// listAllPageSize is the page size of --all, unless --limit sets it
+const listAllPageSize = 100
+
+// listNames resolves the tags of listed documents unless --raw is given
+func listNames(client *api.Client) *metadataNames {
+	if listRaw {
+		return nil
+	}
+	return newMetadataNames(client)
+}
+
+// listAllPages prints every document matching params, writing each page
+// as it arrives. JSON output is one array of documents.
+func listAllPages(client *api.Client, params api.DocumentListParams, columns []tableColumn[api.Document]) error {
+	// Pages are printed as they arrive, so the columns have fixed widths
+	// instead of fitting the content
+	if isJSON() {
+		fmt.Print("[")
+	} else {
+		fmt.Println(fixedRow(columns, func(col tableColumn[api.Document]) string { return columnHeader(col.name) }))
+	}
+
+	listed := 0
+	for params.Page = 1; ; params.Page++ {
+		result, err := fetchDocuments(client, params)
+		if err != nil {
+			if isJSON() {
+				fmt.Println("]")
+			}
+			return err
+		}
+
+		for _, doc := range result.Results {
+			if isJSON() {
+				data, err := json.MarshalIndent(doc, "  ", "  ")
+				if err != nil {
+					return err
+				}
+				if listed > 0 {
+					fmt.Print(",")
+				}
+				fmt.Printf("\n  %s", data)
+			} else {
+				fmt.Println(fixedRow(columns, func(col tableColumn[api.Document]) string { return col.value(doc) }))
+			}
+			listed++
+		}
+		if result.Next == "" || len(result.Results) == 0 {
+			break
+		}
+	}
+
+	if isJSON() {
+		if listed > 0 {
+			fmt.Println()
+		}
+		fmt.Println("]")
+	} else if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "\nListed %d documents\n", listed)
+	}
+	return nil
+}
+
+// printDocumentIDs prints the IDs of the documents matching params, one per
+// line, for piping into other commands. With all it goes through every page.
+func printDocumentIDs(client *api.Client, params api.DocumentListParams, all bool) error {
+	params.Fields = []string{"id"}
+	if all {
+		params.Page = 1
+	}
+	for {
+		result, err := client.ListDocuments(params)
+		if err != nil {
+			return err
+		}
+		for _, doc := range result.Results {
+			fmt.Println(doc.ID)
+		}
+
+		if result.Next == "" || len(result.Results) == 0 {
+			return nil
+		}
+		if !all {
+			if !isQuiet() {
+				fmt.Fprintf(os.Stderr, "More on the next page: --page %d, or --all for all of them\n", params.Page+1)
+			}
+			return nil
+		}
+		params.Page++
+	}
+}
+
+// printDocumentList prints a page of documents as a table of the columns
+// with a count, or as JSON
+func printDocumentList(result *api.PaginatedResponse[api.Document], columns []tableColumn[api.Document]) error {
 	if isJSON() {
 		return printJSON(result)
 	}
@@ -257,13 +737,7 @@ func runDocsList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tTITLE\tCREATED\tTAGS")
-	for _, doc := range result.Results {
-		tagStr := fmt.Sprintf("%d tags", len(doc.Tags))
-		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", doc.ID, truncate(doc.Title, 40), doc.CreatedDate, tagStr)
-	}
-	w.Flush()
+	printTable(columns, result.Results)
 
 	if !isQuiet() {
 		fmt.Fprintf(os.Stderr, "\nShowing %d of %d documents\n", len(result.Results), result.Count)
@@ -278,18 +752,29 @@ func runDocsSearch(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	columns, err := selectDocumentColumns(newMetadataNames(client), "id,title,created,score")
+	if err != nil {
+		return err
+	}
+
 	params := api.DocumentListParams{
 		Query:    args[0],
 		Limit:    listLimit,
 		Ordering: "-created",
 	}
 
-	result, err := client.ListDocuments(params)
+	result, err := fetchDocuments(client, params)
 	if err != nil {
 		return err
 	}
 
 	if isJSON() {
+		for _, doc := range result.Results {
+			if hit := doc.SearchHit; hit != nil {
+				hit.Highlights = stripHighlights(hit.Highlights)
+				hit.NoteHighlights = stripHighlights(hit.NoteHighlights)
+			}
+		}
 		return printJSON(result)
 	}
 
@@ -298,12 +783,7 @@ func runDocsSearch(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tTITLE\tCREATED")
-	for _, doc := range result.Results {
-		fmt.Fprintf(w, "%d\t%s\t%s\n", doc.ID, truncate(doc.Title, 50), doc.CreatedDate)
-	}
-	w.Flush()
+	printSearchHits(columns, result.Results)
 
 	if !isQuiet() {
 		fmt.Fprintf(os.Stderr, "\nFound %d documents\n", result.Count)
@@ -318,20 +798,43 @@ func runDocsGet(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	id, err := strconv.Atoi(args[0])
+	ids, ranged, err := parseDocumentIDRanges(args)
 	if err != nil {
-		return fmt.Errorf("invalid document ID: %s", args[0])
+		return err
 	}
 
-	doc, err := client.GetDocument(id)
-	if err != nil {
-		return err
+	docs := make([]*api.Document, 0, len(ids))
+	for _, id := range ids {
+		doc, err := client.GetDocument(id)
+		if skipMissing(id, ranged, err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		docs = append(docs, doc)
 	}
 
 	if isJSON() {
-		return printJSON(doc)
+		if len(ids) == 1 && len(docs) == 1 {
+			return printJSON(docs[0])
+		}
+		return printJSON(docs)
+	}
+
+	for i, doc := range docs {
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := printDocument(client, doc); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
+// printDocument prints the details of a document
+func printDocument(client *api.Client, doc *api.Document) error {
 	fmt.Printf("ID:           %d\n", doc.ID)
 	fmt.Printf("Title:        %s\n", doc.Title)
 	fmt.Printf("Created:      %s\n", doc.CreatedDate)
@@ -341,210 +844,1087 @@ func runDocsGet(cmd *cobra.Command, args []string) error {
 	if doc.ArchiveSerialNumber != nil {
 		fmt.Printf("ASN:          %d\n", *doc.ArchiveSerialNumber)
 	}
+	var names *metadataNames
+	if !getRaw {
+		names = newMetadataNames(client)
+	}
 	if doc.Correspondent != nil {
-		fmt.Printf("Correspondent: %d\n", *doc.Correspondent)
+		fmt.Printf("Correspondent: %s\n", names.correspondent(*doc.Correspondent))
 	}
 	if doc.DocumentType != nil {
-		fmt.Printf("Type:         %d\n", *doc.DocumentType)
+		fmt.Printf("Type:         %s\n", names.documentType(*doc.DocumentType))
 	}
 	if len(doc.Tags) > 0 {
-		fmt.Printf("Tags:         %v\n", doc.Tags)
+		fmt.Printf("Tags:         %s\n", names.tagList(doc.Tags))
+	}
+	if len(doc.CustomFields) > 0 {
+		fields, err := client.ListCustomFields(api.ListParams{})
+		if err != nil {
+			return err
+		}
+		byID := make(map[int]*api.CustomField, len(fields.Results))
+		for i := range fields.Results {
+			byID[fields.Results[i].ID] = &fields.Results[i]
+		}
+
+		fmt.Println("Custom fields:")
+		for _, cf := range doc.CustomFields {
+			if field, ok := byID[cf.Field]; ok {
+				fmt.Printf("  %s: %s\n", field.Name, field.FormatValue(cf.Value))
+			} else {
+				fmt.Printf("  %d: %v\n", cf.Field, cf.Value)
+			}
+		}
 	}
 
 	return nil
 }
 
 func runDocsUpload(cmd *cobra.Command, args []string) error {
-	client, err := getClient()
-	if err != nil {
+	if uploadTitleSequence != "" && !strings.Contains(uploadTitleSequence, "{n}") {
+		return fmt.Errorf("--title-sequence must contain {n}")
+	}
+	if cmd.Flags().Changed("asn-start") && uploadASNStart < 1 {
+		return fmt.Errorf("--asn-start must be positive")
+	}
+	if uploadConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if err := setSourcePolicies(uploadOnSuccess, uploadOnFailure); err != nil {
 		return err
 	}
+	setConsumedPolicy(uploadDeleteAfter, uploadMoveTo)
 
-	// Resolve correspondent ID
-	var correspondentID *int
-	if uploadCorrespondent != "" {
-		if id, err := strconv.Atoi(uploadCorrespondent); err == nil {
-			correspondentID = &id
-		} else {
-			corr, err := client.FindCorrespondentByName(uploadCorrespondent)
-			if err != nil {
-				return fmt.Errorf("correspondent not found: %s", uploadCorrespondent)
-			}
-			correspondentID = &corr.ID
+	// A resumed batch continues in its manifest
+	var manifest *uploadManifest
+	if uploadResume != "" {
+		if uploadInteractive || cmd.Flags().Changed("asn-start") || uploadTitleSequence != "" {
+			return fmt.Errorf("--resume can't be combined with --interactive, --asn-start or --title-sequence")
 		}
-	}
-
-	// Resolve document type ID
-	var docTypeID *int
-	if uploadDocType != "" {
-		if id, err := strconv.Atoi(uploadDocType); err == nil {
-			docTypeID = &id
-		} else {
-			dt, err := client.FindDocumentTypeByName(uploadDocType)
-			if err != nil {
-				return fmt.Errorf("document type not found: %s", uploadDocType)
-			}
-			docTypeID = &dt.ID
+		m, err := loadUploadManifest(uploadResume)
+		if err != nil {
+			return err
 		}
-	}
-
-	// Resolve tag IDs
-	var tagIDs []int
-	for _, tagArg := range uploadTags {
-		if id, err := strconv.Atoi(tagArg); err == nil {
-			tagIDs = append(tagIDs, id)
-		} else {
-			tag, err := client.FindTagByName(tagArg)
-			if err != nil {
-				return fmt.Errorf("tag not found: %s", tagArg)
+		if args, err = m.remaining(); err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			if !isQuiet() {
+				fmt.Fprintf(os.Stderr, "All %d file(s) in %s were uploaded\n", len(m.Files), uploadResume)
 			}
-			tagIDs = append(tagIDs, tag.ID)
+			return nil
+		}
+		if !isQuiet() {
+			counts := m.counts()
+			fmt.Fprintf(os.Stderr, "Resuming %s: %d uploaded, retrying %d failed and %d pending file(s)\n",
+				uploadResume, counts[manifestUploaded], counts[manifestFailed], counts[manifestPending])
 		}
+		manifest = m
 	}
 
-	for _, filePath := range args {
-		// Check if file exists
-		if _, err := os.Stat(filePath); os.IsNotExist(err) {
-			return fmt.Errorf("file not found: %s", filePath)
+	if i := slices.Index(args, "-"); i >= 0 {
+		if uploadManifestPath != "" {
+			return fmt.Errorf("--manifest can't record stdin")
 		}
-
-		title := uploadTitle
-		if title == "" {
-			// Use filename without extension as title
-			title = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+		if slices.Contains(args[i+1:], "-") {
+			return fmt.Errorf("stdin can only be uploaded once")
 		}
-
-		if !isQuiet() {
-			fmt.Fprintf(os.Stderr, "Uploading %s...\n", filepath.Base(filePath))
+		if uploadInteractive || createMissing.ask {
+			return fmt.Errorf("--interactive and --ask-create can't read answers and a file from stdin")
 		}
-
-		taskID, err := client.UploadDocument(filePath, title, correspondentID, docTypeID, tagIDs)
-		if err != nil {
-			return fmt.Errorf("upload failed for %s: %w", filePath, err)
+		if uploadOnSuccess != "keep" || uploadOnFailure != "keep" || uploadSourcePolicies.consumed.action != "keep" {
+			return fmt.Errorf("--on-success, --on-failure, --delete-after and --move-to don't apply to stdin")
 		}
-
-		if isJSON() {
-			printJSON(map[string]string{"file": filePath, "task_id": taskID})
-		} else if !isQuiet() {
-			fmt.Printf("Uploaded %s (task: %s)\n", filepath.Base(filePath), taskID)
+		path, cleanup, err := spoolStdin(uploadStdinName)
+		if err != nil {
+			return err
 		}
+		defer cleanup()
+		args = slices.Clone(args)
+		args[i] = path
 	}
 
-	return nil
-}
-
-func runDocsDownload(cmd *cobra.Command, args []string) error {
 	client, err := getClient()
 	if err != nil {
 		return err
 	}
 
-	id, err := strconv.Atoi(args[0])
-	if err != nil {
-		return fmt.Errorf("invalid document ID: %s", args[0])
-	}
-
-	data, filename, err := client.DownloadDocument(id, downloadOriginal)
+	// The workspace file fills in what the flags leave open
+	correspondent, docType, storagePath, tags := uploadCorrespondent, uploadDocType, "", uploadTags
+	titleTemplate := uploadTitleSequence
+	ws, err := currentWorkspace()
 	if err != nil {
 		return err
 	}
-
-	outputPath := downloadOutput
-	if outputPath == "" {
-		outputPath = filename
-		if outputPath == "" {
-			outputPath = fmt.Sprintf("document_%d.pdf", id)
+	if ws != nil {
+		correspondent = firstNonEmpty(correspondent, ws.Correspondent)
+		docType = firstNonEmpty(docType, ws.DocumentType)
+		storagePath = ws.StoragePath
+		tags = append(slices.Clone(ws.Tags), tags...)
+		if uploadTitle == "" && titleTemplate == "" {
+			titleTemplate = ws.Title
+		}
+		if !isQuiet() {
+			fmt.Fprintf(os.Stderr, "Using upload defaults from %s\n", ws.Path)
 		}
 	}
 
-	if err := os.WriteFile(outputPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
-	}
-
-	if !isQuiet() {
-		fmt.Printf("Downloaded to %s (%d bytes)\n", outputPath, len(data))
-	}
-
-	return nil
-}
-
-func runDocsEdit(cmd *cobra.Command, args []string) error {
-	client, err := getClient()
+	params, err := resolveUploadParams(client, correspondent, docType, storagePath, tags)
 	if err != nil {
 		return err
 	}
+	params.Title = uploadTitle
 
-	id, err := strconv.Atoi(args[0])
-	if err != nil {
-		return fmt.Errorf("invalid document ID: %s", args[0])
-	}
-
-	// Get current document to modify tags
-	doc, err := client.GetDocument(id)
+	uploadImplications, _, err = loadTagImplications(client)
 	if err != nil {
 		return err
 	}
 
-	updates := make(map[string]interface{})
+	if uploadInteractive {
+		return runUploadWizard(client, args, params, titleTemplate)
+	}
 
-	if editTitle != "" {
-		updates["title"] = editTitle
+	// Check all files first so a numbered batch isn't left half uploaded
+	for _, filePath := range args {
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			return fmt.Errorf("file not found: %s", filePath)
+		}
 	}
 
-	if editCorrespondent != "" {
-		if editCorrespondent == "-" || editCorrespondent == "none" {
-			updates["correspondent"] = nil
-		} else if corrID, err := strconv.Atoi(editCorrespondent); err == nil {
-			updates["correspondent"] = corrID
-		} else {
-			corr, err := client.FindCorrespondentByName(editCorrespondent)
-			if err != nil {
-				return fmt.Errorf("correspondent not found: %s", editCorrespondent)
-			}
-			updates["correspondent"] = corr.ID
+	if uploadManifestPath != "" {
+		if manifest, err = newUploadManifest(uploadManifestPath, args); err != nil {
+			return fmt.Errorf("writing manifest: %w", err)
 		}
 	}
 
-	if editDocType != "" {
-		if editDocType == "-" || editDocType == "none" {
-			updates["document_type"] = nil
-		} else if dtID, err := strconv.Atoi(editDocType); err == nil {
-			updates["document_type"] = dtID
-		} else {
-			dt, err := client.FindDocumentTypeByName(editDocType)
-			if err != nil {
-				return fmt.Errorf("document type not found: %s", editDocType)
+	// Subscribe before uploading so no stage is missed
+	var stream *api.StatusStream
+	if uploadProgress {
+		if stream, err = client.OpenStatusStream(context.Background()); err != nil {
+			if !isQuiet() {
+				fmt.Fprintf(os.Stderr, "Live progress unavailable (%v), polling the tasks instead\n", err)
 			}
-			updates["document_type"] = dt.ID
+		} else {
+			defer stream.Close()
 		}
 	}
 
-	if editASN > 0 {
-		updates["archive_serial_number"] = editASN
-	}
+	sd := handleShutdown()
+	defer sd.release()
 
-	// Handle tag modifications
-	if len(editAddTags) > 0 || len(editRemoveTags) > 0 {
-		tags := make(map[int]bool)
-		for _, t := range doc.Tags {
-			tags[t] = true
+	var total int64
+	sizes := make([]int64, len(args))
+	for i, filePath := range args {
+		if info, err := os.Stat(filePath); err == nil {
+			sizes[i] = info.Size()
+			total += sizes[i]
 		}
+	}
+	label := fmt.Sprintf("Uploading %d files", len(args))
+	if len(args) == 1 {
+		label = "Uploading " + filepath.Base(args[0])
+	}
+	progress, finishProgress := newBatchProgress(label, len(args), total)
 
-		// Add tags
-		for _, tagArg := range editAddTags {
-			if tagID, err := strconv.Atoi(tagArg); err == nil {
-				tags[tagID] = true
-			} else {
-				tag, err := client.FindTagByName(tagArg)
-				if err != nil {
-					return fmt.Errorf("tag not found: %s", tagArg)
-				}
-				tags[tag.ID] = true
+	taskIDs := make([]string, len(args))
+	errs := forEachParallelSized(args, uploadConcurrency, func(i int) int64 { return sizes[i] }, func(i int, filePath string) error {
+		if sd.requested() {
+			return errInterrupted
+		}
+		fileParams := params
+		// Tag implications are appended per file
+		fileParams.Tags = slices.Clone(params.Tags)
+		if titleTemplate != "" {
+			fileParams.Title = expandTitle(titleTemplate, filePath, i+1)
+		}
+		fileParams.Progress = progress.item(i)
+
+		// A throttled upload is retried, so failures are settled below
+		taskID, err := sendFile(client, filePath, fileParams)
+		taskIDs[i] = taskID
+		if manifest != nil && taskID != "" {
+			if recordErr := manifest.record(filePath, taskID, err); recordErr != nil {
+				printAbove(os.Stderr, "Warning: writing manifest: %v\n", recordErr)
 			}
 		}
+		return err
+	})
+	finishProgress()
 
-		// Remove tags
-		for _, tagArg := range editRemoveTags {
+	for i, err := range errs {
+		if err == nil || err == errInterrupted || taskIDs[i] != "" {
+			continue
+		}
+		errs[i] = uploadFailed(args[i], err)
+		if manifest != nil {
+			if recordErr := manifest.record(args[i], "", errs[i]); recordErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: writing manifest: %v\n", recordErr)
+			}
+		}
+	}
+
+	// Report the failures together at the end, as the status lines of
+	// parallel uploads interleave
+	var uploaded, failed, warned int
+	var uploadedFiles, uploadedTasks []string
+	for i, err := range errs {
+		if taskIDs[i] != "" {
+			uploaded++
+			uploadedFiles = append(uploadedFiles, args[i])
+			uploadedTasks = append(uploadedTasks, taskIDs[i])
+		}
+		switch {
+		case err == nil || err == errInterrupted:
+		case taskIDs[i] != "":
+			warned++
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", filepath.Base(args[i]), err)
+		default:
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: %v\n", filepath.Base(args[i]), err)
+		}
+	}
+	if manifest != nil && (failed > 0 || sd.requested()) && !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Upload the rest with: paperless documents upload --resume %s\n", manifest.path)
+	}
+	if sd.requested() {
+		return interrupted(cmd, "Interrupted after uploading %d of %d file(s)", uploaded, len(args))
+	}
+	// The uploads are done; Ctrl-C stops waiting for their consumption at once
+	sd.release()
+
+	var consumeErr error
+	if uploadProgress {
+		consumeErr = followUploads(client, stream, uploadedFiles, uploadedTasks)
+	}
+	if uploadWait && consumeErr == nil {
+		consumeErr = waitForUploads(client, uploadedFiles, uploadedTasks)
+	}
+	// Consumed files are handled even if others failed
+	if uploadSourcePolicies.consumed.action != "keep" {
+		if err := handleConsumedFiles(client, uploadedFiles, uploadedTasks); err != nil && consumeErr == nil {
+			consumeErr = err
+		}
+	}
+	if consumeErr != nil {
+		return consumeErr
+	}
+
+	// Files that failed keep their place in the sequence, so they can be
+	// uploaded again with their ASN
+	if uploadASNStart > 0 {
+		if err := assignASNSequence(client, args, taskIDs, uploadASNStart); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d file(s) failed to upload", failed, len(args))
+	}
+	if warned > 0 {
+		return fmt.Errorf("%d of %d file(s) uploaded with warnings", warned, len(args))
+	}
+	return nil
+}
+
+// followUploads shows the consumption of uploaded files until all are done
+func followUploads(client *api.Client, stream *api.StatusStream, files, taskIDs []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	tasks := make(map[string]string, len(taskIDs))
+	for i, id := range taskIDs {
+		tasks[id] = filepath.Base(files[i])
+	}
+	failed, err := followTasks(ctx, client, stream, tasks, os.Stderr)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d file(s) failed to be consumed", failed, len(files))
+	}
+	return nil
+}
+
+// spoolStdin saves stdin to a temporary file with the given name, so it can
+// be uploaded like any other file
+func spoolStdin(name string) (string, func(), error) {
+	if isTerminal(os.Stdin) {
+		return "", nil, fmt.Errorf("- uploads from stdin, pipe a file into the command")
+	}
+	name = filepath.Base(name)
+	if name == "." || name == string(filepath.Separator) {
+		return "", nil, fmt.Errorf("invalid --filename")
+	}
+
+	dir, err := os.MkdirTemp("", "paperless-stdin-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	n, err := io.Copy(f, os.Stdin)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("reading stdin: %w", err)
+	}
+	if n == 0 {
+		cleanup()
+		return "", nil, fmt.Errorf("no data on stdin")
+	}
+	return path, cleanup, nil
+}
+
+// uploadOutcome is what --wait reports for an uploaded file
+type uploadOutcome struct {
+	File       string `json:"file"`
+	TaskID     string `json:"task_id"`
+	DocumentID int    `json:"document_id,omitempty"`
+	Title      string `json:"title,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// waitForUploads waits for each uploaded file to be consumed and prints the
+// document it became, or why it failed
+func waitForUploads(client *api.Client, files, taskIDs []string) error {
+	if !isQuiet() && !isJSON() && !uploadProgress {
+		fmt.Fprintf(os.Stderr, "Waiting for %d document(s) to be consumed...\n", len(taskIDs))
+	}
+
+	var failed int
+	for i, taskID := range taskIDs {
+		outcome := uploadOutcome{File: files[i], TaskID: taskID}
+		docID, err := waitForDocument(client, taskID, uploadWaitTimeout, uploadWaitInterval)
+		if err == nil {
+			outcome.DocumentID = docID
+			if doc, err := client.GetDocument(docID); err == nil {
+				outcome.Title = doc.Title
+			}
+		} else {
+			outcome.Error = err.Error()
+			failed++
+		}
+
+		switch {
+		case isJSON():
+			printJSON(outcome)
+		case outcome.Error != "":
+			fmt.Fprintf(os.Stderr, "%s: %s\n", filepath.Base(outcome.File), outcome.Error)
+		default:
+			fmt.Printf("%s: document %d: %s\n", filepath.Base(outcome.File), outcome.DocumentID, outcome.Title)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d file(s) were not consumed", failed, len(files))
+	}
+	return nil
+}
+
+// handleConsumedFiles waits for each uploaded file to be consumed and then
+// deletes or moves it. Files that fail to be consumed are kept.
+func handleConsumedFiles(client *api.Client, files, taskIDs []string) error {
+	if !isQuiet() && !uploadWait && !uploadProgress {
+		fmt.Fprintf(os.Stderr, "Waiting for %d document(s) to be consumed...\n", len(taskIDs))
+	}
+
+	var failed int
+	for i, taskID := range taskIDs {
+		_, err := waitForDocument(client, taskID, uploadWaitTimeout, uploadWaitInterval)
+		if err == nil {
+			err = uploadSourcePolicies.consumed.apply(files[i])
+		} else {
+			err = fmt.Errorf("%w, kept", err)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", filepath.Base(files[i]), err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d file(s) were kept", failed, len(files))
+	}
+	return nil
+}
+
+// assignASNSequence waits for each uploaded file to be consumed and gives
+// the documents consecutive archive serial numbers in upload order
+func assignASNSequence(client *api.Client, files, taskIDs []string, start int) error {
+	if !isQuiet() {
+		uploaded := 0
+		for _, taskID := range taskIDs {
+			if taskID != "" {
+				uploaded++
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Waiting for %d document(s) to be consumed...\n", uploaded)
+	}
+
+	var failed int
+	for i, taskID := range taskIDs {
+		asn := start + i
+		if taskID == "" {
+			// Not uploaded, reported already
+			continue
+		}
+		docID, err := waitForDocument(client, taskID, uploadWaitTimeout, uploadWaitInterval)
+		if err == nil {
+			_, err = client.UpdateDocument(docID, map[string]interface{}{"archive_serial_number": asn})
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ASN %d for %s: %v\n", asn, filepath.Base(files[i]), err)
+			failed++
+			continue
+		}
+		if !isQuiet() && !isJSON() {
+			fmt.Printf("Assigned ASN %d to document %d (%s)\n", asn, docID, filepath.Base(files[i]))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to assign %d ASN(s)", failed)
+	}
+	return nil
+}
+
+// resolveUploadParams looks up upload metadata given by name or ID, creating
+// missing tags, correspondents and types with --create-missing. Storage
+// paths may also be given by their path template.
+func resolveUploadParams(client *api.Client, correspondent, docType, storagePath string, tags []string) (api.UploadParams, error) {
+	var params api.UploadParams
+
+	if correspondent != "" {
+		id, err := resolveCorrespondent(client, correspondent)
+		if err != nil {
+			return params, err
+		}
+		params.Correspondent = &id
+	}
+
+	if docType != "" {
+		id, err := resolveDocumentType(client, docType)
+		if err != nil {
+			return params, err
+		}
+		params.DocumentType = &id
+	}
+
+	if storagePath != "" {
+		id, err := resolveStoragePath(client, storagePath)
+		if err != nil {
+			return params, err
+		}
+		params.StoragePath = &id
+	}
+
+	for _, tagArg := range tags {
+		id, err := resolveTag(client, tagArg)
+		if err != nil {
+			return params, err
+		}
+		params.Tags = append(params.Tags, id)
+	}
+
+	return params, nil
+}
+
+// resolveStoragePath finds a storage path by ID, name or path template
+func resolveStoragePath(client *api.Client, arg string) (int, error) {
+	if id, err := strconv.Atoi(arg); err == nil {
+		return id, nil
+	}
+	if sp, err := client.FindStoragePathByName(arg); err == nil {
+		return sp.ID, nil
+	}
+
+	paths, err := client.ListStoragePaths(api.ListParams{})
+	if err != nil {
+		return 0, err
+	}
+	for _, sp := range paths.Results {
+		if sp.Path == arg {
+			return sp.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("storage path not found: %s", arg)
+}
+
+// uploadFile uploads one file, titled after the file name unless
+// params.Title is set, and returns the consumption task ID
+func uploadFile(client *api.Client, filePath string, params api.UploadParams) (string, error) {
+	taskID, err := sendFile(client, filePath, params)
+	if err != nil && taskID == "" {
+		err = uploadFailed(filePath, err)
+	}
+	return taskID, err
+}
+
+// uploadFailed applies the --on-failure policy to a file whose upload
+// failed for good
+func uploadFailed(filePath string, err error) error {
+	if policyErr := uploadSourcePolicies.failure.apply(filePath); policyErr != nil {
+		err = fmt.Errorf("%w (and %s failed: %v)", err, uploadSourcePolicies.failure, policyErr)
+	}
+	return err
+}
+
+// sendFile is uploadFile without the --on-failure policy, leaving a file
+// that failed to upload in place for a retry
+func sendFile(client *api.Client, filePath string, params api.UploadParams) (string, error) {
+	if params.Title == "" {
+		// Use filename without extension as title
+		params.Title = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	}
+	params.Tags = uploadImplications.apply(params.Tags)
+
+	// A progress bar shows the file instead
+	if !isQuiet() && params.Progress == nil {
+		fmt.Fprintf(os.Stderr, "Uploading %s...\n", filepath.Base(filePath))
+	}
+
+	result, err := client.Upload(filePath, params)
+	if err != nil {
+		return "", fmt.Errorf("upload failed for %s: %w", filePath, err)
+	}
+
+	taskID := result.TaskID
+
+	// --wait prints one object per file once it's consumed
+	if isJSON() && !uploadWait {
+		out := map[string]interface{}{"file": filePath, "task_id": taskID}
+		if len(result.Warnings) > 0 {
+			out["warnings"] = result.Warnings
+		}
+		printJSON(out)
+	} else if !isQuiet() {
+		printAbove(os.Stdout, "Uploaded %s (task: %s)\n", filepath.Base(filePath), taskID)
+	}
+	for _, warning := range result.Warnings {
+		printAbove(os.Stderr, "Warning: %s: %s\n", filepath.Base(filePath), warning)
+	}
+	// The file is uploaded either way, so the steps after it only warn
+	var noteErr error
+	if uploadAttachmentsNote {
+		if err := noteXMLAttachments(client, filePath, taskID); err != nil {
+			noteErr = fmt.Errorf("uploaded %s but storing its attachments failed: %w", filePath, err)
+		}
+	}
+	if err := uploadSourcePolicies.success.apply(filePath); err != nil {
+		return taskID, errors.Join(noteErr, fmt.Errorf("uploaded %s but %s failed: %w", filePath, uploadSourcePolicies.success, err))
+	}
+
+	return taskID, noteErr
+}
+
+// noteXMLAttachments waits for an uploaded PDF to be consumed and stores its
+// embedded XML attachments as document notes
+func noteXMLAttachments(client *api.Client, filePath, taskID string) error {
+	if !strings.EqualFold(filepath.Ext(filePath), ".pdf") {
+		return nil
+	}
+
+	attachments, err := readPDFAttachments(filePath)
+	if err != nil {
+		return err
+	}
+
+	var xmlAttachments []pdfAttachment
+	for _, a := range attachments {
+		if a.isXML() {
+			xmlAttachments = append(xmlAttachments, a)
+		}
+	}
+	if len(xmlAttachments) == 0 {
+		return nil
+	}
+
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Waiting for %s to be consumed...\n", filepath.Base(filePath))
+	}
+	docID, err := waitForDocument(client, taskID, uploadWaitTimeout, uploadWaitInterval)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range xmlAttachments {
+		note := fmt.Sprintf("Embedded attachment %s:\n\n%s", a.Name, a.data)
+		if err := client.AddNote(docID, note); err != nil {
+			return err
+		}
+		if !isQuiet() {
+			fmt.Printf("Added %s as note to document %d\n", a.Name, docID)
+		}
+	}
+
+	return nil
+}
+
+func runDocsDownload(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	// --dir has its own handler, which lets the current download finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if downloadZip != "" {
+		ids, ranged, err := parseDocumentIDRanges(args)
+		if err != nil {
+			return err
+		}
+		if ids, err = dropMissingDocuments(client, ids, ranged); err != nil {
+			return err
+		}
+		if len(ids) == 0 && len(args) > 0 && len(downloadIDs) == 0 {
+			return fmt.Errorf("none of the documents exist")
+		}
+		return downloadZipArchive(ctx, client, append(downloadIDs, ids...))
+	}
+	if len(downloadIDs) > 0 {
+		return fmt.Errorf("--ids requires --zip")
+	}
+	if downloadResume && downloadDir == "" {
+		return fmt.Errorf("--resume requires --dir")
+	}
+	if downloadDir != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("--dir downloads the documents matching the filters, not given IDs")
+		}
+		var stamper string
+		if downloadStamp != "" {
+			if stamper, err = pdfTool("--stamp"); err != nil {
+				return err
+			}
+		}
+		stop()
+		return downloadIntoDir(cmd, client, stamper)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("expected a document ID")
+	}
+
+	ids, ranged, err := parseDocumentIDRanges(args)
+	if err != nil {
+		return err
+	}
+	if len(ids) > 1 && downloadOutput != "" {
+		return fmt.Errorf("-o names a single file; use --name-template or --zip for several documents")
+	}
+
+	var stamper string
+	if downloadStamp != "" {
+		if stamper, err = pdfTool("--stamp"); err != nil {
+			return err
+		}
+	}
+
+	taken := make(map[string]bool)
+	for _, id := range ids {
+		if err := downloadDocument(ctx, client, id, stamper, taken); err != nil {
+			if skipMissing(id, ranged, err) {
+				continue
+			}
+			if len(ids) > 1 {
+				return fmt.Errorf("document %d: %w", id, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// downloadDocument saves a document as the download flags say, stamping it
+// with stamper if set. A name in taken, already used by this run, gets the
+// document ID added.
+func downloadDocument(ctx context.Context, client *api.Client, id int, stamper string, taken map[string]bool) error {
+	// The name is resolved first, so a bad template fails before downloading
+	var templated string
+	if downloadNameTmpl != "" {
+		tmpl, err := parseNameTemplate(downloadNameTmpl)
+		if err != nil {
+			return err
+		}
+		doc, err := client.GetDocument(id)
+		if err != nil {
+			return err
+		}
+		if templated, err = expandNameTemplate(client, tmpl, doc); err != nil {
+			return err
+		}
+	}
+
+	if downloadBoth {
+		return downloadBothVariants(ctx, client, id, stamper, templated)
+	}
+
+	outputPath, info, err := downloadToFile(ctx, client, id, downloadOriginal, func(filename string) string {
+		if downloadOutput != "" {
+			return downloadOutput
+		}
+		name := fmt.Sprintf("document_%d.pdf", id)
+		if templated != "" {
+			name = withExtension(templated, filename)
+		} else if filename != "" {
+			name = filename
+		}
+		if taken[name] {
+			name = suffixedPath(name, fmt.Sprintf(" (%d)", id))
+		}
+		taken[name] = true
+		return name
+	})
+	if err != nil {
+		return err
+	}
+	if stamper != "" {
+		if err := stampDownload(stamper, outputPath, downloadStamp, id); err != nil {
+			return err
+		}
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Downloaded to %s (%d bytes)\n", outputPath, info.Written)
+	}
+
+	if downloadMetadata {
+		doc, err := client.GetDocument(id)
+		if err != nil {
+			return err
+		}
+		return writeDocumentMetadata(doc, outputPath)
+	}
+
+	return nil
+}
+
+// downloadBothVariants saves the original and the archived file side by side
+// using "_original" and "_archive" suffixes, stamping them with stamper if set.
+// They are named after -o or the templated name if given.
+func downloadBothVariants(ctx context.Context, client *api.Client, id int, stamper, templated string) error {
+	doc, err := client.GetDocument(id)
+	if err != nil {
+		return err
+	}
+
+	variants := []struct {
+		suffix   string
+		original bool
+	}{
+		{"original", true},
+		{"archive", false},
+	}
+
+	var basePath string
+	for _, v := range variants {
+		if !v.original && doc.ArchivedFileName == "" {
+			if !isQuiet() {
+				fmt.Fprintf(os.Stderr, "Document %d has no archived version, skipping\n", id)
+			}
+			continue
+		}
+
+		outputPath, info, err := downloadToFile(ctx, client, id, v.original, func(filename string) string {
+			base := downloadOutput
+			if base == "" && templated != "" {
+				base = withExtension(templated, filename)
+			} else if base == "" {
+				base = filename
+				if base == "" {
+					base = fmt.Sprintf("document_%d.pdf", id)
+				}
+			} else if ext := filepath.Ext(filename); ext != "" {
+				// The original may not be a PDF, so keep the server's extension
+				base = strings.TrimSuffix(base, filepath.Ext(base)) + ext
+			}
+			if basePath == "" {
+				basePath = base
+			}
+			return suffixedPath(base, "_"+v.suffix)
+		})
+		if err != nil {
+			return err
+		}
+		if stamper != "" {
+			if err := stampDownload(stamper, outputPath, downloadStamp, id); err != nil {
+				return err
+			}
+		}
+
+		if !isQuiet() && !isJSON() {
+			printAbove(os.Stdout, "Downloaded %s to %s (%d bytes)\n", v.suffix, outputPath, info.Written)
+		}
+	}
+
+	if downloadMetadata {
+		return writeDocumentMetadata(doc, basePath)
+	}
+
+	return nil
+}
+
+// downloadToFile streams a document into a temporary file and renames it to
+// the path returned by pathFor, which receives the server's filename. A failed
+// or interrupted download never leaves a truncated file behind.
+func downloadToFile(ctx context.Context, client *api.Client, id int, original bool, pathFor func(filename string) string) (string, *api.DownloadInfo, error) {
+	return saveDownload(ctx, fmt.Sprintf("Document %d", id), pathFor, func(ctx context.Context, w io.Writer, progress api.ProgressFunc) (*api.DownloadInfo, error) {
+		return client.DownloadDocumentTo(ctx, id, w, api.DownloadOptions{
+			Original: original,
+			Progress: progress,
+		})
+	})
+}
+
+// saveDownload runs fetch into a temporary file next to the output and moves
+// it to the path returned by pathFor once it's complete. Cancelling ctx stops
+// the download and removes the temporary file.
+func saveDownload(ctx context.Context, label string, pathFor func(filename string) string, fetch func(ctx context.Context, w io.Writer, progress api.ProgressFunc) (*api.DownloadInfo, error)) (string, *api.DownloadInfo, error) {
+	// Next to the output, so the rename doesn't cross file systems
+	tmpDir := filepath.Dir(firstNonEmpty(downloadOutput, downloadZip, "."))
+	if downloadDir != "" {
+		tmpDir = downloadDir
+	}
+	tmp, err := os.CreateTemp(tmpDir, ".paperless-download-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to write file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	progress, done := newProgressBar(label)
+	info, err := fetch(ctx, tmp, progress)
+	done()
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write file: %w", closeErr)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	outputPath := pathFor(info.Filename)
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return "", nil, fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return "", nil, fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), outputPath); err != nil {
+		return "", nil, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return outputPath, info, nil
+}
+
+// downloadZipArchive saves the given documents as one zip archive
+func downloadZipArchive(ctx context.Context, client *api.Client, ids []int) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("no documents given; pass IDs with --ids")
+	}
+
+	content := api.BulkDownloadArchive
+	switch {
+	case downloadBoth:
+		content = api.BulkDownloadBoth
+	case downloadOriginal:
+		content = api.BulkDownloadOriginals
+	}
+
+	outputPath, info, err := saveDownload(ctx, fmt.Sprintf("%d documents", len(ids)), func(string) string {
+		return downloadZip
+	}, func(ctx context.Context, w io.Writer, progress api.ProgressFunc) (*api.DownloadInfo, error) {
+		return client.BulkDownloadTo(ctx, ids, w, api.BulkDownloadOptions{
+			Content:  content,
+			Progress: progress,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(map[string]interface{}{"file": outputPath, "documents": ids, "bytes": info.Written})
+	}
+	if !isQuiet() {
+		fmt.Printf("Downloaded %d document(s) to %s (%d bytes)\n", len(ids), outputPath, info.Written)
+	}
+
+	return nil
+}
+
+// writeDocumentMetadata stores the document's metadata as JSON next to path
+func writeDocumentMetadata(doc *api.Document, path string) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	metaPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".json"
+	if err := os.WriteFile(metaPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+
+	if !isQuiet() && !isJSON() {
+		printAbove(os.Stdout, "Wrote metadata to %s\n", metaPath)
+	}
+
+	return nil
+}
+
+// suffixedPath inserts suffix between a file name and its extension
+func suffixedPath(path, suffix string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + suffix + ext
+}
+
+func runDocsEdit(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ids, err := parseDocumentIDs(args)
+	if err != nil {
+		return err
+	}
+
+	editFields = append(editFields, editSetFields...)
+
+	if editCreated != "" {
+		if _, err := time.Parse("2006-01-02", editCreated); err != nil {
+			return fmt.Errorf("invalid --created date %q, use YYYY-MM-DD", editCreated)
+		}
+	}
+
+	// The content is read once, as stdin can't be read for every document
+	var content *string
+	if editContentFile != "" {
+		var data []byte
+		if editContentFile == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(editContentFile)
+		}
+		if err != nil {
+			return fmt.Errorf("reading content: %w", err)
+		}
+		text := string(data)
+		content = &text
+	}
+
+	updated := make([]*api.Document, 0, len(ids))
+	for _, id := range ids {
+		doc, err := editDocument(client, id, content)
+		if err != nil {
+			if len(ids) > 1 {
+				return fmt.Errorf("document %d: %w", id, err)
+			}
+			return err
+		}
+		updated = append(updated, doc)
+		if !isJSON() && !isQuiet() {
+			fmt.Printf("Updated document %d\n", id)
+		}
+	}
+
+	if isJSON() {
+		if len(updated) == 1 {
+			return printJSON(updated[0])
+		}
+		return printJSON(updated)
+	}
+	return nil
+}
+
+// editDocument applies the edit flags to a document, replacing its content
+// unless content is nil
+func editDocument(client *api.Client, id int, content *string) (*api.Document, error) {
+	// Get current document to modify tags
+	doc, err := client.GetDocument(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+
+	if editTitle != "" {
+		updates["title"] = editTitle
+	}
+
+	if editCorrespondent != "" {
+		if editCorrespondent == "-" || editCorrespondent == "none" {
+			updates["correspondent"] = nil
+		} else {
+			corrID, err := resolveCorrespondent(client, editCorrespondent)
+			if err != nil {
+				return nil, err
+			}
+			updates["correspondent"] = corrID
+		}
+	}
+
+	if editDocType != "" {
+		if editDocType == "-" || editDocType == "none" {
+			updates["document_type"] = nil
+		} else {
+			dtID, err := resolveDocumentType(client, editDocType)
+			if err != nil {
+				return nil, err
+			}
+			updates["document_type"] = dtID
+		}
+	}
+
+	if editASN > 0 {
+		updates["archive_serial_number"] = editASN
+	}
+
+	if editCreated != "" {
+		updates["created_date"] = editCreated
+	}
+
+	if editStoragePath != "" {
+		if editStoragePath == "-" || editStoragePath == "none" {
+			updates["storage_path"] = nil
+		} else {
+			spID, err := resolveStoragePath(client, editStoragePath)
+			if err != nil {
+				return nil, err
+			}
+			updates["storage_path"] = spID
+		}
+	}
+
+	if content != nil {
+		updates["content"] = *content
+	}
+
+	// Handle tag modifications
+	if len(editAddTags) > 0 || len(editRemoveTags) > 0 {
+		tags := make(map[int]bool)
+		for _, t := range doc.Tags {
+			tags[t] = true
+		}
+
+		// Add tags
+		for _, tagArg := range editAddTags {
+			tagID, err := resolveTag(client, tagArg)
+			if err != nil {
+				return nil, err
+			}
+			tags[tagID] = true
+		}
+
+		// Remove tags
+		for _, tagArg := range editRemoveTags {
 			if tagID, err := strconv.Atoi(tagArg); err == nil {
 				delete(tags, tagID)
 			} else {
@@ -564,39 +1944,93 @@ func runDocsEdit(cmd *cobra.Command, args []string) error {
 		updates["tags"] = newTags
 	}
 
-	if len(updates) == 0 {
-		return fmt.Errorf("no changes specified")
-	}
+	// Handle custom fields
+	if len(editFields) > 0 || len(editRemoveFields) > 0 {
+		values, err := parseFieldAssignments(client, editFields)
+		if err != nil {
+			return nil, err
+		}
+		fields := api.MergeCustomFields(doc.CustomFields, values)
 
-	updatedDoc, err := client.UpdateDocument(id, updates)
-	if err != nil {
-		return err
+		for _, fieldArg := range editRemoveFields {
+			field, err := resolveCustomField(client, fieldArg)
+			if err != nil {
+				return nil, err
+			}
+			kept := fields[:0]
+			for _, f := range fields {
+				if f.Field != field.ID {
+					kept = append(kept, f)
+				}
+			}
+			fields = kept
+		}
+		updates["custom_fields"] = fields
 	}
 
-	if isJSON() {
-		return printJSON(updatedDoc)
+	owner, err := editOwnership.resolve(client)
+	if err != nil {
+		return nil, err
 	}
+	maps.Copy(updates, owner.updates(doc.Permissions, false))
 
-	if !isQuiet() {
-		fmt.Printf("Updated document %d\n", id)
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("no changes specified")
 	}
 
-	return nil
+	return client.UpdateDocument(id, updates)
 }
 
 func runDocsDelete(cmd *cobra.Command, args []string) error {
+	filtered := deleteQuery != "" || len(deleteTags) > 0 || deleteCorrespondent != "" || deleteDocType != ""
+	switch {
+	case len(args) > 0 && filtered:
+		return fmt.Errorf("give either document IDs or filters, not both")
+	case len(args) == 0 && !filtered:
+		return fmt.Errorf("no documents given; pass IDs or use --query, --tag, --correspondent or --type")
+	}
+
 	client, err := getClient()
 	if err != nil {
 		return err
 	}
 
-	var ids []int
-	for _, arg := range args {
-		id, err := strconv.Atoi(arg)
+	if filtered {
+		return deleteMatchingDocuments(client)
+	}
+
+	ids, ranged, err := parseDocumentIDRanges(args)
+	if err != nil {
+		return err
+	}
+	if ids, err = dropMissingDocuments(client, ids, ranged); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		fmt.Println("No documents found")
+		return nil
+	}
+
+	if !overrideHold {
+		hold, err := loadLegalHold(client)
 		if err != nil {
-			return fmt.Errorf("invalid document ID: %s", arg)
+			return err
+		}
+		if hold.active() {
+			var held []int
+			for _, id := range ids {
+				doc, err := client.GetDocument(id)
+				if err != nil {
+					return err
+				}
+				if hold.holds(*doc) {
+					held = append(held, id)
+				}
+			}
+			if len(held) > 0 {
+				return fmt.Errorf("document(s) %s on legal hold (%s); pass --override-hold to delete anyway", joinInts(held, ", "), hold.name)
+			}
 		}
-		ids = append(ids, id)
 	}
 
 	if !deleteForce {
@@ -607,6 +2041,61 @@ func runDocsDelete(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	return deleteDocuments(client, ids)
+}
+
+// deleteMatchingDocuments deletes the documents matching the delete filters
+// after listing them and having the user type their number
+func deleteMatchingDocuments(client *api.Client) error {
+	docs, err := listAllDocuments(client, api.DocumentListParams{
+		Query:         deleteQuery,
+		Tags:          deleteTags,
+		Correspondent: deleteCorrespondent,
+		DocumentType:  deleteDocType,
+	})
+	if err != nil {
+		return err
+	}
+
+	hold, err := loadLegalHold(client)
+	if err != nil {
+		return err
+	}
+	if !overrideHold {
+		before := len(docs)
+		docs = slices.DeleteFunc(docs, hold.holds)
+		hold.reportSkipped(before - len(docs))
+	}
+	if len(docs) == 0 {
+		fmt.Println("No documents found")
+		return nil
+	}
+
+	if !deleteForce {
+		w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTITLE")
+		for _, doc := range docs {
+			fmt.Fprintf(w, "%d\t%s\n", doc.ID, truncate(doc.Title, 60))
+		}
+		w.Flush()
+
+		count := strconv.Itoa(len(docs))
+		if !confirmTyped(fmt.Sprintf("\nDelete these %s document(s)?", count), count) {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	ids := make([]int, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return deleteDocuments(client, ids)
+}
+
+// deleteDocuments deletes the documents one by one, stopping at the first
+// failure
+func deleteDocuments(client *api.Client, ids []int) error {
 	for _, id := range ids {
 		if err := client.DeleteDocument(id); err != nil {
 			return fmt.Errorf("failed to delete document %d: %w", id, err)
@@ -0,0 +1,390 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/ledongthuc/pdf"
+	"github.com/spf13/cobra"
+)
+
+var dossierCmd = &cobra.Command{
+	Use:   "dossier",
+	Short: "Combine documents into one PDF with a table of contents",
+	Long: `Download all matching documents, order them by date and merge them into a
+single PDF, e.g. to hand over everything about a case or a year's taxes.
+
+The dossier starts with a generated table of contents listing each document's
+date, title and first page, and has a bookmark per document so viewers can
+jump between them. Archived versions are used; documents whose original isn't
+a PDF and that have no archived version are left out with a warning.
+
+Merging needs qpdf or pdftk to be installed.
+
+Example:
+  paperless dossier --query "insurance" -o insurance.pdf
+  paperless dossier --tag taxes-2024 --title "Taxes 2024" -o taxes.pdf
+  paperless dossier --correspondent "ACME" --tag contract`,
+	RunE: runDossier,
+}
+
+var (
+	dossierQuery         string
+	dossierTags          []string
+	dossierCorrespondent string
+	dossierDocType       string
+	dossierOutput        string
+	dossierTitle         string
+)
+
+// tocEntriesPerPage is how many documents a table of contents page lists
+const tocEntriesPerPage = 40
+
+func init() {
+	rootCmd.AddCommand(dossierCmd)
+
+	dossierCmd.Flags().StringVar(&dossierQuery, "query", "", "search query")
+	dossierCmd.RegisterFlagCompletionFunc("query", completeQuery)
+	dossierCmd.Flags().StringArrayVar(&dossierTags, "tag", nil, "filter by tag (repeatable)")
+	dossierCmd.Flags().StringVar(&dossierCorrespondent, "correspondent", "", "filter by correspondent")
+	dossierCmd.Flags().StringVar(&dossierDocType, "type", "", "filter by document type")
+	dossierCmd.Flags().StringVarP(&dossierOutput, "output", "o", "dossier.pdf", "output path")
+	dossierCmd.Flags().StringVar(&dossierTitle, "title", "Dossier", "heading of the table of contents")
+}
+
+// dossierEntry is one document of a dossier
+type dossierEntry struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Date  string `json:"date"`
+	Page  int    `json:"page"`
+	Pages int    `json:"pages"`
+
+	path     string
+	original bool
+}
+
+// bookmark is an outline entry pointing at a page, starting at 1
+type bookmark struct {
+	Title string
+	Page  int
+}
+
+// tocPages builds the table of contents for entries, whose page numbers
+// must already count the contents pages
+func tocPages(title string, entries []dossierEntry) []byte {
+	pw := newPDFWriter()
+
+	pages := (len(entries) + tocEntriesPerPage - 1) / tocEntriesPerPage
+	if pages == 0 {
+		pages = 1
+	}
+
+	// Objects 1-3 are the catalog, page tree and fonts, then two per page
+	kids := make([]string, pages)
+	for i := range kids {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	pw.object("<< /Type /Catalog /Pages 2 0 R >>")
+	pw.object("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pages)
+	pw.object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	pw.object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	// A4 with 2cm margins; Helvetica digits are 0.556 em wide, which right
+	// aligns the page numbers
+	for p := 0; p < pages; p++ {
+		var content strings.Builder
+		y := 770.0
+		if p == 0 {
+			fmt.Fprintf(&content, "BT /F2 18 Tf 56 %.0f Td %s Tj ET\n", y, pdfText(title))
+			y -= 40
+		}
+		end := min((p+1)*tocEntriesPerPage, len(entries))
+		for _, e := range entries[p*tocEntriesPerPage : end] {
+			number := strconv.Itoa(e.Page)
+			x := 539 - float64(len(number))*0.556*10
+			fmt.Fprintf(&content, "BT /F1 10 Tf 56 %.0f Td %s Tj ET\n", y, pdfText(e.Date))
+			fmt.Fprintf(&content, "BT /F1 10 Tf 126 %.0f Td %s Tj ET\n", y, pdfText(truncate(e.Title, 70)))
+			fmt.Fprintf(&content, "BT /F1 10 Tf %.2f %.0f Td %s Tj ET\n", x, y, pdfText(number))
+			y -= 17
+		}
+
+		pw.object("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", 6+2*p)
+		pw.object("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String())
+	}
+
+	return pw.bytes()
+}
+
+// countPages returns the number of pages of the PDF at path
+func countPages(path string) (int, error) {
+	f, r, err := pdf.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return r.NumPage(), nil
+}
+
+// mergePDFs concatenates the PDFs in paths into output
+func mergePDFs(tool string, paths []string, output string) error {
+	var err error
+	if tool == "qpdf" {
+		args := append(append([]string{"--empty", "--pages"}, paths...), "--", output)
+		_, err = runPDFTool("qpdf", args...)
+	} else {
+		args := append(append([]string(nil), paths...), "cat", "output", output)
+		_, err = runPDFTool("pdftk", args...)
+	}
+	return err
+}
+
+// addBookmarks writes a copy of the PDF at path with the bookmarks as its
+// outline to output
+func addBookmarks(tool, path, output string, marks []bookmark) error {
+	dir := filepath.Dir(output)
+	if tool == "pdftk" {
+		var info strings.Builder
+		for _, m := range marks {
+			fmt.Fprintf(&info, "BookmarkBegin\nBookmarkTitle: %s\nBookmarkLevel: 1\nBookmarkPageNumber: %d\n",
+				strings.Join(strings.Fields(m.Title), " "), m.Page)
+		}
+		infoPath := filepath.Join(dir, "bookmarks.txt")
+		if err := os.WriteFile(infoPath, []byte(info.String()), 0644); err != nil {
+			return err
+		}
+		_, err := runPDFTool("pdftk", path, "update_info_utf8", infoPath, "output", output)
+		return err
+	}
+
+	// qpdf can't add bookmarks directly, but can apply objects in its JSON
+	// format: the outline items plus the catalog pointing at them
+	out, err := runPDFTool("qpdf", "--json=2", "--json-key=pages", "--json-key=qpdf", path)
+	if err != nil {
+		return err
+	}
+	var doc struct {
+		Pages []struct {
+			Object string `json:"object"`
+		} `json:"pages"`
+		QPDF []json.RawMessage `json:"qpdf"`
+	}
+	if err := json.Unmarshal(out, &doc); err != nil || len(doc.QPDF) != 2 {
+		return fmt.Errorf("failed to read qpdf's JSON output, bookmarks need qpdf 11 or newer")
+	}
+	var header map[string]any
+	var objects map[string]json.RawMessage
+	if err := json.Unmarshal(doc.QPDF[0], &header); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(doc.QPDF[1], &objects); err != nil {
+		return err
+	}
+
+	var trailer struct {
+		Value struct {
+			Root string `json:"/Root"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(objects["trailer"], &trailer); err != nil || trailer.Value.Root == "" {
+		return fmt.Errorf("PDF has no catalog")
+	}
+	var root struct {
+		Value map[string]any `json:"value"`
+	}
+	if err := json.Unmarshal(objects["obj:"+trailer.Value.Root], &root); err != nil || root.Value == nil {
+		return fmt.Errorf("PDF has no catalog")
+	}
+
+	maxID, _ := header["maxobjectid"].(float64)
+	ref := func(n int) string { return fmt.Sprintf("%d 0 R", n) }
+	outlines := int(maxID) + 1
+	first, last := outlines+1, outlines+len(marks)
+
+	update := map[string]any{}
+	root.Value["/Outlines"] = ref(outlines)
+	root.Value["/PageMode"] = "/UseOutlines"
+	update["obj:"+trailer.Value.Root] = map[string]any{"value": root.Value}
+	update["obj:"+ref(outlines)] = map[string]any{"value": map[string]any{
+		"/Type": "/Outlines", "/First": ref(first), "/Last": ref(last), "/Count": len(marks),
+	}}
+	for i, m := range marks {
+		if m.Page < 1 || m.Page > len(doc.Pages) {
+			return fmt.Errorf("bookmark %q points at missing page %d", m.Title, m.Page)
+		}
+		item := map[string]any{
+			"/Title":  "u:" + m.Title,
+			"/Parent": ref(outlines),
+			"/Dest":   []any{doc.Pages[m.Page-1].Object, "/Fit"},
+		}
+		if i > 0 {
+			item["/Prev"] = ref(first + i - 1)
+		}
+		if i < len(marks)-1 {
+			item["/Next"] = ref(first + i + 1)
+		}
+		update["obj:"+ref(first+i)] = map[string]any{"value": item}
+	}
+
+	data, err := json.Marshal(map[string]any{"qpdf": []any{header, update}})
+	if err != nil {
+		return err
+	}
+	updatePath := filepath.Join(dir, "bookmarks.json")
+	if err := os.WriteFile(updatePath, data, 0644); err != nil {
+		return err
+	}
+	_, err = runPDFTool("qpdf", "--update-from-json="+updatePath, path, output)
+	return err
+}
+
+// listAllDocuments returns every document matching params, fetched by ID
+// so documents added meanwhile don't shift the pages
+func listAllDocuments(client *api.Client, params api.DocumentListParams) ([]api.Document, error) {
+	params.Limit = 100
+	params.Ordering = "id"
+
+	var docs []api.Document
+	for {
+		result, err := client.ListDocuments(params)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, result.Results...)
+		if result.Next == "" || len(result.Results) == 0 {
+			return docs, nil
+		}
+		params.IDAfter = result.Results[len(result.Results)-1].ID
+	}
+}
+
+func runDossier(cmd *cobra.Command, args []string) error {
+	tool, err := pdfTool("dossier")
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	docs, err := listAllDocuments(client, api.DocumentListParams{
+		Query:         dossierQuery,
+		Tags:          dossierTags,
+		Correspondent: dossierCorrespondent,
+		DocumentType:  dossierDocType,
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		if !docs[i].Created.Equal(docs[j].Created) {
+			return docs[i].Created.Before(docs[j].Created)
+		}
+		return docs[i].ID < docs[j].ID
+	})
+
+	dir, err := os.MkdirTemp("", "paperless-dossier-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	var entries []dossierEntry
+	for _, doc := range docs {
+		if doc.ArchivedFileName == "" && !strings.EqualFold(filepath.Ext(doc.OriginalFileName), ".pdf") {
+			fmt.Fprintf(os.Stderr, "Warning: skipping document %d, %s isn't a PDF\n", doc.ID, doc.OriginalFileName)
+			continue
+		}
+		entries = append(entries, dossierEntry{
+			ID:    doc.ID,
+			Title: doc.Title,
+			Date:  firstNonEmpty(doc.CreatedDate, doc.Created.Format("2006-01-02")),
+			path:  filepath.Join(dir, fmt.Sprintf("%d.pdf", doc.ID)),
+			// Without an archived version the original is a PDF
+			original: doc.ArchivedFileName == "",
+		})
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no PDF documents found")
+	}
+
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Downloading %d document(s)...\n", len(entries))
+	}
+	errs := forEachParallel(entries, maxPar, func(i int, e dossierEntry) error {
+		f, err := os.Create(e.path)
+		if err != nil {
+			return err
+		}
+		_, err = client.DownloadDocumentTo(context.Background(), e.ID, f, api.DownloadOptions{Original: e.original})
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+
+		entries[i].Pages, err = countPages(e.path)
+		return err
+	})
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("document %d: %w", entries[i].ID, err)
+		}
+	}
+
+	// The contents come first, so the documents start after its pages
+	page := (len(entries)+tocEntriesPerPage-1)/tocEntriesPerPage + 1
+	marks := []bookmark{{Title: "Contents", Page: 1}}
+	paths := []string{filepath.Join(dir, "contents.pdf")}
+	for i := range entries {
+		entries[i].Page = page
+		page += entries[i].Pages
+		marks = append(marks, bookmark{Title: entries[i].Date + " " + entries[i].Title, Page: entries[i].Page})
+		paths = append(paths, entries[i].path)
+	}
+	if err := os.WriteFile(paths[0], tocPages(dossierTitle, entries), 0644); err != nil {
+		return err
+	}
+
+	merged := filepath.Join(dir, "merged.pdf")
+	if err := mergePDFs(tool, paths, merged); err != nil {
+		return err
+	}
+	final := filepath.Join(dir, "dossier.pdf")
+	if err := addBookmarks(tool, merged, final, marks); err != nil {
+		return fmt.Errorf("failed to add bookmarks: %w", err)
+	}
+
+	data, err := os.ReadFile(final)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(dossierOutput, data, 0644); err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(map[string]interface{}{
+			"output":    dossierOutput,
+			"pages":     page - 1,
+			"documents": entries,
+		})
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Saved %d document(s) on %d pages to %s\n", len(entries), page-1, dossierOutput)
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"text/template"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// defaultDirTemplate files documents by correspondent and year
+const defaultDirTemplate = `{{or .Correspondent "No correspondent"}}/{{.Year}}/{{.Created}} {{.Title}}`
+
+// Filters selecting the documents downloaded with --dir
+var (
+	downloadDir           string
+	downloadQuery         string
+	downloadTags          []string
+	downloadCorrespondent string
+	downloadDocType       string
+	downloadCreatedAfter  string
+	downloadCreatedBefore string
+	downloadResume        bool
+)
+
+func init() {
+	f := docsDownloadCmd.Flags()
+	f.StringVar(&downloadDir, "dir", "", "download every matching document into this directory tree")
+	f.StringVar(&downloadQuery, "query", "", "with --dir: search query")
+	docsDownloadCmd.RegisterFlagCompletionFunc("query", completeQuery)
+	f.StringArrayVar(&downloadTags, "tag", nil, "with --dir: filter by tag (repeatable)")
+	f.StringVar(&downloadCorrespondent, "correspondent", "", "with --dir: filter by correspondent")
+	f.StringVar(&downloadDocType, "type", "", "with --dir: filter by document type")
+	f.StringVar(&downloadCreatedAfter, "created-after", "", "with --dir: filter by creation date (YYYY-MM-DD, 30d, last month, 2024-Q1, ...)")
+	f.StringVar(&downloadCreatedBefore, "created-before", "", "with --dir: filter by creation date (YYYY-MM-DD, 30d, last month, 2024-Q1, ...)")
+	f.BoolVar(&downloadResume, "resume", false, "with --dir: continue an interrupted run, retrying failed documents")
+	docsDownloadCmd.MarkFlagsMutuallyExclusive("dir", "output", "zip")
+}
+
+// dirDownload is what the JSON output reports per document
+type dirDownload struct {
+	ID    int    `json:"id"`
+	File  string `json:"file,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// downloadIntoDir downloads every document matching the filter flags into
+// downloadDir, named by --name-template or defaultDirTemplate, each with
+// its metadata in a JSON file next to it
+func downloadIntoDir(cmd *cobra.Command, client *api.Client, stamper string) error {
+	params := api.DocumentListParams{
+		Query:         downloadQuery,
+		Tags:          downloadTags,
+		Correspondent: downloadCorrespondent,
+		DocumentType:  downloadDocType,
+		CreatedAfter:  downloadCreatedAfter,
+		CreatedBefore: downloadCreatedBefore,
+		Ordering:      "id",
+	}
+	if err := resolveDateFilters(&params.CreatedAfter, &params.CreatedBefore); err != nil {
+		return err
+	}
+	tmpl, err := parseNameTemplate(firstNonEmpty(downloadNameTmpl, defaultDirTemplate))
+	if err != nil {
+		return err
+	}
+
+	docs, err := listAllDocuments(client, params)
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		if !isQuiet() {
+			fmt.Fprintln(os.Stderr, "No matching documents")
+		}
+		return nil
+	}
+	if err := os.MkdirAll(downloadDir, 0755); err != nil {
+		return err
+	}
+	// --both writes the metadata itself
+	downloadMetadata = true
+
+	options := append([]string{downloadDir, downloadQuery, downloadCorrespondent, downloadDocType,
+		params.CreatedAfter, params.CreatedBefore, downloadNameTmpl, downloadStamp,
+		strconv.FormatBool(downloadOriginal), strconv.FormatBool(downloadBoth)}, downloadTags...)
+	cp := openCheckpoint("download-dir", options, downloadResume)
+
+	// Documents whose names collide get their ID appended. Those downloaded
+	// by an earlier run keep their names, so later ones don't overwrite them.
+	taken := make(map[string]bool)
+	todo := docs
+	if cp.resumed() {
+		retry := cp.retry()
+		todo = nil
+		for i := range docs {
+			if docs[i].ID > cp.LastID || slices.Contains(retry, docs[i].ID) {
+				todo = append(todo, docs[i])
+			} else if _, err := dirBase(client, tmpl, &docs[i], taken); err != nil {
+				return err
+			}
+		}
+		if !isQuiet() && !isJSON() {
+			fmt.Fprintf(os.Stderr, "Resuming after document %d (%d done, %d to retry)\n", cp.LastID, cp.Done, len(retry))
+		}
+	}
+	if !isQuiet() && !isJSON() {
+		fmt.Fprintf(os.Stderr, "Downloading %d document(s) to %s\n", len(todo), downloadDir)
+	}
+
+	sd := handleShutdown()
+	defer sd.release()
+	ctx := sd.context()
+
+	// One bar for the batch replaces those of the single downloads
+	progress, finishProgress := newCountProgressBar("Downloading")
+	defer finishProgress()
+
+	var results []dirDownload
+	failed := 0
+	for i := range todo {
+		if sd.requested() {
+			finishProgress()
+			return interrupted(cmd, "Interrupted after %d of %d document(s), continue with --resume", i, len(todo))
+		}
+		if progress != nil {
+			progress(int64(i), int64(len(todo)))
+		}
+		doc := &todo[i]
+		result := dirDownload{ID: doc.ID}
+
+		path, err := downloadDocIntoDir(ctx, client, doc, tmpl, taken, stamper)
+		if err != nil && ctx.Err() != nil {
+			finishProgress()
+			return interrupted(cmd, "Aborted document %d after %d of %d document(s), continue with --resume", doc.ID, i, len(todo))
+		}
+		if err != nil {
+			result.Error = err.Error()
+			failed++
+			cp.fail(doc.ID)
+			printAbove(os.Stderr, "document %d: %v\n", doc.ID, err)
+		} else {
+			result.File = path
+			cp.succeeded(doc.ID)
+		}
+		results = append(results, result)
+
+		// Retried documents come before LastID and were counted already
+		processed := 1
+		if doc.ID <= cp.LastID {
+			processed = 0
+		}
+		if err := cp.save(max(cp.LastID, doc.ID), processed); err != nil {
+			printAbove(os.Stderr, "Warning: could not save progress: %v\n", err)
+		}
+	}
+	if progress != nil {
+		progress(int64(len(todo)), int64(len(todo)))
+	}
+	finishProgress()
+
+	if isJSON() {
+		printJSON(results)
+	} else if !isQuiet() {
+		fmt.Printf("Downloaded %d document(s) to %s\n", len(todo)-failed, downloadDir)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to download %d document(s), retry them with --resume", failed)
+	}
+	cp.clear()
+	return nil
+}
+
+// dirBase returns the path of a document under downloadDir without the
+// extension and marks it taken, appending the ID if it already was
+func dirBase(client *api.Client, tmpl *template.Template, doc *api.Document, taken map[string]bool) (string, error) {
+	name, err := expandNameTemplate(client, tmpl, doc)
+	if err != nil {
+		return "", err
+	}
+	base := filepath.Join(downloadDir, name)
+	if taken[base] {
+		base = suffixedPath(base, fmt.Sprintf(" (%d)", doc.ID))
+	}
+	taken[base] = true
+	return base, nil
+}
+
+// downloadDocIntoDir saves one document and its metadata under downloadDir
+// and returns the path of the file
+func downloadDocIntoDir(ctx context.Context, client *api.Client, doc *api.Document, tmpl *template.Template, taken map[string]bool, stamper string) (string, error) {
+	base, err := dirBase(client, tmpl, doc, taken)
+	if err != nil {
+		return "", err
+	}
+
+	if downloadBoth {
+		return base, downloadBothVariants(ctx, client, doc.ID, stamper, base)
+	}
+
+	path, info, err := downloadToFile(ctx, client, doc.ID, downloadOriginal, func(filename string) string {
+		return withExtension(base, filename)
+	})
+	if err != nil {
+		return "", err
+	}
+	if stamper != "" {
+		if err := stampDownload(stamper, path, downloadStamp, doc.ID); err != nil {
+			return "", err
+		}
+	}
+	if !isQuiet() && !isJSON() {
+		printAbove(os.Stdout, "Downloaded to %s (%d bytes)\n", path, info.Written)
+	}
+	if err := writeDocumentMetadata(doc, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,31 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setEcho turns the terminal's echo of typed characters on or off
+func setEcho(on bool) error {
+	mode := "-echo"
+	if on {
+		mode = "echo"
+	}
+	c := exec.Command("stty", mode)
+	c.Stdin = os.Stdin
+	return c.Run()
+}
+
+// setKeyMode switches the terminal to reading single keys without echo,
+// rather than whole lines, or back
+func setKeyMode(on bool) error {
+	args := []string{"icanon", "echo"}
+	if on {
+		args = []string{"-icanon", "-echo", "min", "1"}
+	}
+	c := exec.Command("stty", args...)
+	c.Stdin = os.Stdin
+	return c.Run()
+}
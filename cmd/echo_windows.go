@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+	"syscall"
+)
+
+var procSetConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+// Console mode flags: enableLineInput returns input only at Enter,
+// enableEchoInput echoes typed characters
+const (
+	enableLineInput = 0x0002
+	enableEchoInput = 0x0004
+)
+
+// setEcho turns the console's echo of typed characters on or off
+func setEcho(on bool) error {
+	h := syscall.Handle(os.Stdin.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(h, &mode); err != nil {
+		return err
+	}
+	if on {
+		mode |= enableEchoInput
+	} else {
+		mode &^= enableEchoInput
+	}
+	if ok, _, err := procSetConsoleMode.Call(uintptr(h), uintptr(mode)); ok == 0 {
+		return err
+	}
+	return nil
+}
+
+// setKeyMode switches the console to reading single keys without echo,
+// rather than whole lines, or back
+func setKeyMode(on bool) error {
+	h := syscall.Handle(os.Stdin.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(h, &mode); err != nil {
+		return err
+	}
+	if on {
+		mode &^= enableLineInput | enableEchoInput
+	} else {
+		mode |= enableLineInput | enableEchoInput
+	}
+	if ok, _, err := procSetConsoleMode.Call(uintptr(h), uintptr(mode)); ok == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,589 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var fieldsCmd = &cobra.Command{
+	Use:     "custom-fields",
+	Aliases: []string{"fields"},
+	Short:   "Manage custom fields",
+	Long: `Manage custom field definitions.
+
+Values are set on documents with "documents edit --field" and
+"documents set-field".`,
+}
+
+var fieldsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all custom fields",
+	Long: `List all custom fields in Paperless.
+
+Example:
+  paperless custom-fields list
+  paperless custom-fields list --json`,
+	Args: cobra.NoArgs,
+	RunE: runFieldsList,
+}
+
+var fieldsGetCmd = &cobra.Command{
+	Use:   "get <field>",
+	Short: "Get custom field details",
+	Long: `Get details of a custom field, given by name or ID.
+
+Example:
+  paperless custom-fields get "Invoice Number"
+  paperless custom-fields get 3`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFieldsGet,
+}
+
+var fieldsCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a custom field",
+	Long: `Create a custom field.
+
+Types: string, url, date, boolean, integer, float, monetary, documentlink,
+select, longtext.
+
+Example:
+  paperless custom-fields create "Invoice Number" --type string
+  paperless custom-fields create Amount --type monetary --currency EUR
+  paperless custom-fields create Priority --type select --option low --option high`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFieldsCreate,
+}
+
+var fieldsEditCmd = &cobra.Command{
+	Use:   "edit <field>",
+	Short: "Edit a custom field",
+	Long: `Rename a custom field or change its default currency.
+
+Example:
+  paperless custom-fields edit "Invoice Number" --name "Invoice No."
+  paperless custom-fields edit Amount --currency USD`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFieldsEdit,
+}
+
+var fieldsDeleteCmd = &cobra.Command{
+	Use:   "delete <field>",
+	Short: "Delete a custom field",
+	Long: `Delete a custom field. Its values are removed from all documents.
+
+Example:
+  paperless custom-fields delete "Invoice Number"
+  paperless custom-fields delete 3 --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFieldsDelete,
+}
+
+var fieldsOptionsCmd = &cobra.Command{
+	Use:   "options",
+	Short: "Manage the choices of a select field",
+	Long:  `List, add and remove the choices of a select-type custom field.`,
+}
+
+var fieldsOptionsListCmd = &cobra.Command{
+	Use:   "list <field>",
+	Short: "List the choices of a select field",
+	Long: `List the choices of a select field, given by name or ID.
+
+Example:
+  paperless custom-fields options list Priority`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFieldsOptionsList,
+}
+
+var fieldsOptionsAddCmd = &cobra.Command{
+	Use:   "add <field> <label>...",
+	Short: "Add choices to a select field",
+	Long: `Add one or more choices to a select field.
+
+Example:
+  paperless custom-fields options add Priority urgent
+  paperless custom-fields options add "Payment method" "Credit card" PayPal`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runFieldsOptionsAdd,
+}
+
+var fieldsOptionsRemoveCmd = &cobra.Command{
+	Use:   "remove <field> <label|id>...",
+	Short: "Remove choices from a select field",
+	Long: `Remove one or more choices from a select field.
+
+Documents using a choice are listed first and the removal must be
+confirmed, as those documents lose their value for the field.
+
+Example:
+  paperless custom-fields options remove Priority urgent
+  paperless custom-fields options remove Priority urgent --force`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runFieldsOptionsRemove,
+}
+
+var (
+	fieldType     string
+	fieldName     string
+	fieldCurrency string
+	fieldOptions  []string
+	fieldForce    bool
+
+	fieldsOptionsForce bool
+)
+
+func init() {
+	rootCmd.AddCommand(fieldsCmd)
+	fieldsCmd.AddCommand(fieldsListCmd)
+	fieldsCmd.AddCommand(fieldsGetCmd)
+	fieldsCmd.AddCommand(fieldsCreateCmd)
+	fieldsCmd.AddCommand(fieldsEditCmd)
+	fieldsCmd.AddCommand(fieldsDeleteCmd)
+	fieldsCmd.AddCommand(fieldsOptionsCmd)
+	fieldsOptionsCmd.AddCommand(fieldsOptionsListCmd)
+	fieldsOptionsCmd.AddCommand(fieldsOptionsAddCmd)
+	fieldsOptionsCmd.AddCommand(fieldsOptionsRemoveCmd)
+
+	addTableFlags(fieldsListCmd, fieldColumns)
+	addTableFlags(fieldsOptionsListCmd, optionColumns)
+	fieldsCreateCmd.Flags().StringVar(&fieldType, "type", "", "data type (required)")
+	fieldsCreateCmd.Flags().StringVar(&fieldCurrency, "currency", "", "default currency of a monetary field, e.g. EUR")
+	fieldsCreateCmd.Flags().StringArrayVar(&fieldOptions, "option", nil, "choice of a select field (repeatable)")
+	fieldsCreateCmd.MarkFlagRequired("type")
+	fieldsEditCmd.Flags().StringVar(&fieldName, "name", "", "new name")
+	fieldsEditCmd.Flags().StringVar(&fieldCurrency, "currency", "", "new default currency of a monetary field")
+	fieldsDeleteCmd.Flags().BoolVarP(&fieldForce, "force", "f", false, "skip confirmation")
+
+	fieldsOptionsRemoveCmd.Flags().BoolVarP(&fieldsOptionsForce, "force", "f", false, "remove choices in use without confirmation")
+}
+
+// fieldColumns are the columns of the custom fields list
+var fieldColumns = []tableColumn[api.CustomField]{
+	{name: "id", value: func(f api.CustomField) string { return strconv.Itoa(f.ID) }},
+	{name: "name", value: func(f api.CustomField) string { return f.Name }},
+	{name: "type", value: func(f api.CustomField) string { return f.DataType }},
+	{name: "docs", value: func(f api.CustomField) string { return strconv.Itoa(f.DocumentCount) }},
+}
+
+func runFieldsList(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	columns, err := selectColumns(fieldColumns, "id,name,type,docs")
+	if err != nil {
+		return err
+	}
+
+	result, err := client.ListCustomFields(api.ListParams{})
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(result)
+	}
+
+	if len(result.Results) == 0 {
+		fmt.Println("No custom fields found")
+		return nil
+	}
+
+	printTable(columns, result.Results)
+
+	return nil
+}
+
+func runFieldsGet(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	field, err := resolveCustomField(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(field)
+	}
+
+	fmt.Printf("ID:        %d\n", field.ID)
+	fmt.Printf("Name:      %s\n", field.Name)
+	fmt.Printf("Type:      %s\n", field.DataType)
+	fmt.Printf("Documents: %d\n", field.DocumentCount)
+	if currency, _ := field.ExtraData["default_currency"].(string); currency != "" {
+		fmt.Printf("Currency:  %s\n", currency)
+	}
+	if field.DataType == api.FieldSelect {
+		var labels []string
+		for _, opt := range field.SelectOptions() {
+			labels = append(labels, opt.Label)
+		}
+		fmt.Printf("Options:   %s\n", strings.Join(labels, ", "))
+	}
+
+	return nil
+}
+
+func runFieldsCreate(cmd *cobra.Command, args []string) error {
+	dataType := strings.ToLower(fieldType)
+	if !slices.Contains(api.FieldTypes, dataType) {
+		return fmt.Errorf("invalid type %q (valid types: %s)", fieldType, strings.Join(api.FieldTypes, ", "))
+	}
+	if len(fieldOptions) > 0 && dataType != api.FieldSelect {
+		return fmt.Errorf("--option is only valid for select fields")
+	}
+	if fieldCurrency != "" && dataType != api.FieldMonetary {
+		return fmt.Errorf("--currency is only valid for monetary fields")
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	var extra map[string]any
+	switch dataType {
+	case api.FieldSelect:
+		options := make([]any, len(fieldOptions))
+		for i, label := range fieldOptions {
+			if client.Supports(api.FeatureSelectOptionIDs) {
+				options[i] = api.SelectOption{Label: label}
+			} else {
+				options[i] = label
+			}
+		}
+		extra = map[string]any{"select_options": options}
+	case api.FieldMonetary:
+		if fieldCurrency != "" {
+			extra = map[string]any{"default_currency": strings.ToUpper(fieldCurrency)}
+		}
+	}
+
+	field, err := client.CreateCustomField(args[0], dataType, extra)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(field)
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Created custom field %d: %s (%s)\n", field.ID, field.Name, field.DataType)
+	}
+
+	return nil
+}
+
+func runFieldsEdit(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	field, err := resolveCustomField(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	updates := make(map[string]interface{})
+	if fieldName != "" {
+		updates["name"] = fieldName
+	}
+	if fieldCurrency != "" {
+		if field.DataType != api.FieldMonetary {
+			return fmt.Errorf("--currency is only valid for monetary fields")
+		}
+		extra := make(map[string]any, len(field.ExtraData)+1)
+		for k, v := range field.ExtraData {
+			extra[k] = v
+		}
+		extra["default_currency"] = strings.ToUpper(fieldCurrency)
+		updates["extra_data"] = extra
+	}
+
+	if len(updates) == 0 {
+		return fmt.Errorf("no updates specified")
+	}
+
+	updated, err := client.UpdateCustomField(field.ID, updates)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(updated)
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Updated custom field %d\n", updated.ID)
+	}
+
+	return nil
+}
+
+func runFieldsDelete(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	field, err := resolveCustomField(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	if !fieldForce {
+		msg := fmt.Sprintf("Delete custom field %q?", field.Name)
+		if field.DocumentCount > 0 {
+			msg = fmt.Sprintf("Delete custom field %q and its values on %d document(s)?", field.Name, field.DocumentCount)
+		}
+		if !confirmAction(msg) {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	if err := client.DeleteCustomField(field.ID); err != nil {
+		return err
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Deleted custom field %d\n", field.ID)
+	}
+
+	return nil
+}
+
+// resolveSelectField finds a custom field by ID or name and checks that it
+// is a select field
+func resolveSelectField(client *api.Client, arg string) (*api.CustomField, error) {
+	field, err := resolveCustomField(client, arg)
+	if err != nil {
+		return nil, err
+	}
+	if field.DataType != api.FieldSelect {
+		return nil, fmt.Errorf("custom field %q is a %s field, not a select field", field.Name, field.DataType)
+	}
+	return field, nil
+}
+
+// optionColumns are the columns of a select field's options list
+var optionColumns = []tableColumn[api.SelectOption]{
+	{name: "id", value: func(o api.SelectOption) string { return o.ID }},
+	{name: "label", value: func(o api.SelectOption) string { return o.Label }},
+}
+
+func runFieldsOptionsList(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	columns, err := selectColumns(optionColumns, "id,label")
+	if err != nil {
+		return err
+	}
+
+	field, err := resolveSelectField(client, args[0])
+	if err != nil {
+		return err
+	}
+	options := field.SelectOptions()
+
+	if isJSON() {
+		return printJSON(options)
+	}
+
+	if len(options) == 0 {
+		fmt.Println("No options found")
+		return nil
+	}
+
+	printTable(columns, options)
+
+	return nil
+}
+
+func runFieldsOptionsAdd(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	field, err := resolveSelectField(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	options := field.SelectOptions()
+	for _, label := range args[1:] {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			return fmt.Errorf("option label must not be empty")
+		}
+		for _, opt := range options {
+			if strings.EqualFold(opt.Label, label) {
+				return fmt.Errorf("field %q already has option %q", field.Name, opt.Label)
+			}
+		}
+		options = append(options, api.SelectOption{Label: label})
+	}
+
+	updated, err := client.SetSelectOptions(field, options)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(updated.SelectOptions())
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Added %d option(s) to field %q\n", len(args)-1, field.Name)
+	}
+
+	return nil
+}
+
+func runFieldsOptionsRemove(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	field, err := resolveSelectField(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	options := field.SelectOptions()
+	remove := make(map[int]bool)
+	for _, arg := range args[1:] {
+		i := findSelectOption(options, arg)
+		if i < 0 {
+			return fmt.Errorf("field %q has no option %q", field.Name, arg)
+		}
+		remove[i] = true
+	}
+
+	// Legacy options are referenced by position, so removing one in the
+	// middle would silently change the value of documents using later ones
+	if field.LegacySelectOptions() {
+		for i := len(options) - len(remove); i < len(options); i++ {
+			if !remove[i] {
+				return fmt.Errorf("this server stores select options by position; only the last options of field %q can be removed", field.Name)
+			}
+		}
+	}
+
+	inUse := false
+	for i := range remove {
+		ids, err := client.DocumentsWithFieldValue(field.ID, field.SelectOptionValue(i))
+		if err != nil {
+			if _, ok := err.(*api.UnsupportedFeatureError); ok {
+				fmt.Fprintf(os.Stderr, "Warning: can't check which documents use option %q: %v\n", options[i].Label, err)
+				inUse = true
+				continue
+			}
+			return err
+		}
+		if len(ids) > 0 {
+			fmt.Fprintf(os.Stderr, "Option %q is used by %d document(s): %s\n", options[i].Label, len(ids), joinInts(ids, ", "))
+			inUse = true
+		}
+	}
+
+	if inUse && !fieldsOptionsForce {
+		if !confirmAction(fmt.Sprintf("Remove %d option(s) from field %q?", len(remove), field.Name)) {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	kept := make([]api.SelectOption, 0, len(options)-len(remove))
+	for i, opt := range options {
+		if !remove[i] {
+			kept = append(kept, opt)
+		}
+	}
+
+	updated, err := client.SetSelectOptions(field, kept)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(updated.SelectOptions())
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Removed %d option(s) from field %q\n", len(remove), field.Name)
+	}
+
+	return nil
+}
+
+// findSelectOption returns the index of the option matching arg by label or
+// ID, or -1
+func findSelectOption(options []api.SelectOption, arg string) int {
+	for i, opt := range options {
+		if strings.EqualFold(opt.Label, arg) || opt.ID == arg {
+			return i
+		}
+	}
+	return -1
+}
+
+// resolveCustomField finds a custom field by ID or name
+func resolveCustomField(client *api.Client, arg string) (*api.CustomField, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return client.FindCustomFieldByName(arg)
+	}
+
+	fields, err := client.ListCustomFields(api.ListParams{})
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields.Results {
+		if field.ID == id {
+			return &field, nil
+		}
+	}
+	return nil, fmt.Errorf("custom field not found: %d", id)
+}
+
+// parseFieldAssignments parses <field>=<value> arguments into values keyed
+// by field ID, converted and validated for each field's data type
+func parseFieldAssignments(client *api.Client, specs []string) (map[int]any, error) {
+	values := make(map[int]any, len(specs))
+	for _, spec := range specs {
+		name, raw, ok := strings.Cut(spec, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid field %q, expected <field>=<value>", spec)
+		}
+
+		field, err := resolveCustomField(client, name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := field.ParseValue(raw)
+		if err != nil {
+			return nil, err
+		}
+		values[field.ID] = value
+	}
+	return values, nil
+}
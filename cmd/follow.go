@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var tasksFollowCmd = &cobra.Command{
+	Use:   "follow [task-id...]",
+	Short: "Show live consumption progress",
+	Long: `Show the consumption of uploaded files live, stage by stage: parsing and
+OCR, thumbnails, dates, saving and indexing.
+
+Updates come from the server's status websocket. Without task IDs, every
+file being consumed is shown until Ctrl-C. With task IDs, the command ends
+once those tasks have finished and fails if one of them failed.
+
+Some servers only accept browser logins on the websocket. Given task IDs
+are then polled instead, which shows when they finish but not their stages.
+
+Example:
+  paperless tasks follow
+  paperless tasks follow 1f0c7e2a-4b8d-4e61-9f3a-2c5d8e7b6a10
+  paperless tasks follow --json`,
+	RunE: runTasksFollow,
+}
+
+// followPoll is how often followed tasks are polled: always without the
+// websocket, and now and then with it for tasks that finished unseen
+const (
+	followPoll         = 2 * time.Second
+	followPollBackstop = 10 * time.Second
+)
+
+func init() {
+	tasksCmd.AddCommand(tasksFollowCmd)
+}
+
+func runTasksFollow(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	stream, err := client.OpenStatusStream(ctx)
+	if err != nil {
+		if len(args) == 0 {
+			return fmt.Errorf("live progress unavailable: %w", err)
+		}
+		if !isQuiet() {
+			fmt.Fprintf(os.Stderr, "Live progress unavailable (%v), polling the tasks instead\n", err)
+		}
+	} else {
+		defer stream.Close()
+	}
+
+	if len(args) > 0 {
+		// Shown under their file names
+		tasks := make(map[string]string, len(args))
+		for _, id := range args {
+			tasks[id] = ""
+		}
+		failed, err := followTasks(ctx, client, stream, tasks, os.Stdout)
+		if err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d task(s) failed", failed, len(args))
+		}
+		return nil
+	}
+
+	if !isQuiet() {
+		fmt.Fprintln(os.Stderr, "Following consumption, press Ctrl-C to stop")
+	}
+	for {
+		status, err := stream.Next()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("status stream: %w", err)
+		}
+		printConsumerStatus(os.Stdout, "", status)
+	}
+}
+
+// followTasks shows the consumption of tasks, mapped to the labels to show
+// them under, until all have finished. Without a stream, or for tasks that
+// finished before the stream saw them, the tasks are polled. It returns the
+// number of failed tasks.
+func followTasks(ctx context.Context, client *api.Client, stream *api.StatusStream, tasks map[string]string, out io.Writer) (int, error) {
+	pending := make(map[string]string, len(tasks))
+	for id, label := range tasks {
+		pending[id] = label
+	}
+
+	updates := make(chan *api.ConsumerStatus)
+	streamErr := make(chan error, 1)
+	poll := followPoll
+	if stream != nil {
+		poll = followPollBackstop
+		go func() {
+			for {
+				status, err := stream.Next()
+				if err != nil {
+					streamErr <- err
+					return
+				}
+				select {
+				case updates <- status:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	failed := 0
+	for len(pending) > 0 {
+		select {
+		case <-ctx.Done():
+			return failed, fmt.Errorf("stopped with %d task(s) still running", len(pending))
+
+		case status := <-updates:
+			label, ok := pending[status.TaskID]
+			if !ok {
+				continue
+			}
+			printConsumerStatus(out, label, status)
+			if status.Finished() {
+				delete(pending, status.TaskID)
+				if status.Status == "FAILED" {
+					failed++
+				}
+			}
+
+		case err := <-streamErr:
+			// Carry on polling if the connection drops
+			if !isQuiet() {
+				fmt.Fprintf(os.Stderr, "Live progress lost (%v), polling the tasks instead\n", err)
+			}
+			ticker.Reset(followPoll)
+
+		case <-ticker.C:
+			for id, label := range pending {
+				task, err := client.GetTask(id)
+				if err != nil {
+					// Just uploaded tasks may not be registered yet
+					continue
+				}
+				status := taskConsumerStatus(task)
+				if status.Finished() {
+					printConsumerStatus(out, label, status)
+					delete(pending, id)
+					if status.Status == "FAILED" {
+						failed++
+					}
+				}
+			}
+		}
+	}
+	return failed, nil
+}
+
+// taskConsumerStatus expresses a polled task as a consumption update
+func taskConsumerStatus(task *api.Task) *api.ConsumerStatus {
+	status := &api.ConsumerStatus{Filename: task.TaskFileName, TaskID: task.TaskID, Status: "WORKING"}
+	switch task.Status {
+	case "SUCCESS":
+		status.Status, status.Message = "SUCCESS", "finished"
+		if id, err := strconv.Atoi(task.RelatedDoc); err == nil {
+			status.DocumentID = &id
+		}
+	case "FAILURE", "REVOKED":
+		status.Status, status.Message = "FAILED", task.Result
+	}
+	return status
+}
+
+// consumerStages describes the stages the server reports
+var consumerStages = map[string]string{
+	"new_file":             "starting",
+	"parsing_document":     "parsing and OCR",
+	"generating_thumbnail": "generating thumbnail",
+	"parse_date":           "detecting dates",
+	"save_document":        "saving and indexing",
+	"finished":             "done",
+}
+
+// printConsumerStatus prints an update as one line, or as JSON. The label
+// defaults to the file name.
+func printConsumerStatus(out io.Writer, label string, status *api.ConsumerStatus) {
+	if isJSON() {
+		printJSON(status)
+		return
+	}
+
+	stage, ok := consumerStages[status.Message]
+	switch {
+	case status.Status == "FAILED":
+		stage = "failed: " + status.Message
+	case status.Status == "SUCCESS" && status.DocumentID != nil:
+		stage = fmt.Sprintf("done, document %d", *status.DocumentID)
+	case !ok:
+		stage = strings.ReplaceAll(status.Message, "_", " ")
+	}
+
+	progress := "    "
+	if status.MaxProgress > 0 {
+		progress = fmt.Sprintf("%3d%%", 100*status.CurrentProgress/status.MaxProgress)
+	} else if status.Status == "SUCCESS" {
+		progress = "100%"
+	}
+	fmt.Fprintf(out, "%s  %s  %s\n", progress, firstNonEmpty(label, status.Filename, status.TaskID), stage)
+}
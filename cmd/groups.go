@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var groupsCmd = &cobra.Command{
+	Use:     "groups",
+	Aliases: []string{"group"},
+	Short:   "Manage user groups",
+	Long: `List, create, and delete user groups and edit their members. Needs a token
+of a user with permission to manage users and groups.`,
+}
+
+var groupsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all groups",
+	Long: `List all user groups.
+
+Example:
+  paperless groups list
+  paperless groups list --json`,
+	Args: cobra.NoArgs,
+	RunE: runGroupsList,
+}
+
+var groupsGetCmd = &cobra.Command{
+	Use:   "get <id|name>",
+	Short: "Get group details",
+	Long: `Show a group's permissions and members.
+
+Example:
+  paperless groups get accounting`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGroupsGet,
+}
+
+var groupsCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a new group",
+	Long: `Create a new group, optionally with permissions. Permissions are codenames
+like view_document or change_tag.
+
+Example:
+  paperless groups create accounting
+  paperless groups create readers --permission view_document --permission view_tag`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGroupsCreate,
+}
+
+var groupsDeleteCmd = &cobra.Command{
+	Use:   "delete <id|name>",
+	Short: "Delete a group",
+	Long: `Delete a group. Its members keep their accounts but lose the group's
+permissions.
+
+Example:
+  paperless groups delete accounting
+  paperless groups delete 4 --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGroupsDelete,
+}
+
+var groupsAddMemberCmd = &cobra.Command{
+	Use:   "add-member <group> <user>...",
+	Short: "Add users to a group",
+	Long: `Add users to a group. Groups and users are given by ID or name.
+
+Example:
+  paperless groups add-member accounting anna bob`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGroupsMembership(args, true)
+	},
+}
+
+var groupsRemoveMemberCmd = &cobra.Command{
+	Use:   "remove-member <group> <user>...",
+	Short: "Remove users from a group",
+	Long: `Remove users from a group. Groups and users are given by ID or name.
+
+Example:
+  paperless groups remove-member accounting bob`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGroupsMembership(args, false)
+	},
+}
+
+var (
+	groupPermissions []string
+	groupForce       bool
+)
+
+func init() {
+	rootCmd.AddCommand(groupsCmd)
+	groupsCmd.AddCommand(groupsListCmd)
+	addTableFlags(groupsListCmd, groupColumns)
+	groupsCmd.AddCommand(groupsGetCmd)
+	groupsCmd.AddCommand(groupsCreateCmd)
+	groupsCmd.AddCommand(groupsDeleteCmd)
+	groupsCmd.AddCommand(groupsAddMemberCmd)
+	groupsCmd.AddCommand(groupsRemoveMemberCmd)
+
+	groupsCreateCmd.Flags().StringArrayVar(&groupPermissions, "permission", nil, "permission codename (repeatable)")
+	groupsDeleteCmd.Flags().BoolVarP(&groupForce, "force", "f", false, "skip confirmation")
+}
+
+// findGroup resolves a group ID or name
+func findGroup(client *api.Client, arg string) (*api.Group, error) {
+	if id, err := strconv.Atoi(arg); err == nil {
+		return client.GetGroup(id)
+	}
+	return client.FindGroupByName(arg)
+}
+
+// groupColumns are the columns of the groups list
+var groupColumns = []tableColumn[api.Group]{
+	{name: "id", value: func(g api.Group) string { return strconv.Itoa(g.ID) }},
+	{name: "name", value: func(g api.Group) string { return g.Name }},
+	{name: "permissions", value: func(g api.Group) string { return strconv.Itoa(len(g.Permissions)) }},
+}
+
+func runGroupsList(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	columns, err := selectColumns(groupColumns, "id,name,permissions")
+	if err != nil {
+		return err
+	}
+
+	result, err := client.ListGroups(api.ListParams{})
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(result)
+	}
+
+	if len(result.Results) == 0 {
+		fmt.Println("No groups found")
+		return nil
+	}
+
+	printTable(columns, result.Results)
+
+	return nil
+}
+
+func runGroupsGet(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	g, err := findGroup(client, args[0])
+	if err != nil {
+		return err
+	}
+	members, err := client.GroupMembers(g.ID)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(struct {
+			*api.Group
+			Members []api.User `json:"members"`
+		}{g, members})
+	}
+
+	names := make([]string, len(members))
+	for i, u := range members {
+		names[i] = u.Username
+	}
+
+	fmt.Printf("ID:          %d\n", g.ID)
+	fmt.Printf("Name:        %s\n", g.Name)
+	fmt.Printf("Members:     %s\n", strings.Join(names, ", "))
+	fmt.Printf("Permissions: %s\n", strings.Join(g.Permissions, ", "))
+
+	return nil
+}
+
+func runGroupsCreate(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	g, err := client.CreateGroup(args[0], groupPermissions)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(g)
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Created group %d: %s\n", g.ID, g.Name)
+	}
+
+	return nil
+}
+
+func runGroupsDelete(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	g, err := findGroup(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	if !groupForce {
+		if !confirmAction(fmt.Sprintf("Delete group %d (%s)?", g.ID, g.Name)) {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	if err := client.DeleteGroup(g.ID); err != nil {
+		return err
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Deleted group %d\n", g.ID)
+	}
+
+	return nil
+}
+
+func runGroupsMembership(args []string, member bool) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	g, err := findGroup(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args[1:] {
+		u, err := findUser(client, arg)
+		if err != nil {
+			return err
+		}
+		if _, err := client.SetGroupMembership(u, g.ID, member); err != nil {
+			return err
+		}
+		if !isQuiet() {
+			if member {
+				fmt.Printf("Added %s to %s\n", u.Username, g.Name)
+			} else {
+				fmt.Printf("Removed %s from %s\n", u.Username, g.Name)
+			}
+		}
+	}
+
+	return nil
+}
@@ -1,29 +1,261 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/julianfbeck/paperless-cli/internal/cache"
 	"github.com/julianfbeck/paperless-cli/internal/config"
 )
 
-// getClient returns an authenticated API client
+// getClient returns an authenticated API client for the selected profile
 func getClient() (*api.Client, error) {
-	url := urlFlag
-	if url == "" {
-		url = config.GetURL()
+	profile := config.ActiveProfile()
+	settings, err := config.Settings(profile)
+	if err != nil {
+		return nil, err
 	}
-	if url == "" {
+	markStartup("config")
+	if urlFlag != "" {
+		settings.URL = urlFlag
+	}
+	client, err := newClient(profile, settings)
+	markStartup("client")
+	return client, err
+}
+
+// newClient returns an authenticated API client for a profile's settings,
+// with the TLS flags applied
+func newClient(profile string, settings config.Profile) (*api.Client, error) {
+	if settings.URL == "" {
+		if profile != config.DefaultProfile {
+			return nil, fmt.Errorf("no server URL configured for profile %s. Run 'paperless --profile %s config set-url <url>'", profile, profile)
+		}
 		return nil, fmt.Errorf("no server URL configured. Set PAPERLESS_URL or run 'paperless config set-url <url>'")
 	}
+	if settings.Token == "" {
+		if profile != config.DefaultProfile {
+			return nil, fmt.Errorf("no API token configured for profile %s. Run 'paperless --profile %s config login'", profile, profile)
+		}
+		return nil, fmt.Errorf("no API token configured. Set PAPERLESS_TOKEN or run 'paperless config login'")
+	}
+	return connect(profile, settings)
+}
+
+// connect returns an API client for a profile's settings without checking
+// them, authenticated if they have a token
+func connect(profile string, settings config.Profile) (*api.Client, error) {
+	var opts []api.Option
+	if len(settings.Filter) > 0 && !noFilter {
+		scope, err := documentScope(settings.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: %w", profile, err)
+		}
+		opts = append(opts, api.WithDocumentScope(scope))
+	}
+	if debug, trace := debugLevel(); debug {
+		opts = append(opts, api.WithDebug(os.Stderr, trace))
+	}
+
+	if !noCache {
+		if dir, err := cache.DefaultDir(); err == nil {
+			opts = append(opts, api.WithCache(cache.New(dir), config.GetCacheTTL()))
+		}
+	}
+
+	tlsOpts := api.TLSOptions{
+		CAFile:      firstNonEmpty(caFileFlag, settings.CAFile),
+		Fingerprint: settings.Fingerprint,
+		Insecure:    insecure || settings.Insecure,
+		ClientCert:  firstNonEmpty(clientCert, settings.ClientCert),
+		ClientKey:   firstNonEmpty(clientKey, settings.ClientKey),
+	}
+	if !tlsOpts.IsZero() {
+		tlsConfig, err := api.NewTLSConfig(tlsOpts)
+		if err != nil {
+			return nil, err
+		}
+		if tlsOpts.Insecure && !isQuiet() {
+			fmt.Fprintln(os.Stderr, "Warning: TLS certificate verification is disabled")
+		}
+		opts = append(opts, api.WithTLSConfig(tlsConfig))
+	}
+
+	return api.NewClient(settings.URL, settings.Token, opts...), nil
+}
+
+// documentScope parses a profile's filters
+func documentScope(filters []string) (api.DocumentScope, error) {
+	var scope api.DocumentScope
+	for _, f := range filters {
+		key, value, err := config.ParseFilter(f)
+		if err != nil {
+			return scope, err
+		}
+		switch key {
+		case "owner":
+			scope.Owner = value
+		case "tag":
+			scope.Tags = append(scope.Tags, value)
+		case "correspondent":
+			scope.Correspondent = value
+		case "type":
+			scope.DocumentType = value
+		case "storage_path":
+			scope.StoragePath = value
+		}
+	}
+	return scope, nil
+}
+
+// lazyRegexp compiles expr on first use instead of at startup, which most
+// commands never need to pay for
+func lazyRegexp(expr string) func() *regexp.Regexp {
+	return sync.OnceValue(func() *regexp.Regexp {
+		return regexp.MustCompile(expr)
+	})
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// joinInts formats IDs as a separated list
+func joinInts(ids []int, sep string) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, sep)
+}
+
+// maxIDRange limits how many IDs a range like "100-120" may expand to
+const maxIDRange = 10000
+
+// parseDocumentIDs parses document IDs given as arguments. Each argument is
+// an ID, a range like "100-120" or a comma-separated list of those. The IDs
+// keep their order; repeated ones are dropped.
+func parseDocumentIDs(args []string) ([]int, error) {
+	ids, _, err := parseDocumentIDRanges(args)
+	return ids, err
+}
 
-	token := config.GetToken()
-	if token == "" {
-		return nil, fmt.Errorf("no API token configured. Set PAPERLESS_TOKEN or run 'paperless config set-token <token>'")
+// parseDocumentIDRanges is parseDocumentIDs that also returns the IDs only
+// given as part of a range. Those may have been deleted, so commands skip
+// them with a warning instead of failing.
+func parseDocumentIDRanges(args []string) (ids []int, ranged map[int]bool, err error) {
+	seen := make(map[int]bool)
+	ranged = make(map[int]bool)
+	for _, arg := range args {
+		for _, part := range strings.Split(arg, ",") {
+			part = strings.TrimSpace(part)
+			from, to, isRange := strings.Cut(part, "-")
+			first, err := strconv.Atoi(from)
+			last := first
+			if err == nil && isRange {
+				last, err = strconv.Atoi(to)
+			}
+			if err != nil || first < 1 || last < first {
+				return nil, nil, fmt.Errorf("invalid document ID: %s", part)
+			}
+			if last-first >= maxIDRange {
+				return nil, nil, fmt.Errorf("document ID range %s is too large (at most %d IDs)", part, maxIDRange)
+			}
+			for id := first; id <= last; id++ {
+				if !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+					ranged[id] = isRange
+				} else if !isRange {
+					ranged[id] = false
+				}
+			}
+		}
 	}
+	return ids, ranged, nil
+}
 
-	return api.NewClient(url, token), nil
+// skipMissing reports whether err says that document id, given only as
+// part of a range, doesn't exist, and warns that it's skipped
+func skipMissing(id int, ranged map[int]bool, err error) bool {
+	if !ranged[id] || !api.IsDocumentNotFound(err) {
+		return false
+	}
+	warnMissing(id)
+	return true
+}
+
+// warnMissing warns that a document of a range is skipped
+func warnMissing(id int) {
+	printAbove(os.Stderr, "Warning: skipping document %d, which doesn't exist\n", id)
+}
+
+// dropMissingDocuments removes the IDs given only as part of a range that
+// don't exist, checking them in parallel, for commands acting on all IDs at
+// once
+func dropMissingDocuments(client *api.Client, ids []int, ranged map[int]bool) ([]int, error) {
+	var check []int
+	for _, id := range ids {
+		if ranged[id] {
+			check = append(check, id)
+		}
+	}
+	if len(check) == 0 {
+		return ids, nil
+	}
+
+	exists := make([]bool, len(check))
+	errs := forEachParallel(check, maxPar, func(i int, id int) error {
+		_, err := client.GetDocument(id)
+		if api.IsDocumentNotFound(err) {
+			return nil
+		}
+		exists[i] = err == nil
+		return err
+	})
+	missing := make(map[int]bool)
+	for i, id := range check {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("document %d: %w", id, errs[i])
+		}
+		if !exists[i] {
+			warnMissing(id)
+			missing[id] = true
+		}
+	}
+	return slices.DeleteFunc(ids, func(id int) bool { return missing[id] }), nil
+}
+
+// debugLevel reports whether HTTP debugging and tracing are enabled, either
+// via --debug/--trace or PAPERLESS_DEBUG (set to "trace" for full output)
+func debugLevel() (debug, trace bool) {
+	if traceFlag {
+		return true, true
+	}
+	if debugFlag {
+		return true, false
+	}
+	switch strings.ToLower(os.Getenv("PAPERLESS_DEBUG")) {
+	case "", "0", "false", "no", "off":
+		return false, false
+	case "trace", "2":
+		return true, true
+	default:
+		return true, false
+	}
 }
 
 // confirmAction asks for user confirmation
@@ -36,3 +268,14 @@ func confirmAction(message string) bool {
 	fmt.Scanln(&response)
 	return response == "y" || response == "Y" || response == "yes" || response == "Yes"
 }
+
+// confirmTyped asks the user to type want to confirm, for actions too
+// destructive for a y/N prompt
+func confirmTyped(message, want string) bool {
+	if quietMode {
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "%s Type %q to confirm: ", message, want)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(line) == want
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestParseDocumentIDs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    []int
+		ranged  []int
+		wantErr bool
+	}{
+		{args: []string{"5"}, want: []int{5}},
+		{args: []string{"5", "3"}, want: []int{5, 3}},
+		{args: []string{"1,2", " 3 "}, want: []int{1, 2, 3}},
+
+		// Ranges
+		{args: []string{"100-103"}, want: []int{100, 101, 102, 103}, ranged: []int{100, 101, 102, 103}},
+		{args: []string{"7-7"}, want: []int{7}, ranged: []int{7}},
+		{args: []string{"1,4-5", "9"}, want: []int{1, 4, 5, 9}, ranged: []int{4, 5}},
+		{args: []string{"1-10000"}, want: seq(1, maxIDRange), ranged: seq(1, maxIDRange)},
+
+		// Duplicates keep their first position; a range doesn't make an ID
+		// named on its own optional
+		{args: []string{"3", "3"}, want: []int{3}},
+		{args: []string{"2,1,2"}, want: []int{2, 1}},
+		{args: []string{"2-4", "3"}, want: []int{2, 3, 4}, ranged: []int{2, 4}},
+		{args: []string{"3", "2-4"}, want: []int{3, 2, 4}, ranged: []int{2, 4}},
+		{args: []string{"1-3,2-4"}, want: []int{1, 2, 3, 4}, ranged: []int{1, 2, 3, 4}},
+
+		// Reversed ranges
+		{args: []string{"5-3"}, wantErr: true},
+		{args: []string{"1", "9-8"}, wantErr: true},
+
+		// Invalid
+		{args: []string{""}, wantErr: true},
+		{args: []string{"0"}, wantErr: true},
+		{args: []string{"-3"}, wantErr: true},
+		{args: []string{"3-"}, wantErr: true},
+		{args: []string{"0-2"}, wantErr: true},
+		{args: []string{"1-2-3"}, wantErr: true},
+		{args: []string{"abc"}, wantErr: true},
+		{args: []string{"1,,2"}, wantErr: true},
+		{args: []string{"1.5"}, wantErr: true},
+
+		// More than maxIDRange IDs
+		{args: []string{"1-10001"}, wantErr: true},
+		{args: []string{"5-100000"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		ids, ranged, err := parseDocumentIDRanges(tt.args)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseDocumentIDs(%q) = %v, want an error", tt.args, ids)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseDocumentIDs(%q): %v", tt.args, err)
+			continue
+		}
+		if !slices.Equal(ids, tt.want) {
+			t.Errorf("parseDocumentIDs(%q) = %s, want %s", tt.args, short(ids), short(tt.want))
+		}
+
+		var gotRanged []int
+		for _, id := range ids {
+			if ranged[id] {
+				gotRanged = append(gotRanged, id)
+			}
+		}
+		if !slices.Equal(gotRanged, tt.ranged) {
+			t.Errorf("parseDocumentIDs(%q) ranged = %s, want %s", tt.args, short(gotRanged), short(tt.ranged))
+		}
+	}
+}
+
+// seq returns the IDs from first to last
+func seq(first, last int) []int {
+	var ids []int
+	for id := first; id <= last; id++ {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// short formats IDs for test failures, eliding long lists
+func short(ids []int) string {
+	if len(ids) > 10 {
+		return fmt.Sprintf("[%s ... %d] (%d IDs)", joinInts(ids[:5], " "), ids[len(ids)-1], len(ids))
+	}
+	return fmt.Sprint(ids)
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/julianfbeck/paperless-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// overrideHold lets a command touch documents on legal hold
+var overrideHold bool
+
+// addOverrideHoldFlag adds --override-hold to a command that deletes or
+// changes documents in bulk
+func addOverrideHoldFlag(c *cobra.Command) {
+	c.Flags().BoolVar(&overrideHold, "override-hold", false, "include documents on legal hold")
+}
+
+// legalHold identifies documents that must not be deleted or changed in
+// bulk: those with the hold tag, or with the boolean custom field of the
+// same name set (see 'config set hold')
+type legalHold struct {
+	name    string
+	tagID   int
+	fieldID int
+}
+
+// loadLegalHold looks up the hold tag and custom field. Either may not
+// exist, in which case it doesn't hold anything.
+func loadLegalHold(client *api.Client) (*legalHold, error) {
+	h := &legalHold{name: config.GetHold()}
+
+	tags, err := client.ListTags(api.ListParams{})
+	if err != nil {
+		return nil, fmt.Errorf("looking up legal hold tag: %w", err)
+	}
+	for _, tag := range tags.Results {
+		if strings.EqualFold(tag.Name, h.name) {
+			h.tagID = tag.ID
+		}
+	}
+
+	if client.Supports(api.FeatureCustomFields) {
+		fields, err := client.ListCustomFields(api.ListParams{})
+		if err != nil {
+			return nil, fmt.Errorf("looking up legal hold field: %w", err)
+		}
+		for _, field := range fields.Results {
+			if strings.EqualFold(field.Name, h.name) && field.DataType == "boolean" {
+				h.fieldID = field.ID
+			}
+		}
+	}
+
+	return h, nil
+}
+
+// active reports whether any document can be on hold
+func (h *legalHold) active() bool {
+	return h.tagID != 0 || h.fieldID != 0
+}
+
+// holds reports whether doc is on legal hold
+func (h *legalHold) holds(doc api.Document) bool {
+	if h.tagID != 0 && slices.Contains(doc.Tags, h.tagID) {
+		return true
+	}
+	for _, f := range doc.CustomFields {
+		if h.fieldID != 0 && f.Field == h.fieldID && f.Value == true {
+			return true
+		}
+	}
+	return false
+}
+
+// heldIDs returns the IDs of all documents on hold
+func (h *legalHold) heldIDs(client *api.Client) (map[int]bool, error) {
+	held := make(map[int]bool)
+
+	if h.tagID != 0 {
+		result, err := client.ListDocuments(api.DocumentListParams{TagIDsAny: []int{h.tagID}, Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range result.All {
+			held[id] = true
+		}
+	}
+
+	if h.fieldID != 0 {
+		if client.Supports(api.FeatureCustomFieldQuery) {
+			ids, err := client.DocumentsWithFieldValue(h.fieldID, true)
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range ids {
+				held[id] = true
+			}
+		} else {
+			// Older servers can't filter by field value, so check them all
+			docs, err := listAllDocuments(client, api.DocumentListParams{})
+			if err != nil {
+				return nil, err
+			}
+			for _, doc := range docs {
+				if h.holds(doc) {
+					held[doc.ID] = true
+				}
+			}
+		}
+	}
+
+	return held, nil
+}
+
+// exclude drops the documents on hold from ids unless --override-hold is
+// given, and tells how many were left out
+func (h *legalHold) exclude(client *api.Client, ids []int) ([]int, error) {
+	if overrideHold || !h.active() {
+		return ids, nil
+	}
+
+	held, err := h.heldIDs(client)
+	if err != nil {
+		return nil, fmt.Errorf("checking legal hold: %w", err)
+	}
+	kept := slices.DeleteFunc(slices.Clone(ids), func(id int) bool { return held[id] })
+	h.reportSkipped(len(ids) - len(kept))
+	return kept, nil
+}
+
+// reportSkipped tells that n documents on hold were left out
+func (h *legalHold) reportSkipped(n int) {
+	if n > 0 && !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Skipping %d document(s) on legal hold (%s); pass --override-hold to include them\n", n, h.name)
+	}
+}
@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var inboxCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "Triage inbox documents one by one",
+	Long: `Go through the documents tagged with an inbox tag one at a time, showing
+each one's metadata, Paperless' suggestions and the start of its content.
+
+Single keys change the document: c sets the correspondent, t the document
+type, g the tags and e the title; a takes over all suggestions. d saves the
+changes and removes the inbox tags, s skips the document and q quits.
+Names are matched fuzzily, as in 'documents upload --interactive'.
+
+Example:
+  paperless inbox`,
+	Args: cobra.NoArgs,
+	RunE: runInbox,
+}
+
+func init() {
+	rootCmd.AddCommand(inboxCmd)
+}
+
+// inboxTriage holds the choices of the inbox loop and the edits of the
+// current document
+type inboxTriage struct {
+	*uploadWizard
+	inboxTags []int
+
+	title         string
+	correspondent *int
+	docType       *int
+	docTags       []int
+}
+
+func runInbox(cmd *cobra.Command, args []string) error {
+	if !isTerminal(os.Stdin) {
+		return fmt.Errorf("inbox needs a terminal")
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	t := &inboxTriage{uploadWizard: &uploadWizard{client: client, in: bufio.NewReader(os.Stdin)}}
+	if err := t.loadChoices(); err != nil {
+		return err
+	}
+	tags, err := client.ListTags(api.ListParams{})
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags.Results {
+		if tag.IsInboxTag {
+			t.inboxTags = append(t.inboxTags, tag.ID)
+		}
+	}
+	if len(t.inboxTags) == 0 {
+		return fmt.Errorf("no inbox tag; mark one with 'paperless tags edit <tag> --inbox'")
+	}
+
+	docs, err := listAllDocuments(client, api.DocumentListParams{TagIDsAny: t.inboxTags})
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		fmt.Println("Inbox is empty")
+		return nil
+	}
+
+	done := 0
+	for i := range docs {
+		fmt.Fprintf(os.Stderr, "\n[%d/%d] ", i+1, len(docs))
+		triaged, err := t.triage(&docs[i])
+		if errors.Is(err, errWizardQuit) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if triaged {
+			done++
+		}
+	}
+
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "\nTriaged %d of %d document(s)\n", done, len(docs))
+	}
+	return nil
+}
+
+// triage shows a document and applies the keys pressed until it's done or
+// skipped. It reports whether the document was saved.
+func (t *inboxTriage) triage(doc *api.Document) (bool, error) {
+	t.title, t.correspondent, t.docType = doc.Title, doc.Correspondent, doc.DocumentType
+	t.docTags = slices.Clone(doc.Tags)
+
+	suggestions, err := t.client.GetDocumentSuggestions(doc.ID)
+	if err != nil {
+		return false, err
+	}
+
+	fmt.Fprintf(os.Stderr, "Document %d, created %s, %s\n", doc.ID, doc.CreatedDate, doc.OriginalFileName)
+	t.show()
+	t.showSuggestions(suggestions)
+	t.showContent(doc.Content)
+
+	for {
+		key, err := t.readKey("[c]orrespondent [t]ype ta[g]s titl[e] [a]ccept suggestions [d]one [s]kip [q]uit")
+		if err != nil {
+			return false, err
+		}
+
+		switch key {
+		case 'c':
+			if t.correspondent, err = t.askObject("Correspondent", "correspondent", t.correspondents, t.correspondent); err != nil {
+				return false, err
+			}
+		case 't':
+			if t.docType, err = t.askObject("Type", "document type", t.types, t.docType); err != nil {
+				return false, err
+			}
+		case 'g':
+			if t.docTags, err = t.askTags(t.docTags); err != nil {
+				return false, err
+			}
+		case 'e':
+			if t.title, err = t.prompt("Title", t.title); err != nil {
+				return false, err
+			}
+		case 'a':
+			t.accept(suggestions)
+		case 'd':
+			if err := t.save(doc); err != nil {
+				return false, err
+			}
+			return true, nil
+		case 's':
+			return false, nil
+		case 'q':
+			return false, errWizardQuit
+		default:
+			continue
+		}
+		t.show()
+	}
+}
+
+// show prints the document's metadata as edited so far
+func (t *inboxTriage) show() {
+	tags := t.keptTags()
+	names := make([]string, len(tags))
+	for i, id := range tags {
+		names[i] = objectName(t.tags, &id)
+	}
+
+	fmt.Fprintf(os.Stderr, "  Title:         %s\n", t.title)
+	fmt.Fprintf(os.Stderr, "  Correspondent: %s\n", firstNonEmpty(objectName(t.correspondents, t.correspondent), "-"))
+	fmt.Fprintf(os.Stderr, "  Type:          %s\n", firstNonEmpty(objectName(t.types, t.docType), "-"))
+	fmt.Fprintf(os.Stderr, "  Tags:          %s\n", firstNonEmpty(strings.Join(names, ", "), "-"))
+}
+
+// showSuggestions prints what Paperless suggests for the document
+func (t *inboxTriage) showSuggestions(s *api.DocumentSuggestions) {
+	tags := slices.DeleteFunc(slices.Clone(s.Tags), func(id int) bool {
+		return slices.Contains(t.inboxTags, id)
+	})
+
+	var parts []string
+	for _, k := range []struct {
+		label string
+		objs  []namedObject
+		ids   []int
+	}{
+		{"correspondent", t.correspondents, s.Correspondents},
+		{"type", t.types, s.DocumentTypes},
+		{"tags", t.tags, tags},
+	} {
+		if len(k.ids) == 0 {
+			continue
+		}
+		names := make([]string, len(k.ids))
+		for i, id := range k.ids {
+			names[i] = objectName(k.objs, &id)
+		}
+		parts = append(parts, k.label+" "+strings.Join(names, ", "))
+	}
+	if len(parts) > 0 {
+		fmt.Fprintf(os.Stderr, "  Suggested:     %s\n", strings.Join(parts, "; "))
+	}
+}
+
+// showContent prints the first lines of the document's content
+func (t *inboxTriage) showContent(content string) {
+	shown := 0
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "  | %s\n", truncate(line, 76))
+		if shown++; shown == previewLines {
+			break
+		}
+	}
+}
+
+// accept takes over the first suggested correspondent and type and adds
+// all suggested tags
+func (t *inboxTriage) accept(s *api.DocumentSuggestions) {
+	if len(s.Correspondents) > 0 {
+		t.correspondent = &s.Correspondents[0]
+	}
+	if len(s.DocumentTypes) > 0 {
+		t.docType = &s.DocumentTypes[0]
+	}
+	for _, id := range s.Tags {
+		if !slices.Contains(t.docTags, id) {
+			t.docTags = append(t.docTags, id)
+		}
+	}
+}
+
+// keptTags returns the document's tags without the inbox tags
+func (t *inboxTriage) keptTags() []int {
+	tags := []int{}
+	for _, id := range t.docTags {
+		if !slices.Contains(t.inboxTags, id) {
+			tags = append(tags, id)
+		}
+	}
+	return tags
+}
+
+// save applies the edits to the document and removes its inbox tags
+func (t *inboxTriage) save(doc *api.Document) error {
+	updates := map[string]interface{}{
+		"title":         t.title,
+		"correspondent": t.correspondent,
+		"document_type": t.docType,
+		"tags":          t.keptTags(),
+	}
+	if _, err := t.client.UpdateDocument(doc.ID, updates); err != nil {
+		return err
+	}
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "  Saved document %d\n", doc.ID)
+	}
+	return nil
+}
+
+// readKey shows the choices and returns the key pressed, lower-cased. If
+// the terminal can't read single keys, the first letter of a line is used.
+func (t *inboxTriage) readKey(choices string) (rune, error) {
+	fmt.Fprintf(os.Stderr, "%s: ", choices)
+
+	if err := setKeyMode(true); err == nil {
+		// Ctrl-C would otherwise leave the terminal without echo
+		sig := make(chan os.Signal, 1)
+		done := make(chan struct{})
+		signal.Notify(sig, os.Interrupt)
+		go func() {
+			select {
+			case <-sig:
+				setKeyMode(false)
+				fmt.Fprintln(os.Stderr)
+				os.Exit(exitInterrupted)
+			case <-done:
+			}
+		}()
+
+		r, _, err := t.in.ReadRune()
+		signal.Stop(sig)
+		close(done)
+		setKeyMode(false)
+		if err != nil {
+			fmt.Fprintln(os.Stderr)
+			return 0, errWizardQuit
+		}
+		fmt.Fprintf(os.Stderr, "%c\n", r)
+		return unicode.ToLower(r), nil
+	}
+
+	line, err := t.in.ReadString('\n')
+	line = strings.TrimSpace(line)
+	if line == "" {
+		if err != nil {
+			fmt.Fprintln(os.Stderr)
+			return 0, errWizardQuit
+		}
+		return 0, nil
+	}
+	return unicode.ToLower([]rune(line)[0]), nil
+}
@@ -0,0 +1,482 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var docsInvoiceDataCmd = &cobra.Command{
+	Use:   "invoice-data <id|file>",
+	Short: "Extract e-invoice data as JSON",
+	Long: `Extract totals, VAT, IBAN and due date from an invoice.
+
+The argument is either a document ID or a local PDF file. Embedded
+ZUGFeRD / Factur-X (CII) and XRechnung (UBL) XML is used when present,
+otherwise the values are guessed from the document text.
+
+With --set-field the extracted values are written into custom fields of the
+document, given as <key>=<custom field name>. Keys: ` + strings.Join(invoiceFieldKeys(), ", ") + `.
+
+Example:
+  paperless documents invoice-data 123
+  paperless documents invoice-data invoice.pdf
+  paperless documents invoice-data 123 --set-field grand_total=Amount --set-field due_date="Due date"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDocsInvoiceData,
+}
+
+var invoiceSetFields []string
+
+func init() {
+	documentsCmd.AddCommand(docsInvoiceDataCmd)
+
+	docsInvoiceDataCmd.Flags().StringArrayVar(&invoiceSetFields, "set-field", nil, "write a value into a custom field: <key>=<field name> (repeatable)")
+}
+
+// invoiceData holds the values extracted from an invoice
+type invoiceData struct {
+	Source        string       `json:"source"`
+	Format        string       `json:"format,omitempty"`
+	InvoiceNumber string       `json:"invoice_number,omitempty"`
+	IssueDate     string       `json:"issue_date,omitempty"`
+	DueDate       string       `json:"due_date,omitempty"`
+	Seller        string       `json:"seller,omitempty"`
+	Currency      string       `json:"currency,omitempty"`
+	NetTotal      string       `json:"net_total,omitempty"`
+	TaxTotal      string       `json:"tax_total,omitempty"`
+	GrandTotal    string       `json:"grand_total,omitempty"`
+	AmountDue     string       `json:"amount_due,omitempty"`
+	IBAN          string       `json:"iban,omitempty"`
+	VAT           []invoiceVAT `json:"vat,omitempty"`
+}
+
+// invoiceVAT is one line of the VAT breakdown
+type invoiceVAT struct {
+	Rate     string `json:"rate,omitempty"`
+	Basis    string `json:"basis,omitempty"`
+	Amount   string `json:"amount,omitempty"`
+	Category string `json:"category,omitempty"`
+}
+
+// invoiceFields maps --set-field keys to extracted values
+var invoiceFields = map[string]func(*invoiceData) string{
+	"invoice_number": func(d *invoiceData) string { return d.InvoiceNumber },
+	"issue_date":     func(d *invoiceData) string { return d.IssueDate },
+	"due_date":       func(d *invoiceData) string { return d.DueDate },
+	"seller":         func(d *invoiceData) string { return d.Seller },
+	"currency":       func(d *invoiceData) string { return d.Currency },
+	"net_total":      func(d *invoiceData) string { return d.NetTotal },
+	"tax_total":      func(d *invoiceData) string { return d.TaxTotal },
+	"grand_total":    func(d *invoiceData) string { return d.GrandTotal },
+	"amount_due":     func(d *invoiceData) string { return d.AmountDue },
+	"iban":           func(d *invoiceData) string { return d.IBAN },
+}
+
+func invoiceFieldKeys() []string {
+	keys := make([]string, 0, len(invoiceFields))
+	for k := range invoiceFields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func runDocsInvoiceData(cmd *cobra.Command, args []string) error {
+	var data *invoiceData
+
+	if _, err := os.Stat(args[0]); err == nil {
+		if len(invoiceSetFields) > 0 {
+			return fmt.Errorf("--set-field requires a document ID, not a file")
+		}
+		data, err = invoiceDataFromPDF(args[0])
+		if err != nil {
+			return err
+		}
+		return printJSON(data)
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("no such file or document ID: %s", args[0])
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	data, err = invoiceDataFromDocument(client, id)
+	if err != nil {
+		return err
+	}
+
+	if len(invoiceSetFields) > 0 {
+		if err := setInvoiceFields(client, id, data); err != nil {
+			return err
+		}
+	}
+
+	return printJSON(data)
+}
+
+// invoiceDataFromDocument looks for e-invoice XML in the original file and
+// falls back to the OCR text stored in Paperless
+func invoiceDataFromDocument(client *api.Client, id int) (*invoiceData, error) {
+	tmp, err := os.CreateTemp("", "paperless-invoice-*.pdf")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	info, err := client.DownloadDocumentTo(context.Background(), id, tmp, api.DownloadOptions{Original: true})
+	tmp.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.Contains(info.ContentType, "pdf") {
+		if data := invoiceDataFromAttachments(tmp.Name()); data != nil {
+			return data, nil
+		}
+	}
+
+	doc, err := client.GetDocument(id)
+	if err != nil {
+		return nil, err
+	}
+	return parseInvoiceText(doc.Content), nil
+}
+
+// invoiceDataFromPDF reads a local PDF, preferring embedded XML over text
+func invoiceDataFromPDF(path string) (*invoiceData, error) {
+	if data := invoiceDataFromAttachments(path); data != nil {
+		return data, nil
+	}
+
+	text, err := extractPDFText(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+	return parseInvoiceText(text), nil
+}
+
+// invoiceDataFromAttachments returns the data of the first embedded XML
+// file that is an e-invoice, or nil if there is none
+func invoiceDataFromAttachments(path string) *invoiceData {
+	attachments, err := readPDFAttachments(path)
+	if err != nil {
+		return nil
+	}
+
+	for _, a := range attachments {
+		if !a.isXML() {
+			continue
+		}
+		if data, err := parseInvoiceXML(a.data); err == nil {
+			data.Source = a.Name
+			return data
+		}
+	}
+	return nil
+}
+
+// invoiceXMLRules map element paths (matched against the end of the current
+// element stack) to the value they hold, for both CII and UBL
+var invoiceXMLRules = []struct {
+	path []string
+	set  func(d *invoiceData, v string)
+}{
+	// ZUGFeRD / Factur-X (UN/CEFACT Cross Industry Invoice)
+	{[]string{"ExchangedDocument", "ID"}, func(d *invoiceData, v string) { d.InvoiceNumber = v }},
+	{[]string{"ExchangedDocument", "IssueDateTime", "DateTimeString"}, func(d *invoiceData, v string) { d.IssueDate = normalizeInvoiceDate(v) }},
+	{[]string{"SpecifiedTradePaymentTerms", "DueDateDateTime", "DateTimeString"}, func(d *invoiceData, v string) { d.DueDate = normalizeInvoiceDate(v) }},
+	{[]string{"SellerTradeParty", "Name"}, func(d *invoiceData, v string) { d.Seller = v }},
+	{[]string{"InvoiceCurrencyCode"}, func(d *invoiceData, v string) { d.Currency = v }},
+	{[]string{"SpecifiedTradeSettlementHeaderMonetarySummation", "TaxBasisTotalAmount"}, func(d *invoiceData, v string) { d.NetTotal = normalizeAmount(v) }},
+	{[]string{"SpecifiedTradeSettlementHeaderMonetarySummation", "TaxTotalAmount"}, func(d *invoiceData, v string) { d.TaxTotal = normalizeAmount(v) }},
+	{[]string{"SpecifiedTradeSettlementHeaderMonetarySummation", "GrandTotalAmount"}, func(d *invoiceData, v string) { d.GrandTotal = normalizeAmount(v) }},
+	{[]string{"SpecifiedTradeSettlementHeaderMonetarySummation", "DuePayableAmount"}, func(d *invoiceData, v string) { d.AmountDue = normalizeAmount(v) }},
+	{[]string{"PayeePartyCreditorFinancialAccount", "IBANID"}, func(d *invoiceData, v string) { d.IBAN = v }},
+	{[]string{"ApplicableHeaderTradeSettlement", "ApplicableTradeTax", "CalculatedAmount"}, func(d *invoiceData, v string) { lastVAT(d).Amount = normalizeAmount(v) }},
+	{[]string{"ApplicableHeaderTradeSettlement", "ApplicableTradeTax", "BasisAmount"}, func(d *invoiceData, v string) { lastVAT(d).Basis = normalizeAmount(v) }},
+	{[]string{"ApplicableHeaderTradeSettlement", "ApplicableTradeTax", "RateApplicablePercent"}, func(d *invoiceData, v string) { lastVAT(d).Rate = v }},
+	{[]string{"ApplicableHeaderTradeSettlement", "ApplicableTradeTax", "CategoryCode"}, func(d *invoiceData, v string) { lastVAT(d).Category = v }},
+
+	// XRechnung / Peppol (OASIS UBL)
+	{[]string{"Invoice", "ID"}, func(d *invoiceData, v string) { d.InvoiceNumber = v }},
+	{[]string{"Invoice", "IssueDate"}, func(d *invoiceData, v string) { d.IssueDate = v }},
+	{[]string{"Invoice", "DueDate"}, func(d *invoiceData, v string) { d.DueDate = v }},
+	{[]string{"AccountingSupplierParty", "Party", "PartyName", "Name"}, func(d *invoiceData, v string) { d.Seller = v }},
+	{[]string{"AccountingSupplierParty", "Party", "PartyLegalEntity", "RegistrationName"}, func(d *invoiceData, v string) { d.Seller = v }},
+	{[]string{"DocumentCurrencyCode"}, func(d *invoiceData, v string) { d.Currency = v }},
+	{[]string{"LegalMonetaryTotal", "TaxExclusiveAmount"}, func(d *invoiceData, v string) { d.NetTotal = normalizeAmount(v) }},
+	{[]string{"Invoice", "TaxTotal", "TaxAmount"}, func(d *invoiceData, v string) { d.TaxTotal = normalizeAmount(v) }},
+	{[]string{"LegalMonetaryTotal", "TaxInclusiveAmount"}, func(d *invoiceData, v string) { d.GrandTotal = normalizeAmount(v) }},
+	{[]string{"LegalMonetaryTotal", "PayableAmount"}, func(d *invoiceData, v string) { d.AmountDue = normalizeAmount(v) }},
+	{[]string{"PaymentMeans", "PayeeFinancialAccount", "ID"}, func(d *invoiceData, v string) { d.IBAN = v }},
+	{[]string{"TaxSubtotal", "TaxableAmount"}, func(d *invoiceData, v string) { lastVAT(d).Basis = normalizeAmount(v) }},
+	{[]string{"TaxSubtotal", "TaxAmount"}, func(d *invoiceData, v string) { lastVAT(d).Amount = normalizeAmount(v) }},
+	{[]string{"TaxSubtotal", "TaxCategory", "Percent"}, func(d *invoiceData, v string) { lastVAT(d).Rate = v }},
+	{[]string{"TaxSubtotal", "TaxCategory", "ID"}, func(d *invoiceData, v string) { lastVAT(d).Category = v }},
+}
+
+// parseInvoiceXML extracts invoice data from CII or UBL XML
+func parseInvoiceXML(data []byte) (*invoiceData, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	inv := &invoiceData{}
+	var stack []string
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+			if len(stack) == 1 {
+				switch t.Name.Local {
+				case "CrossIndustryInvoice":
+					inv.Format = "CII"
+				case "Invoice", "CreditNote":
+					inv.Format = "UBL"
+				}
+			}
+			// Only the document level VAT breakdown, not per line item
+			if hasPathSuffix(stack, "ApplicableHeaderTradeSettlement", "ApplicableTradeTax") || hasPathSuffix(stack, "TaxTotal", "TaxSubtotal") {
+				inv.VAT = append(inv.VAT, invoiceVAT{})
+			}
+
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+
+		case xml.CharData:
+			text := strings.TrimSpace(string(t))
+			if text == "" {
+				continue
+			}
+			for _, rule := range invoiceXMLRules {
+				if hasPathSuffix(stack, rule.path...) {
+					rule.set(inv, text)
+					break
+				}
+			}
+		}
+	}
+
+	if inv.Format == "" {
+		return nil, fmt.Errorf("not an e-invoice")
+	}
+	return inv, nil
+}
+
+// hasPathSuffix reports whether the element stack ends with names
+func hasPathSuffix(stack []string, names ...string) bool {
+	if len(names) > len(stack) {
+		return false
+	}
+	tail := stack[len(stack)-len(names):]
+	for i := range names {
+		if tail[i] != names[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func lastVAT(d *invoiceData) *invoiceVAT {
+	if len(d.VAT) == 0 {
+		d.VAT = append(d.VAT, invoiceVAT{})
+	}
+	return &d.VAT[len(d.VAT)-1]
+}
+
+var (
+	amountPattern        = `(\d{1,3}(?:[.,' ]\d{3})*[.,]\d{2}|\d+[.,]\d{2})`
+	invoiceTotalRe       = lazyRegexp(`(?i)(?:gesamtbetrag|rechnungsbetrag|endbetrag|gesamtsumme|summe|zu zahlen|total|amount due|balance due)[^\d\n]{0,30}?` + amountPattern)
+	invoiceVATRe         = lazyRegexp(`(?i)(?:mwst|ust|vat|mehrwertsteuer|umsatzsteuer)\.?[^\d\n]{0,15}?(\d{1,2}(?:[.,]\d{1,2})?)\s*%(?:[^\d\n]{0,30}?` + amountPattern + `)?`)
+	invoiceDueRe         = lazyRegexp(`(?i)(?:fällig(?:\s+am)?|zahlbar bis|due date|due|payable by)[:\s]*(\d{1,2}\.\d{1,2}\.\d{4}|\d{4}-\d{2}-\d{2})`)
+	invoiceNumberRe      = lazyRegexp(`(?i)(?:rechnungs-?nr\.?|rechnungsnummer|invoice\s+(?:no\.?|number|#))[:\s]*([A-Z0-9][A-Z0-9\-/]{2,})`)
+	invoiceIBANRe        = lazyRegexp(`\b([A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?)\b`)
+	invoiceGermanDateRe  = lazyRegexp(`^(\d{1,2})\.(\d{1,2})\.(\d{4})$`)
+	invoiceCompactDateRe = lazyRegexp(`^(\d{4})(\d{2})(\d{2})$`)
+)
+
+// parseInvoiceText guesses invoice data from plain text. It is a best
+// effort fallback for invoices without embedded XML.
+func parseInvoiceText(text string) *invoiceData {
+	inv := &invoiceData{Source: "text"}
+
+	// The grand total is the largest of the amounts labeled as a total
+	var best float64
+	for _, m := range invoiceTotalRe().FindAllStringSubmatch(text, -1) {
+		amount := normalizeAmount(m[1])
+		if v, err := strconv.ParseFloat(amount, 64); err == nil && v > best {
+			best = v
+			inv.GrandTotal = amount
+		}
+	}
+
+	for _, m := range invoiceVATRe().FindAllStringSubmatch(text, -1) {
+		vat := invoiceVAT{Rate: strings.ReplaceAll(m[1], ",", ".")}
+		if m[2] != "" {
+			vat.Amount = normalizeAmount(m[2])
+		}
+		inv.VAT = append(inv.VAT, vat)
+	}
+
+	if m := invoiceDueRe().FindStringSubmatch(text); m != nil {
+		inv.DueDate = normalizeInvoiceDate(m[1])
+	}
+	if m := invoiceNumberRe().FindStringSubmatch(text); m != nil {
+		inv.InvoiceNumber = m[1]
+	}
+
+	for _, m := range invoiceIBANRe().FindAllStringSubmatch(text, -1) {
+		if iban := strings.ReplaceAll(m[1], " ", ""); validIBAN(iban) {
+			inv.IBAN = iban
+			break
+		}
+	}
+
+	switch {
+	case strings.Contains(text, "€") || strings.Contains(text, "EUR"):
+		inv.Currency = "EUR"
+	case strings.Contains(text, "CHF"):
+		inv.Currency = "CHF"
+	case strings.Contains(text, "£") || strings.Contains(text, "GBP"):
+		inv.Currency = "GBP"
+	case strings.Contains(text, "$") || strings.Contains(text, "USD"):
+		inv.Currency = "USD"
+	}
+
+	return inv
+}
+
+// normalizeAmount converts amounts like "1.234,56" or "1,234.56" to "1234.56"
+func normalizeAmount(s string) string {
+	s = strings.NewReplacer(" ", "", "'", "").Replace(strings.TrimSpace(s))
+
+	dot, comma := strings.LastIndex(s, "."), strings.LastIndex(s, ",")
+	switch {
+	case dot >= 0 && comma >= 0:
+		// Whichever separator comes last is the decimal separator
+		if comma > dot {
+			s = strings.ReplaceAll(s, ".", "")
+			s = strings.Replace(s, ",", ".", 1)
+		} else {
+			s = strings.ReplaceAll(s, ",", "")
+		}
+	case comma >= 0:
+		if len(s)-comma == 3 {
+			s = strings.Replace(s, ",", ".", 1)
+		} else {
+			s = strings.ReplaceAll(s, ",", "")
+		}
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// normalizeInvoiceDate converts CII (20240315) and German (15.03.2024)
+// dates to ISO format
+func normalizeInvoiceDate(s string) string {
+	if m := invoiceCompactDateRe().FindStringSubmatch(s); m != nil {
+		return m[1] + "-" + m[2] + "-" + m[3]
+	}
+	if m := invoiceGermanDateRe().FindStringSubmatch(s); m != nil {
+		day, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		return fmt.Sprintf("%s-%02d-%02d", m[3], month, day)
+	}
+	return s
+}
+
+// validIBAN checks the ISO 13616 mod-97 checksum
+func validIBAN(iban string) bool {
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	var digits strings.Builder
+	for _, r := range iban[4:] + iban[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			digits.WriteString(strconv.Itoa(int(r-'A') + 10))
+		default:
+			return false
+		}
+	}
+
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// setInvoiceFields writes the values selected with --set-field into the
+// document's custom fields
+func setInvoiceFields(client *api.Client, id int, data *invoiceData) error {
+	values := make(map[int]any)
+	for _, spec := range invoiceSetFields {
+		key, name, ok := strings.Cut(spec, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid --set-field %q, expected <key>=<field name>", spec)
+		}
+		get, known := invoiceFields[key]
+		if !known {
+			return fmt.Errorf("unknown invoice key %q (valid: %s)", key, strings.Join(invoiceFieldKeys(), ", "))
+		}
+
+		value := get(data)
+		if value == "" {
+			if !isQuiet() {
+				fmt.Fprintf(os.Stderr, "No %s found, not setting %s\n", key, name)
+			}
+			continue
+		}
+
+		field, err := resolveCustomField(client, name)
+		if err != nil {
+			return err
+		}
+		if field.DataType == api.FieldMonetary {
+			value = data.Currency + value
+		}
+		values[field.ID], err = field.ParseValue(value)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	if _, err := client.SetCustomFields(id, values); err != nil {
+		return err
+	}
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Updated %d custom field(s) on document %d\n", len(values), id)
+	}
+	return nil
+}
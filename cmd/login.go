@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/julianfbeck/paperless-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var configLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Log in with username and password to get an API token",
+	Long: `Log in with your Paperless username and password, then save the API token
+the server returns, so it doesn't have to be copied from the web UI. The
+password is only sent to the server and not stored.
+
+The server URL is asked for if none is configured. When stdin isn't a
+terminal, the password is read from it.
+
+Example:
+  paperless config login
+  paperless --url https://paperless.example.com config login --username anna
+  paperless --profile business config login
+  echo "$PASSWORD" | paperless config login --username anna`,
+	Args: cobra.NoArgs,
+	RunE: runConfigLogin,
+}
+
+var loginUsername string
+
+func init() {
+	configCmd.AddCommand(configLoginCmd)
+
+	configLoginCmd.Flags().StringVar(&loginUsername, "username", "", "username (asked for if not given)")
+}
+
+// readLine reads a line of input without its line ending
+func readLine(in *bufio.Reader) (string, error) {
+	line, err := in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// promptLine asks for a line of input on stderr
+func promptLine(in *bufio.Reader, question string) (string, error) {
+	fmt.Fprintf(os.Stderr, "%s: ", question)
+	line, err := readLine(in)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", strings.ToLower(question), err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// promptPassword asks for a password without echoing it. Echo is turned
+// back on if the prompt is interrupted.
+func promptPassword(in *bufio.Reader) (string, error) {
+	if err := setEcho(false); err != nil {
+		return "", fmt.Errorf("can't hide the password input: %w", err)
+	}
+
+	sig := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sig, os.Interrupt)
+	go func() {
+		select {
+		case <-sig:
+			setEcho(true)
+			fmt.Fprintln(os.Stderr)
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+	defer func() {
+		signal.Stop(sig)
+		close(done)
+		setEcho(true)
+		fmt.Fprintln(os.Stderr)
+	}()
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := readLine(in)
+	if err != nil {
+		return "", fmt.Errorf("reading password: %w", err)
+	}
+	return password, nil
+}
+
+func runConfigLogin(cmd *cobra.Command, args []string) error {
+	profile := config.ActiveProfile()
+	settings, err := config.Settings(profile)
+	if err != nil {
+		// Logging in to a new profile creates it
+		cfg, loadErr := config.Load()
+		if loadErr != nil {
+			return fmt.Errorf("failed to load config: %w", loadErr)
+		}
+		settings = *cfg.Current()
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	interactive := isTerminal(os.Stdin)
+
+	saveURL := urlFlag != ""
+	if urlFlag != "" {
+		settings.URL = urlFlag
+	}
+	if settings.URL == "" {
+		if !interactive {
+			return fmt.Errorf("no server URL configured: pass --url")
+		}
+		if settings.URL, err = promptLine(in, "Server URL"); err != nil {
+			return err
+		}
+		if settings.URL == "" {
+			return fmt.Errorf("no server URL given")
+		}
+		saveURL = true
+	}
+
+	username := loginUsername
+	if username == "" {
+		if !interactive {
+			return fmt.Errorf("--username is required when stdin isn't a terminal")
+		}
+		if username, err = promptLine(in, "Username"); err != nil {
+			return err
+		}
+		if username == "" {
+			return fmt.Errorf("no username given")
+		}
+	}
+
+	var password string
+	if interactive {
+		password, err = promptPassword(in)
+	} else if password, err = readLine(in); err != nil {
+		err = fmt.Errorf("reading password: %w", err)
+	}
+	if err != nil {
+		return err
+	}
+	if password == "" {
+		return fmt.Errorf("no password given")
+	}
+
+	settings.Token = ""
+	client, err := connect(profile, settings)
+	if err != nil {
+		return err
+	}
+	token, err := client.ObtainToken(username, password)
+	if err != nil {
+		return err
+	}
+
+	if saveURL {
+		if err := config.SetURL(settings.URL); err != nil {
+			return fmt.Errorf("failed to save URL: %w", err)
+		}
+	}
+	if err := config.SetToken(token); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Logged in to %s as %s, token saved\n", settings.URL, username)
+		if profile == config.DefaultProfile && os.Getenv("PAPERLESS_TOKEN") != "" {
+			fmt.Fprintln(os.Stderr, "Note: PAPERLESS_TOKEN is set and takes precedence over the saved token")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Upload states of the files in a manifest
+const (
+	manifestPending  = "pending"
+	manifestUploaded = "uploaded"
+	manifestFailed   = "failed"
+)
+
+// uploadManifest records the files of a batch upload and how each one went,
+// so that --resume can retry the failed and never attempted ones. It is
+// saved after every file.
+type uploadManifest struct {
+	path string
+	// mu guards Files and the file on disk, as uploads run in parallel
+	mu sync.Mutex
+
+	Created time.Time       `json:"created"`
+	Updated time.Time       `json:"updated"`
+	Files   []manifestEntry `json:"files"`
+}
+
+// manifestEntry is one file of a manifest. The checksum is the MD5 sum
+// Paperless stores for originals, so the manifest can be checked against
+// "paperless documents checksums".
+type manifestEntry struct {
+	File     string `json:"file"`
+	Checksum string `json:"checksum"`
+	TaskID   string `json:"task_id,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	// Warning is what went wrong after an upload, e.g. moving the file
+	Warning string `json:"warning,omitempty"`
+}
+
+// newUploadManifest lists files as pending in a new manifest at path. Files
+// are recorded with absolute paths, so a batch can be resumed from anywhere.
+func newUploadManifest(path string, files []string) (*uploadManifest, error) {
+	m := &uploadManifest{path: path, Created: time.Now()}
+	for _, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		e := manifestEntry{File: abs, Status: manifestPending}
+		if err := e.sum(); err != nil {
+			return nil, err
+		}
+		m.Files = append(m.Files, e)
+	}
+	return m, m.save()
+}
+
+// loadUploadManifest reads the manifest of an earlier upload
+func loadUploadManifest(path string) (*uploadManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &uploadManifest{path: path}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// remaining returns the files that failed or were never attempted. Their
+// checksums are updated, as a file may have been replaced since.
+func (m *uploadManifest) remaining() ([]string, error) {
+	var files []string
+	for i := range m.Files {
+		if m.Files[i].Status == manifestUploaded {
+			continue
+		}
+		if err := m.Files[i].sum(); err != nil {
+			return nil, err
+		}
+		files = append(files, m.Files[i].File)
+	}
+	return files, nil
+}
+
+// sum updates the checksum of the entry's file. A missing file is left
+// without one, for the upload to report.
+func (e *manifestEntry) sum() error {
+	sum, err := md5File(e.File)
+	if os.IsNotExist(err) {
+		e.Checksum = ""
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	e.Checksum = sum
+	return nil
+}
+
+// record saves how the upload of file went
+func (m *uploadManifest) record(file, taskID string, uploadErr error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := m.entry(file)
+	if e == nil {
+		return fmt.Errorf("%s is not in the manifest", file)
+	}
+	e.TaskID = taskID
+	e.Status, e.Error, e.Warning = manifestUploaded, "", ""
+	// A file that was uploaded counts as such, even if moving it failed
+	if taskID == "" {
+		e.Status, e.Error = manifestFailed, uploadErr.Error()
+	} else if uploadErr != nil {
+		e.Warning = uploadErr.Error()
+	}
+	return m.save()
+}
+
+// entry finds the entry of file, given as recorded or relative
+func (m *uploadManifest) entry(file string) *manifestEntry {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		abs = file
+	}
+	for i := range m.Files {
+		if m.Files[i].File == abs {
+			return &m.Files[i]
+		}
+	}
+	return nil
+}
+
+// counts returns how many files are in each state
+func (m *uploadManifest) counts() map[string]int {
+	counts := make(map[string]int)
+	for _, e := range m.Files {
+		counts[e.Status]++
+	}
+	return counts
+}
+
+// save writes the manifest through a temporary file, so an interrupted
+// write never leaves it truncated
+func (m *uploadManifest) save() error {
+	m.Updated = time.Now()
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), ".manifest-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), m.path)
+}
+
+// md5File returns the hex MD5 sum of a file
+func md5File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// matchingFlags set how the server auto-assigns an object to new documents
+type matchingFlags struct {
+	match       string
+	algorithm   string
+	insensitive bool
+}
+
+func (f *matchingFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.match, "match", "", "text to match in new documents")
+	cmd.Flags().StringVar(&f.algorithm, "matching-algorithm", "", "how --match is applied: none, any, all, literal, regex, fuzzy or auto")
+	cmd.Flags().BoolVar(&f.insensitive, "insensitive", true, "match case-insensitively, --insensitive=false to match case")
+	// --algorithm is the short form storage paths had first
+	cmd.Flags().StringVar(&f.algorithm, "algorithm", "", "")
+	cmd.Flags().MarkHidden("algorithm")
+	cmd.RegisterFlagCompletionFunc("matching-algorithm", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var names []string
+		for i := api.MatchNone; i <= api.MatchAuto; i++ {
+			names = append(names, api.MatchingAlgorithmName(i))
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// updates returns the API fields of the flags that were given
+func (f *matchingFlags) updates(cmd *cobra.Command) (map[string]interface{}, error) {
+	updates := make(map[string]interface{})
+	if cmd.Flags().Changed("match") {
+		updates["match"] = f.match
+	}
+	if cmd.Flags().Changed("matching-algorithm") || cmd.Flags().Changed("algorithm") {
+		algorithm, ok := api.ParseMatchingAlgorithm(f.algorithm)
+		if !ok {
+			return nil, fmt.Errorf("invalid --matching-algorithm %q: use none, any, all, literal, regex, fuzzy or auto", f.algorithm)
+		}
+		updates["matching_algorithm"] = algorithm
+	}
+	if cmd.Flags().Changed("insensitive") {
+		updates["is_insensitive"] = f.insensitive
+	}
+	return updates, nil
+}
+
+// matchingLabel describes an object's matching for details output, like
+// `any "invoice bill"`
+func matchingLabel(algorithm int, match string, insensitive bool) string {
+	name := api.MatchingAlgorithmName(algorithm)
+	if algorithm == api.MatchNone || algorithm == api.MatchAuto || match == "" {
+		return name
+	}
+	label := fmt.Sprintf("%s %q", name, match)
+	if !insensitive {
+		label += " (case-sensitive)"
+	}
+	return label
+}
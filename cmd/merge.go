@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var docsMergeCmd = &cobra.Command{
+	Use:   "merge <id> <id>...",
+	Short: "Merge documents into one",
+	Long: `Merge two or more documents into a new document, in the order given, so
+multi-part scans can be combined after upload.
+
+The server does the merge in the background and consumes the result like an
+upload. The new document takes its metadata from --metadata-from, which must
+be one of the merged documents. --delete-originals deletes the merged
+documents once the new one exists; it asks for confirmation first and
+refuses documents on legal hold (see 'config set hold') unless
+--override-hold is given.
+
+Example:
+  paperless documents merge 12 13 14
+  paperless documents merge 12 13 14 --delete-originals --metadata-from 12`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runDocsMerge,
+}
+
+var (
+	mergeMetadataFrom    int
+	mergeDeleteOriginals bool
+	mergeForce           bool
+)
+
+func init() {
+	documentsCmd.AddCommand(docsMergeCmd)
+
+	docsMergeCmd.Flags().IntVar(&mergeMetadataFrom, "metadata-from", 0, "document ID to copy title, tags and other metadata from")
+	docsMergeCmd.Flags().BoolVar(&mergeDeleteOriginals, "delete-originals", false, "delete the merged documents afterwards")
+	docsMergeCmd.Flags().BoolVarP(&mergeForce, "force", "f", false, "skip confirmation")
+	addOverrideHoldFlag(docsMergeCmd)
+}
+
+func runDocsMerge(cmd *cobra.Command, args []string) error {
+	ids, err := parseDocumentIDs(args)
+	if err != nil {
+		return err
+	}
+	if len(ids) < 2 {
+		return fmt.Errorf("give at least two different documents to merge")
+	}
+
+	var metadataFrom *int
+	if cmd.Flags().Changed("metadata-from") {
+		if !slices.Contains(ids, mergeMetadataFrom) {
+			return fmt.Errorf("--metadata-from must be one of the merged documents")
+		}
+		metadataFrom = &mergeMetadataFrom
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	if err := client.RequireFeature(api.FeaturePDFEditing); err != nil {
+		return err
+	}
+
+	if mergeDeleteOriginals {
+		hold, err := loadLegalHold(client)
+		if err != nil {
+			return err
+		}
+		if !overrideHold && hold.active() {
+			held, err := hold.heldIDs(client)
+			if err != nil {
+				return err
+			}
+			var onHold []int
+			for _, id := range ids {
+				if held[id] {
+					onHold = append(onHold, id)
+				}
+			}
+			if len(onHold) == 1 {
+				return fmt.Errorf("document %d is on legal hold (%s) and can't be deleted; pass --override-hold or drop --delete-originals", onHold[0], hold.name)
+			}
+			if len(onHold) > 1 {
+				return fmt.Errorf("documents %s are on legal hold (%s) and can't be deleted; pass --override-hold or drop --delete-originals", joinInts(onHold, ", "), hold.name)
+			}
+		}
+
+		if !mergeForce && !confirmAction(fmt.Sprintf("Merge documents %s and delete the originals?", joinInts(ids, ", "))) {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	if err := client.BulkEditDocuments(ids, api.BulkMerge(metadataFrom, mergeDeleteOriginals)); err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(map[string]interface{}{
+			"merged":           ids,
+			"metadata_from":    metadataFrom,
+			"delete_originals": mergeDeleteOriginals,
+			"queued":           true,
+		})
+	}
+	if !isQuiet() {
+		fmt.Printf("Merge of documents %s queued\n", joinInts(ids, ", "))
+	}
+	return nil
+}
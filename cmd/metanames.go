@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+)
+
+// metadataNames maps tag, correspondent, document type and storage path IDs
+// to names for display. Each list is fetched on first use, usually from the
+// cache, and best effort: IDs that can't be resolved are shown as they are.
+// A nil *metadataNames shows all IDs, for --raw.
+type metadataNames struct {
+	client         *api.Client
+	tags           map[int]string
+	correspondents map[int]string
+	documentTypes  map[int]string
+	storagePaths   map[int]string
+}
+
+func newMetadataNames(client *api.Client) *metadataNames {
+	return &metadataNames{client: client}
+}
+
+func (n *metadataNames) tag(id int) string {
+	if n == nil {
+		return strconv.Itoa(id)
+	}
+	if n.tags == nil {
+		n.tags = map[int]string{}
+		if tags, err := n.client.ListTags(api.ListParams{}); err == nil {
+			for _, t := range tags.Results {
+				n.tags[t.ID] = t.Name
+			}
+		}
+	}
+	return nameOrID(n.tags, id)
+}
+
+// tagList joins the names of tags, like "bills, tax"
+func (n *metadataNames) tagList(ids []int) string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = n.tag(id)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (n *metadataNames) correspondent(id int) string {
+	if n == nil {
+		return strconv.Itoa(id)
+	}
+	if n.correspondents == nil {
+		n.correspondents = map[int]string{}
+		if corrs, err := n.client.ListCorrespondents(api.ListParams{}); err == nil {
+			for _, c := range corrs.Results {
+				n.correspondents[c.ID] = c.Name
+			}
+		}
+	}
+	return nameOrID(n.correspondents, id)
+}
+
+func (n *metadataNames) documentType(id int) string {
+	if n == nil {
+		return strconv.Itoa(id)
+	}
+	if n.documentTypes == nil {
+		n.documentTypes = map[int]string{}
+		if types, err := n.client.ListDocumentTypes(api.ListParams{}); err == nil {
+			for _, dt := range types.Results {
+				n.documentTypes[dt.ID] = dt.Name
+			}
+		}
+	}
+	return nameOrID(n.documentTypes, id)
+}
+
+func (n *metadataNames) storagePath(id int) string {
+	if n == nil {
+		return strconv.Itoa(id)
+	}
+	if n.storagePaths == nil {
+		n.storagePaths = map[int]string{}
+		if paths, err := n.client.ListStoragePaths(api.ListParams{}); err == nil {
+			for _, sp := range paths.Results {
+				n.storagePaths[sp.ID] = sp.Name
+			}
+		}
+	}
+	return nameOrID(n.storagePaths, id)
+}
+
+func nameOrID(names map[int]string, id int) string {
+	if name, ok := names[id]; ok {
+		return name
+	}
+	return strconv.Itoa(id)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// missingFlags let upload and edit create tags, correspondents and document
+// types they are given by a name that doesn't exist yet
+type missingFlags struct {
+	create bool
+	ask    bool
+}
+
+// createMissing is shared by the commands that register it, as only one of
+// them runs at a time. Left unset, unknown names fail as before.
+var createMissing missingFlags
+
+func (f *missingFlags) register(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&f.create, "create-missing", false, "create tags, correspondents and document types that don't exist yet")
+	cmd.Flags().BoolVar(&f.ask, "ask-create", false, "like --create-missing, but ask before creating each")
+}
+
+// allow reports whether a missing object may be created, asking first if
+// requested
+func (f *missingFlags) allow(kind, name string) bool {
+	if !f.create && !f.ask {
+		return false
+	}
+	if f.ask {
+		return confirmAction(fmt.Sprintf("Create %s %q?", kind, name))
+	}
+	return true
+}
+
+// created notes an object created on the fly
+func (f *missingFlags) created(kind, name string, id int) {
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Created %s %s (ID %d)\n", kind, name, id)
+	}
+}
+
+// resolveTag finds a tag by ID or name, creating it if allowed
+func resolveTag(client *api.Client, arg string) (int, error) {
+	if id, err := strconv.Atoi(arg); err == nil {
+		return id, nil
+	}
+	tag, err := client.FindTagByName(arg)
+	if err == nil {
+		return tag.ID, nil
+	}
+	if !api.IsNotFound(err) || !createMissing.allow("tag", arg) {
+		return 0, err
+	}
+	tag, err = client.CreateTag(arg, "", nil)
+	if err != nil {
+		return 0, fmt.Errorf("creating tag %s: %w", arg, err)
+	}
+	createMissing.created("tag", tag.Name, tag.ID)
+	return tag.ID, nil
+}
+
+// resolveCorrespondent finds a correspondent by ID or name, creating it if
+// allowed
+func resolveCorrespondent(client *api.Client, arg string) (int, error) {
+	if id, err := strconv.Atoi(arg); err == nil {
+		return id, nil
+	}
+	corr, err := client.FindCorrespondentByName(arg)
+	if err == nil {
+		return corr.ID, nil
+	}
+	if !api.IsNotFound(err) || !createMissing.allow("correspondent", arg) {
+		return 0, err
+	}
+	corr, err = client.CreateCorrespondent(arg, nil)
+	if err != nil {
+		return 0, fmt.Errorf("creating correspondent %s: %w", arg, err)
+	}
+	createMissing.created("correspondent", corr.Name, corr.ID)
+	return corr.ID, nil
+}
+
+// resolveDocumentType finds a document type by ID or name, creating it if
+// allowed
+func resolveDocumentType(client *api.Client, arg string) (int, error) {
+	if id, err := strconv.Atoi(arg); err == nil {
+		return id, nil
+	}
+	dt, err := client.FindDocumentTypeByName(arg)
+	if err == nil {
+		return dt.ID, nil
+	}
+	if !api.IsNotFound(err) || !createMissing.allow("document type", arg) {
+		return 0, err
+	}
+	dt, err = client.CreateDocumentType(arg, nil)
+	if err != nil {
+		return 0, fmt.Errorf("creating document type %s: %w", arg, err)
+	}
+	createMissing.created("document type", dt.Name, dt.ID)
+	return dt.ID, nil
+}
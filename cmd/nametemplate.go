@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+)
+
+// downloadName holds the document metadata a --name-template can use. IDs
+// are resolved to names; values are made safe to use in file names.
+type downloadName struct {
+	ID            int
+	Title         string
+	Correspondent string
+	DocumentType  string
+	StoragePath   string
+	Tags          []string
+	// Created is the creation date as YYYY-MM-DD
+	Created string
+	Year    string
+	ASN     string
+	// OriginalName is the uploaded file name without its extension
+	OriginalName string
+}
+
+// nameTemplateFuncs are available in --name-template besides the builtins
+var nameTemplateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// parseNameTemplate parses a Go template for download file names, like
+// "{{.Created}}_{{.Correspondent}}_{{.Title}}.pdf"
+func parseNameTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("name").Funcs(nameTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --name-template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// expandNameTemplate renders a relative file name for doc. Slashes in the
+// template create directories, those in metadata don't.
+func expandNameTemplate(client *api.Client, tmpl *template.Template, doc *api.Document) (string, error) {
+	data := downloadName{
+		ID:           doc.ID,
+		Title:        safeNamePart(doc.Title),
+		Created:      firstNonEmpty(doc.CreatedDate, doc.Created.Format("2006-01-02")),
+		OriginalName: safeNamePart(strings.TrimSuffix(doc.OriginalFileName, filepath.Ext(doc.OriginalFileName))),
+	}
+	data.Year, _, _ = strings.Cut(data.Created, "-")
+	if doc.ArchiveSerialNumber != nil {
+		data.ASN = strconv.Itoa(*doc.ArchiveSerialNumber)
+	}
+
+	if doc.Correspondent != nil {
+		corr, err := client.GetCorrespondent(*doc.Correspondent)
+		if err != nil {
+			return "", err
+		}
+		data.Correspondent = safeNamePart(corr.Name)
+	}
+	if doc.DocumentType != nil {
+		dt, err := client.GetDocumentType(*doc.DocumentType)
+		if err != nil {
+			return "", err
+		}
+		data.DocumentType = safeNamePart(dt.Name)
+	}
+	if doc.StoragePath != nil {
+		sp, err := client.GetStoragePath(*doc.StoragePath)
+		if err != nil {
+			return "", err
+		}
+		data.StoragePath = safeNamePart(sp.Name)
+	}
+	for _, id := range doc.Tags {
+		tag, err := client.GetTag(id)
+		if err != nil {
+			return "", err
+		}
+		data.Tags = append(data.Tags, safeNamePart(tag.Name))
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("--name-template: %w", err)
+	}
+	name := filepath.Clean(strings.TrimSpace(buf.String()))
+	if name == "." || strings.HasSuffix(buf.String(), "/") {
+		return "", fmt.Errorf("--name-template gave no file name for document %d", doc.ID)
+	}
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("--name-template gave %q for document %d, which is not a relative path inside the current directory", name, doc.ID)
+	}
+	return name, nil
+}
+
+// withExtension adds the extension of the server's filename to a templated
+// name unless it ends with it already. Titles may contain dots, so any other
+// "extension" is kept as part of the name.
+func withExtension(name, filename string) string {
+	ext := filepath.Ext(filename)
+	if strings.EqualFold(filepath.Ext(name), ext) {
+		return name
+	}
+	return name + ext
+}
+
+// safeNamePart replaces characters that aren't allowed in file names on
+// common systems
+func safeNamePart(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case strings.ContainsRune(`/\:*?"<>|`, r), r < 0x20:
+			return '_'
+		}
+		return r
+	}, s)
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var docsOpenCmd = &cobra.Command{
+	Use:   "open <id>",
+	Short: "Open a document in the local PDF viewer",
+	Long: `Download a document to a temporary file and open it with the system's
+default viewer, for a quick look without keeping a copy.
+
+The archived version is opened unless --original is given. On a terminal,
+the command waits until you press Enter and then removes the temporary
+file; otherwise the file is left in the temporary directory.
+
+Example:
+  paperless documents open 123
+  paperless documents open 123 --original`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDocsOpen,
+}
+
+var openOriginal bool
+
+func init() {
+	documentsCmd.AddCommand(docsOpenCmd)
+
+	docsOpenCmd.Flags().BoolVar(&openOriginal, "original", false, "open the original file instead of the archived version")
+}
+
+func runDocsOpen(cmd *cobra.Command, args []string) error {
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid document ID: %s", args[0])
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "paperless-open-*")
+	if err != nil {
+		return err
+	}
+	path, err := downloadForViewing(client, id, dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+
+	if err := openBrowser(path); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+
+	// The viewer usually runs detached, so the file is kept until the user
+	// is done with it. Without anyone to ask, it's left behind.
+	keep := func() error {
+		if !isQuiet() {
+			fmt.Fprintf(os.Stderr, "Opened %s\n", path)
+		}
+		return nil
+	}
+	if !isTerminal(os.Stdin) {
+		return keep()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	read := make(chan error, 1)
+	fmt.Fprintf(os.Stderr, "Opened %s; press Enter to remove it when done ", path)
+	go func() {
+		_, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		read <- err
+	}()
+	select {
+	case err := <-read:
+		if err != nil {
+			// Nothing to read after all, so the file stays
+			fmt.Fprintln(os.Stderr)
+			return nil
+		}
+	case <-ctx.Done():
+		fmt.Fprintln(os.Stderr)
+	}
+	return os.RemoveAll(dir)
+}
+
+// downloadForViewing downloads a document into dir under the server's file
+// name and returns its path
+func downloadForViewing(client *api.Client, id int, dir string) (string, error) {
+	tmp, err := os.CreateTemp(dir, ".paperless-download-*")
+	if err != nil {
+		return "", err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	progress, done := newProgressBar(fmt.Sprintf("Document %d", id))
+	info, err := client.DownloadDocumentTo(ctx, id, tmp, api.DownloadOptions{
+		Original: openOriginal,
+		Progress: progress,
+	})
+	done()
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	name := filepath.Base(info.Filename)
+	if name == "." || name == "/" || name == "" {
+		name = fmt.Sprintf("document_%d.pdf", id)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"sync"
+	"time"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+)
+
+const (
+	// initialParallel is the concurrency bulk operations start with
+	initialParallel = 2
+	// slowFactor marks a request as degraded when it takes this many times
+	// longer than the running latency baseline
+	slowFactor = 3
+	// maxThrottleRetries is how often a throttled item is retried
+	maxThrottleRetries = 3
+	// sizeUnit is the amount of data forEachParallelSized measures latency
+	// per; smaller items count as a whole unit, as the request overhead
+	// dominates for them
+	sizeUnit = 1 << 20
+)
+
+// adaptiveLimiter bounds the number of in-flight requests. The limit is
+// halved whenever the server throttles (429/503) or latency spikes and grows
+// by one after a run of healthy requests, never exceeding max.
+type adaptiveLimiter struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	limit    int
+	max      int
+	active   int
+	healthy  int
+	baseline time.Duration
+}
+
+func newAdaptiveLimiter(max int) *adaptiveLimiter {
+	if max < 1 {
+		max = 1
+	}
+	l := &adaptiveLimiter{limit: min(initialParallel, max), max: max}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// acquire blocks until a worker slot is free
+func (l *adaptiveLimiter) acquire() {
+	l.mu.Lock()
+	for l.active >= l.limit {
+		l.cond.Wait()
+	}
+	l.active++
+	l.mu.Unlock()
+}
+
+// release frees a slot and adjusts the limit based on how the request went
+func (l *adaptiveLimiter) release(elapsed time.Duration, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.active--
+	slow := l.baseline > 0 && elapsed > slowFactor*l.baseline
+
+	if api.IsThrottled(err) || slow {
+		l.limit = max(1, l.limit/2)
+		l.healthy = 0
+	} else {
+		if l.baseline == 0 {
+			l.baseline = elapsed
+		} else {
+			l.baseline = (4*l.baseline + elapsed) / 5
+		}
+		l.healthy++
+		if l.healthy >= l.limit && l.limit < l.max {
+			l.limit++
+			l.healthy = 0
+		}
+	}
+
+	l.cond.Broadcast()
+}
+
+// forEachParallel calls fn with the index and value of every item, running
+// up to maxWorkers calls at once. Concurrency adapts to server latency and
+// throttling; throttled items are retried with backoff. Errors are returned
+// in the same order as items.
+func forEachParallel[T any](items []T, maxWorkers int, fn func(int, T) error) []error {
+	return forEachParallelSized(items, maxWorkers, nil, fn)
+}
+
+// forEachParallelSized is forEachParallel for items of very different sizes,
+// such as uploads. size returns the size of the i-th item in bytes, and
+// latency is measured per sizeUnit so large items don't pass for a slow
+// server.
+func forEachParallelSized[T any](items []T, maxWorkers int, size func(int) int64, fn func(int, T) error) []error {
+	limiter := newAdaptiveLimiter(maxWorkers)
+	errs := make([]error, len(items))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < limiter.max; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				for attempt := 0; ; attempt++ {
+					limiter.acquire()
+					start := time.Now()
+					err := fn(i, items[i])
+					elapsed := time.Since(start)
+					if size != nil {
+						elapsed = time.Duration(float64(elapsed) * sizeUnit / float64(max(size(i), sizeUnit)))
+					}
+					limiter.release(elapsed, err)
+
+					if !api.IsThrottled(err) || attempt == maxThrottleRetries {
+						errs[i] = err
+						break
+					}
+					time.Sleep(time.Duration(1<<attempt) * time.Second)
+				}
+			}
+		}()
+	}
+
+	for i := range items {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return errs
+}
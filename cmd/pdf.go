@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/ledongthuc/pdf"
 	"github.com/spf13/cobra"
@@ -38,10 +42,28 @@ Example:
 	RunE: runPDFInfo,
 }
 
+var pdfAttachmentsCmd = &cobra.Command{
+	Use:   "attachments <file>",
+	Short: "List or extract embedded attachments",
+	Long: `List files embedded in a PDF, such as the XML invoice data of ZUGFeRD or
+Factur-X invoices, and optionally extract them.
+
+Example:
+  paperless pdf attachments invoice.pdf
+  paperless pdf attachments invoice.pdf --extract ./attachments`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPDFAttachments,
+}
+
+var attachmentsExtractDir string
+
 func init() {
 	rootCmd.AddCommand(pdfCmd)
 	pdfCmd.AddCommand(pdfReadCmd)
 	pdfCmd.AddCommand(pdfInfoCmd)
+	pdfCmd.AddCommand(pdfAttachmentsCmd)
+
+	pdfAttachmentsCmd.Flags().StringVar(&attachmentsExtractDir, "extract", "", "write attachments to this directory")
 }
 
 func runPDFRead(cmd *cobra.Command, args []string) error {
@@ -131,3 +153,168 @@ func extractPDFText(filePath string) (string, error) {
 
 	return textBuilder.String(), nil
 }
+
+func runPDFAttachments(cmd *cobra.Command, args []string) error {
+	filePath := args[0]
+
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		return fmt.Errorf("file not found: %s", filePath)
+	}
+
+	attachments, err := readPDFAttachments(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read PDF: %w", err)
+	}
+
+	if attachmentsExtractDir != "" {
+		if err := os.MkdirAll(attachmentsExtractDir, 0755); err != nil {
+			return err
+		}
+		for i := range attachments {
+			// Never trust embedded names with directory components
+			outPath := filepath.Join(attachmentsExtractDir, filepath.Base(attachments[i].Name))
+			if err := os.WriteFile(outPath, attachments[i].data, 0644); err != nil {
+				return fmt.Errorf("failed to write attachment: %w", err)
+			}
+			attachments[i].Path = outPath
+		}
+	}
+
+	if isJSON() {
+		return printJSON(attachments)
+	}
+
+	if len(attachments) == 0 {
+		fmt.Println("No attachments found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSIZE\tTYPE\tDESCRIPTION")
+	for _, a := range attachments {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", a.Name, a.Size, a.MimeType, truncate(a.Description, 40))
+	}
+	w.Flush()
+
+	if attachmentsExtractDir != "" && !isQuiet() {
+		fmt.Fprintf(os.Stderr, "\nExtracted %d attachment(s) to %s\n", len(attachments), attachmentsExtractDir)
+	}
+
+	return nil
+}
+
+// pdfAttachment is a file embedded in a PDF
+type pdfAttachment struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mime_type,omitempty"`
+	Size        int    `json:"size"`
+	Path        string `json:"path,omitempty"`
+	data        []byte
+}
+
+// isXML reports whether the attachment holds XML data
+func (a pdfAttachment) isXML() bool {
+	return strings.HasSuffix(strings.ToLower(a.Name), ".xml") || strings.Contains(a.MimeType, "xml")
+}
+
+// readPDFAttachments reads all files from the document's EmbeddedFiles name tree
+func readPDFAttachments(filePath string) ([]pdfAttachment, error) {
+	f, r, err := pdf.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tree := r.Trailer().Key("Root").Key("Names").Key("EmbeddedFiles")
+	var attachments []pdfAttachment
+	collectPDFAttachments(tree, &attachments, 0)
+	return attachments, nil
+}
+
+// collectPDFAttachments walks a name tree node and its kids
+func collectPDFAttachments(node pdf.Value, out *[]pdfAttachment, depth int) {
+	if node.IsNull() || depth > 32 {
+		return
+	}
+
+	names := node.Key("Names")
+	for i := 0; i+1 < names.Len(); i += 2 {
+		if a, ok := readPDFFileSpec(names.Index(i).Text(), names.Index(i+1)); ok {
+			*out = append(*out, a)
+		}
+	}
+
+	kids := node.Key("Kids")
+	for i := 0; i < kids.Len(); i++ {
+		collectPDFAttachments(kids.Index(i), out, depth+1)
+	}
+}
+
+// readPDFFileSpec reads the embedded stream of a file specification
+func readPDFFileSpec(treeName string, spec pdf.Value) (pdfAttachment, bool) {
+	stream := spec.Key("EF").Key("UF")
+	if stream.IsNull() {
+		stream = spec.Key("EF").Key("F")
+	}
+	if stream.IsNull() {
+		return pdfAttachment{}, false
+	}
+
+	rc := stream.Reader()
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return pdfAttachment{}, false
+	}
+
+	name := spec.Key("UF").Text()
+	if name == "" {
+		name = spec.Key("F").Text()
+	}
+	if name == "" {
+		name = treeName
+	}
+
+	return pdfAttachment{
+		Name:        name,
+		Description: spec.Key("Desc").Text(),
+		MimeType:    stream.Key("Subtype").Name(),
+		Size:        len(data),
+		data:        data,
+	}, true
+}
+
+// pdfWriter builds a small PDF from scratch, one numbered object at a time.
+// Object 1 must be the catalog.
+type pdfWriter struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+func newPDFWriter() *pdfWriter {
+	w := &pdfWriter{}
+	w.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	return w
+}
+
+// object appends the next object and returns its number
+func (w *pdfWriter) object(format string, a ...interface{}) int {
+	w.offsets = append(w.offsets, w.buf.Len())
+	n := len(w.offsets)
+	fmt.Fprintf(&w.buf, "%d 0 obj\n", n)
+	fmt.Fprintf(&w.buf, format, a...)
+	w.buf.WriteString("\nendobj\n")
+	return n
+}
+
+// bytes finishes the file with the cross-reference table
+func (w *pdfWriter) bytes() []byte {
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, off := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.offsets)+1, xref)
+	return w.buf.Bytes()
+}
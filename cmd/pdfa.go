@@ -0,0 +1,464 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/ledongthuc/pdf"
+	"github.com/spf13/cobra"
+)
+
+var pdfValidateACmd = &cobra.Command{
+	Use:   "validate-a <file|document id>",
+	Short: "Check PDF/A conformance",
+	Long: `Check whether a local PDF or the archived file of a document conforms to
+PDF/A. Exits with an error if it doesn't.
+
+If veraPDF is installed (the verapdf command is in PATH), it does a full
+validation against the flavour the file declares. Otherwise built-in checks
+cover the most common problems: the PDF/A declaration in the XMP metadata,
+output intent, encryption, JavaScript, embedded files and unembedded fonts.
+They can't prove conformance, only find violations.
+
+Example:
+  paperless pdf validate-a scan.pdf
+  paperless pdf validate-a 123
+  paperless pdf validate-a 123 --validator builtin --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPDFValidateA,
+}
+
+var docsPDFAReportCmd = &cobra.Command{
+	Use:   "pdfa-report",
+	Short: "List archived files that aren't valid PDF/A",
+	Long: `Download the archived file of every matching document, check its PDF/A
+conformance like "paperless pdf validate-a" and list the ones that fail.
+Documents without an archived version are skipped.
+
+Example:
+  paperless documents pdfa-report
+  paperless documents pdfa-report --tag scans --validator verapdf --json`,
+	Args: cobra.NoArgs,
+	RunE: runDocsPDFAReport,
+}
+
+var (
+	pdfaValidator string
+
+	pdfaReportQuery         string
+	pdfaReportTags          []string
+	pdfaReportCorrespondent string
+	pdfaReportDocType       string
+)
+
+func init() {
+	pdfCmd.AddCommand(pdfValidateACmd)
+	documentsCmd.AddCommand(docsPDFAReportCmd)
+
+	for _, c := range []*cobra.Command{pdfValidateACmd, docsPDFAReportCmd} {
+		c.Flags().StringVar(&pdfaValidator, "validator", "auto", "auto (veraPDF if installed), verapdf or builtin")
+	}
+
+	docsPDFAReportCmd.Flags().StringVar(&pdfaReportQuery, "query", "", "search query")
+	docsPDFAReportCmd.RegisterFlagCompletionFunc("query", completeQuery)
+	docsPDFAReportCmd.Flags().StringArrayVar(&pdfaReportTags, "tag", nil, "filter by tag (repeatable)")
+	docsPDFAReportCmd.Flags().StringVar(&pdfaReportCorrespondent, "correspondent", "", "filter by correspondent")
+	docsPDFAReportCmd.Flags().StringVar(&pdfaReportDocType, "type", "", "filter by document type")
+}
+
+// pdfaResult is the outcome of a PDF/A check
+type pdfaResult struct {
+	File     string `json:"file,omitempty"`
+	Document int    `json:"document,omitempty"`
+	Title    string `json:"title,omitempty"`
+	// Validator is "verapdf" or "builtin"
+	Validator string `json:"validator"`
+	// Flavour is the PDF/A part and level checked, like "PDF/A-2B"
+	Flavour   string   `json:"flavour,omitempty"`
+	Compliant bool     `json:"compliant"`
+	Issues    []string `json:"issues,omitempty"`
+}
+
+// pdfaValidatorName resolves the --validator flag
+func pdfaValidatorName() (string, error) {
+	switch pdfaValidator {
+	case "auto":
+		if _, err := exec.LookPath("verapdf"); err == nil {
+			return "verapdf", nil
+		}
+		return "builtin", nil
+	case "verapdf":
+		if _, err := exec.LookPath("verapdf"); err != nil {
+			return "", fmt.Errorf("verapdf not found in PATH")
+		}
+		return "verapdf", nil
+	case "builtin":
+		return "builtin", nil
+	}
+	return "", fmt.Errorf("invalid validator: %s (use auto, verapdf or builtin)", pdfaValidator)
+}
+
+// validatePDFA checks a local file with the given validator
+func validatePDFA(path, validator string) (*pdfaResult, error) {
+	if validator == "verapdf" {
+		return validatePDFAVeraPDF(path)
+	}
+	return validatePDFABuiltin(path)
+}
+
+func runPDFValidateA(cmd *cobra.Command, args []string) error {
+	validator, err := pdfaValidatorName()
+	if err != nil {
+		return err
+	}
+
+	var result *pdfaResult
+	if _, statErr := os.Stat(args[0]); statErr == nil {
+		if result, err = validatePDFA(args[0], validator); err != nil {
+			return err
+		}
+		result.File = args[0]
+	} else if id, convErr := strconv.Atoi(args[0]); convErr == nil {
+		client, err := getClient()
+		if err != nil {
+			return err
+		}
+		doc, err := client.GetDocument(id)
+		if err != nil {
+			return err
+		}
+		if doc.ArchivedFileName == "" {
+			return fmt.Errorf("document %d has no archived version", id)
+		}
+		if result, err = validateArchivedPDFA(client, *doc, validator); err != nil {
+			return err
+		}
+	} else {
+		return fmt.Errorf("file not found: %s", args[0])
+	}
+
+	if isJSON() {
+		if err := printJSON(result); err != nil {
+			return err
+		}
+	} else {
+		printPDFAResult(result)
+	}
+
+	if !result.Compliant {
+		return fmt.Errorf("not PDF/A conformant")
+	}
+	return nil
+}
+
+func printPDFAResult(r *pdfaResult) {
+	flavour := r.Flavour
+	if flavour == "" {
+		flavour = "none declared"
+	}
+	verdict := "conformant"
+	switch {
+	case !r.Compliant:
+		verdict = "NOT conformant"
+	case r.Validator == "builtin":
+		verdict = "no problems found (basic checks only)"
+	}
+
+	if r.Document > 0 {
+		fmt.Printf("Document:  %d (%s)\n", r.Document, r.Title)
+	} else {
+		fmt.Printf("File:      %s\n", r.File)
+	}
+	fmt.Printf("PDF/A:     %s\n", flavour)
+	fmt.Printf("Validator: %s\n", r.Validator)
+	fmt.Printf("Result:    %s\n", verdict)
+	for _, issue := range r.Issues {
+		fmt.Printf("  - %s\n", issue)
+	}
+}
+
+// validateArchivedPDFA downloads a document's archived file and checks it
+func validateArchivedPDFA(client *api.Client, doc api.Document, validator string) (*pdfaResult, error) {
+	tmp, err := os.CreateTemp("", "paperless-pdfa-*.pdf")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = client.DownloadDocumentTo(context.Background(), doc.ID, tmp, api.DownloadOptions{})
+	tmp.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := validatePDFA(tmp.Name(), validator)
+	if err != nil {
+		return nil, err
+	}
+	result.Document = doc.ID
+	result.Title = doc.Title
+	return result, nil
+}
+
+func runDocsPDFAReport(cmd *cobra.Command, args []string) error {
+	validator, err := pdfaValidatorName()
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	params := api.DocumentListParams{
+		Query:         pdfaReportQuery,
+		Tags:          pdfaReportTags,
+		Correspondent: pdfaReportCorrespondent,
+		DocumentType:  pdfaReportDocType,
+		Limit:         100,
+		Ordering:      "id",
+	}
+
+	sd := handleShutdown()
+	defer sd.release()
+
+	failing := []*pdfaResult{}
+	checked, failed := 0, 0
+	stopped := false
+	for page := 1; ; page++ {
+		if sd.requested() {
+			stopped = true
+			break
+		}
+
+		params.Page = page
+		result, err := client.ListDocuments(params)
+		if err != nil {
+			return err
+		}
+
+		var docs []api.Document
+		for _, doc := range result.Results {
+			if doc.ArchivedFileName != "" {
+				docs = append(docs, doc)
+			}
+		}
+
+		results := make([]*pdfaResult, len(docs))
+		errs := forEachParallel(docs, maxPar, func(i int, doc api.Document) error {
+			r, err := validateArchivedPDFA(client, doc, validator)
+			results[i] = r
+			return err
+		})
+
+		for i, r := range results {
+			if errs[i] != nil {
+				failed++
+				fmt.Fprintf(os.Stderr, "document %d: %v\n", docs[i].ID, errs[i])
+				continue
+			}
+			checked++
+			if !r.Compliant {
+				failing = append(failing, r)
+			}
+		}
+
+		if result.Next == "" {
+			break
+		}
+	}
+
+	if isJSON() {
+		if err := printJSON(failing); err != nil {
+			return err
+		}
+	} else if len(failing) > 0 {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTITLE\tPDF/A\tISSUES")
+		for _, r := range failing {
+			flavour := r.Flavour
+			if flavour == "" {
+				flavour = "-"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", r.Document, truncate(r.Title, 40), flavour, truncate(strings.Join(r.Issues, "; "), 80))
+		}
+		w.Flush()
+	}
+
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Checked %d archived document(s) with %s, %d not conformant\n", checked, validator, len(failing))
+	}
+	if stopped {
+		return interrupted(cmd, "Interrupted, the results are partial")
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to check %d document(s)", failed)
+	}
+	return nil
+}
+
+// veraPDFReport is the part of veraPDF's machine readable report we use
+type veraPDFReport struct {
+	Jobs []struct {
+		Report *struct {
+			Profile   string `xml:"profileName,attr"`
+			Compliant bool   `xml:"isCompliant,attr"`
+			Rules     []struct {
+				Clause      string `xml:"clause,attr"`
+				Status      string `xml:"status,attr"`
+				Description string `xml:"description"`
+			} `xml:"details>rule"`
+		} `xml:"validationReport"`
+		Exception string `xml:"taskException>exceptionMessage"`
+	} `xml:"jobs>job"`
+}
+
+func validatePDFAVeraPDF(path string) (*pdfaResult, error) {
+	var stdout, stderr bytes.Buffer
+	run := exec.Command("verapdf", "--format", "mrr", path)
+	run.Stdout, run.Stderr = &stdout, &stderr
+	err := run.Run()
+
+	// Exit status 1 only means the file isn't conformant
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+		return nil, fmt.Errorf("verapdf: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var report veraPDFReport
+	if err := xml.Unmarshal(stdout.Bytes(), &report); err != nil {
+		return nil, fmt.Errorf("reading verapdf report: %w", err)
+	}
+	if len(report.Jobs) == 0 || report.Jobs[0].Report == nil {
+		if len(report.Jobs) > 0 && report.Jobs[0].Exception != "" {
+			return nil, fmt.Errorf("verapdf: %s", report.Jobs[0].Exception)
+		}
+		return nil, fmt.Errorf("verapdf returned no validation report")
+	}
+
+	vr := report.Jobs[0].Report
+	result := &pdfaResult{
+		Validator: "verapdf",
+		Flavour:   strings.TrimSuffix(vr.Profile, " validation profile"),
+		Compliant: vr.Compliant,
+	}
+	for _, rule := range vr.Rules {
+		if rule.Status == "failed" {
+			result.Issues = append(result.Issues, fmt.Sprintf("%s: %s", rule.Clause, strings.TrimSpace(rule.Description)))
+		}
+	}
+	return result, nil
+}
+
+var (
+	pdfaPartRe        = lazyRegexp(`pdfaid:part(?:="|'|>)\s*(\d)`)
+	pdfaConformanceRe = lazyRegexp(`pdfaid:conformance(?:="|'|>)\s*([A-Za-z])`)
+)
+
+func validatePDFABuiltin(path string) (*pdfaResult, error) {
+	f, r, err := pdf.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	defer f.Close()
+
+	result := &pdfaResult{Validator: "builtin"}
+	issue := func(format string, a ...interface{}) {
+		result.Issues = append(result.Issues, fmt.Sprintf(format, a...))
+	}
+
+	trailer := r.Trailer()
+	root := trailer.Key("Root")
+
+	part := 0
+	if meta := root.Key("Metadata"); meta.IsNull() {
+		issue("no XMP metadata")
+	} else {
+		rc := meta.Reader()
+		xmp, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read XMP metadata: %w", err)
+		}
+		if m := pdfaPartRe().FindSubmatch(xmp); m != nil {
+			part, _ = strconv.Atoi(string(m[1]))
+			result.Flavour = "PDF/A-" + string(m[1])
+			if m := pdfaConformanceRe().FindSubmatch(xmp); m != nil {
+				result.Flavour += strings.ToUpper(string(m[1]))
+			}
+		} else {
+			issue("XMP metadata doesn't declare PDF/A (pdfaid:part)")
+		}
+	}
+
+	if !trailer.Key("Encrypt").IsNull() {
+		issue("file is encrypted")
+	}
+	if trailer.Key("ID").Len() == 0 {
+		issue("trailer has no file identifier (ID)")
+	}
+
+	hasIntent := false
+	intents := root.Key("OutputIntents")
+	for i := 0; i < intents.Len(); i++ {
+		intent := intents.Index(i)
+		if intent.Key("S").Name() == "GTS_PDFA1" && !intent.Key("DestOutputProfile").IsNull() {
+			hasIntent = true
+		}
+	}
+	if !hasIntent {
+		issue("no PDF/A output intent with an ICC profile")
+	}
+
+	if !root.Key("Names").Key("JavaScript").IsNull() || root.Key("OpenAction").Key("S").Name() == "JavaScript" {
+		issue("contains JavaScript")
+	}
+	if part == 1 && !root.Key("Names").Key("EmbeddedFiles").IsNull() {
+		issue("PDF/A-1 doesn't allow embedded files")
+	}
+
+	unembedded := map[string]bool{}
+	for n := 1; n <= r.NumPage(); n++ {
+		fonts := r.Page(n).Resources().Key("Font")
+		for _, key := range fonts.Keys() {
+			font := fonts.Key(key)
+			if !pdfFontEmbedded(font) && !unembedded[font.Key("BaseFont").Name()] {
+				unembedded[font.Key("BaseFont").Name()] = true
+				issue("font %s is not embedded", font.Key("BaseFont").Name())
+			}
+		}
+	}
+
+	result.Compliant = len(result.Issues) == 0
+	return result, nil
+}
+
+// pdfFontEmbedded reports whether a font dictionary carries its font program
+func pdfFontEmbedded(font pdf.Value) bool {
+	switch font.Key("Subtype").Name() {
+	case "Type3":
+		// Glyphs are content streams in the font itself
+		return true
+	case "Type0":
+		font = font.Key("DescendantFonts").Index(0)
+	}
+	desc := font.Key("FontDescriptor")
+	for _, key := range []string{"FontFile", "FontFile2", "FontFile3"} {
+		if !desc.Key(key).IsNull() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var docsRotateCmd = &cobra.Command{
+	Use:   "rotate <id>...",
+	Short: "Rotate the pages of documents",
+	Long: `Rotate all pages of the given documents clockwise on the server, without
+downloading and re-uploading them.
+
+The server rotates the original and regenerates the archived version in the
+background. Documents on legal hold (see 'config set hold') are skipped
+unless --override-hold is given.
+
+Example:
+  paperless documents rotate 42 --degrees 90
+  paperless documents rotate 42 43 --degrees 180`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDocsRotate,
+}
+
+var docsDeletePagesCmd = &cobra.Command{
+	Use:   "delete-pages <id>",
+	Short: "Delete pages from a document",
+	Long: `Delete pages from a document on the server, such as blank pages or
+separator sheets, without downloading and re-uploading it.
+
+Pages are counted from 1 and given as a comma-separated list of pages and
+ranges. At least one page must remain. Asks for confirmation unless --force
+is given; a document on legal hold (see 'config set hold') is refused unless
+--override-hold is given.
+
+Example:
+  paperless documents delete-pages 42 --pages 2,5
+  paperless documents delete-pages 42 --pages 3-4 --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDocsDeletePages,
+}
+
+var (
+	rotateDegrees    int
+	deletePages      string
+	deletePagesForce bool
+)
+
+func init() {
+	documentsCmd.AddCommand(docsRotateCmd)
+	documentsCmd.AddCommand(docsDeletePagesCmd)
+
+	docsRotateCmd.Flags().IntVar(&rotateDegrees, "degrees", 90, "clockwise rotation: 90, 180 or 270")
+	addOverrideHoldFlag(docsRotateCmd)
+
+	docsDeletePagesCmd.Flags().StringVar(&deletePages, "pages", "", `pages to delete, like "2,5" or "3-4"`)
+	docsDeletePagesCmd.Flags().BoolVarP(&deletePagesForce, "force", "f", false, "skip confirmation")
+	addOverrideHoldFlag(docsDeletePagesCmd)
+	docsDeletePagesCmd.MarkFlagRequired("pages")
+}
+
+func runDocsRotate(cmd *cobra.Command, args []string) error {
+	switch rotateDegrees {
+	case 90, 180, 270:
+	case -90:
+		rotateDegrees = 270
+	default:
+		return fmt.Errorf("invalid rotation: %d (use 90, 180 or 270)", rotateDegrees)
+	}
+
+	ids, err := parseDocumentIDs(args)
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	if err := client.RequireFeature(api.FeaturePDFEditing); err != nil {
+		return err
+	}
+
+	hold, err := loadLegalHold(client)
+	if err != nil {
+		return err
+	}
+	if ids, err = hold.exclude(client, ids); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := client.BulkEditDocuments(ids, api.BulkRotate(rotateDegrees)); err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(map[string]interface{}{
+			"rotated": ids,
+			"degrees": rotateDegrees,
+			"queued":  true,
+		})
+	}
+	if !isQuiet() {
+		fmt.Printf("Rotation of document(s) %s by %d degrees queued\n", joinInts(ids, ", "), rotateDegrees)
+	}
+	return nil
+}
+
+func runDocsDeletePages(cmd *cobra.Command, args []string) error {
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid document ID: %s", args[0])
+	}
+
+	ranges, err := parsePageRanges(deletePages)
+	if err != nil {
+		return err
+	}
+	var pages []int
+	for _, r := range ranges {
+		for p := r[0]; p <= r[1]; p++ {
+			pages = append(pages, p)
+		}
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	if err := client.RequireFeature(api.FeatureDeletePages); err != nil {
+		return err
+	}
+
+	doc, err := client.GetDocument(id)
+	if err != nil {
+		return err
+	}
+	if doc.PageCount != nil {
+		if last := pages[len(pages)-1]; last > *doc.PageCount {
+			return fmt.Errorf("page %d is out of range; document %d has %d page(s)", last, id, *doc.PageCount)
+		}
+		if len(pages) >= *doc.PageCount {
+			return fmt.Errorf("can't delete all %d page(s) of document %d", *doc.PageCount, id)
+		}
+	}
+
+	hold, err := loadLegalHold(client)
+	if err != nil {
+		return err
+	}
+	if !overrideHold && hold.holds(*doc) {
+		return fmt.Errorf("document %d is on legal hold (%s); pass --override-hold to change it anyway", id, hold.name)
+	}
+
+	msg := fmt.Sprintf("Delete page(s) %s from %q? This can't be undone.", joinInts(pages, ", "), doc.Title)
+	if !deletePagesForce && !confirmAction(msg) {
+		fmt.Println("Cancelled")
+		return nil
+	}
+
+	if err := client.BulkEditDocuments([]int{id}, api.BulkDeletePages(pages)); err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(map[string]interface{}{
+			"document": id,
+			"pages":    pages,
+			"queued":   true,
+		})
+	}
+	if !isQuiet() {
+		fmt.Printf("Deletion of page(s) %s from document %d queued\n", joinInts(pages, ", "), id)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+)
+
+// progressBarWidth is the number of cells in the rendered bar
+const progressBarWidth = 30
+
+// progressBar renders transfer progress on a single terminal line
+type progressBar struct {
+	out    io.Writer
+	label  string
+	format func(int64) string
+	// rate adds the speed to the line, for bytes
+	rate bool
+
+	mu      sync.Mutex
+	started time.Time
+	last    time.Time
+	// line is what's drawn, to redraw it after printAbove
+	line string
+}
+
+// activeBar is the bar being drawn. Only one is shown at a time: transfers
+// within a batch don't draw their own while the batch's bar is shown.
+var activeBar struct {
+	mu  sync.Mutex
+	bar *progressBar
+}
+
+// newProgressBar returns a progress callback drawing transferred bytes with
+// speed and ETA to stderr, or nil when stderr is not a terminal, output
+// should stay machine readable or --no-progress is given
+func newProgressBar(label string) (api.ProgressFunc, func()) {
+	return startProgressBar(label, formatBytes, true)
+}
+
+// newCountProgressBar is like newProgressBar for counting items instead of bytes
+func newCountProgressBar(label string) (api.ProgressFunc, func()) {
+	return startProgressBar(label, func(n int64) string {
+		return strconv.FormatInt(n, 10)
+	}, false)
+}
+
+func startProgressBar(label string, format func(int64) string, rate bool) (api.ProgressFunc, func()) {
+	if noProgress || isQuiet() || isJSON() || !isTerminal(os.Stderr) {
+		return nil, func() {}
+	}
+
+	activeBar.mu.Lock()
+	defer activeBar.mu.Unlock()
+	if activeBar.bar != nil {
+		return nil, func() {}
+	}
+	bar := &progressBar{out: os.Stderr, label: label, format: format, rate: rate, started: time.Now()}
+	activeBar.bar = bar
+	return bar.update, bar.finish
+}
+
+func (b *progressBar) update(done, total int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Redrawing on every read would flood slow terminals
+	if time.Since(b.last) < 100*time.Millisecond && done != total {
+		return
+	}
+	b.last = time.Now()
+
+	if total <= 0 {
+		b.draw(fmt.Sprintf("%s %s%s", b.label, b.format(done), b.speed(done, 0)))
+		return
+	}
+
+	done = min(done, total)
+	filled := int(float64(progressBarWidth) * float64(done) / float64(total))
+	b.draw(fmt.Sprintf("%s [%s%s] %3d%% %s/%s%s", b.label,
+		strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled),
+		done*100/total, b.format(done), b.format(total), b.speed(done, total)))
+}
+
+// speed describes the rate and, with a total, the time left once it can be
+// estimated
+func (b *progressBar) speed(done, total int64) string {
+	elapsed := time.Since(b.started)
+	if elapsed < time.Second || done == 0 {
+		return ""
+	}
+	perSecond := float64(done) / elapsed.Seconds()
+
+	var s string
+	if b.rate {
+		s = " " + b.format(int64(perSecond)) + "/s"
+	}
+	if total > 0 && done < total {
+		left := time.Duration(float64(total-done) / perSecond * float64(time.Second))
+		s += " ETA " + formatETA(left)
+	}
+	return s
+}
+
+// draw replaces the bar's line, clearing what's left of a longer one
+func (b *progressBar) draw(line string) {
+	b.line = line
+	fmt.Fprintf(b.out, "\r%s\x1b[K", line)
+}
+
+func (b *progressBar) finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Finishing twice is fine, e.g. early and deferred
+	if !b.last.IsZero() {
+		fmt.Fprintln(b.out)
+	}
+	b.last, b.line = time.Time{}, ""
+
+	activeBar.mu.Lock()
+	if activeBar.bar == b {
+		activeBar.bar = nil
+	}
+	activeBar.mu.Unlock()
+}
+
+// printAbove prints a status line while a bar may be drawn: the bar is
+// cleared, the line printed and the bar drawn again below it
+func printAbove(w io.Writer, format string, a ...interface{}) {
+	activeBar.mu.Lock()
+	bar := activeBar.bar
+	activeBar.mu.Unlock()
+	if bar == nil {
+		fmt.Fprintf(w, format, a...)
+		return
+	}
+
+	bar.mu.Lock()
+	defer bar.mu.Unlock()
+	if bar.line != "" {
+		fmt.Fprint(bar.out, "\r\x1b[K")
+	}
+	fmt.Fprintf(w, format, a...)
+	if bar.line != "" {
+		fmt.Fprint(bar.out, bar.line)
+	}
+}
+
+// batchProgress adds up the progress of transfers running in parallel into
+// one bar
+type batchProgress struct {
+	update api.ProgressFunc
+	total  int64
+
+	mu   sync.Mutex
+	done []int64
+}
+
+// newBatchProgress returns a bar for n transfers of total bytes. It is nil,
+// like its callbacks, if no bar is shown.
+func newBatchProgress(label string, n int, total int64) (*batchProgress, func()) {
+	update, finish := newProgressBar(label)
+	if update == nil {
+		return nil, finish
+	}
+	return &batchProgress{update: update, total: total, done: make([]int64, n)}, finish
+}
+
+// item returns the progress callback of transfer i
+func (p *batchProgress) item(i int) api.ProgressFunc {
+	if p == nil {
+		return nil
+	}
+	return func(done, total int64) {
+		p.mu.Lock()
+		p.done[i] = done
+		var sum int64
+		for _, n := range p.done {
+			sum += n
+		}
+		p.mu.Unlock()
+		p.update(sum, p.total)
+	}
+}
+
+// formatETA renders a duration as m:ss or h:mm:ss
+func formatETA(d time.Duration) string {
+	s := int(d.Round(time.Second).Seconds())
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// isTerminal reports whether f is connected to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// formatBytes renders a byte count with a binary unit suffix
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
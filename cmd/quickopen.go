@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/julianfbeck/paperless-cli/internal/cache"
+	"github.com/julianfbeck/paperless-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var quickOpenCmd = &cobra.Command{
+	Use:   "o <words>...",
+	Short: "Quickly open a document by fuzzy title",
+	Long: `Find a document by words of its title and open it in the web UI. Titles are
+matched against a local index, so no request is made to find the document:
+words may be abbreviated or have letters left out, and the newest of equally
+good matches wins.
+
+The index is built on first use and kept in ~/.cache/paperless-cli; run with
+--refresh to pick up documents added since. With --download the match is
+saved to the current directory instead, and with --json the best matches are
+printed without opening anything.
+
+Example:
+  paperless o acme invoice
+  paperless o insur 2024 --pick
+  paperless o --refresh lease
+  paperless o tax notice --download`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runQuickOpen,
+}
+
+var (
+	quickOpenPick     bool
+	quickOpenRefresh  bool
+	quickOpenDownload bool
+	quickOpenOriginal bool
+)
+
+func init() {
+	rootCmd.AddCommand(quickOpenCmd)
+
+	quickOpenCmd.Flags().BoolVar(&quickOpenPick, "pick", false, "choose from the best matches instead of taking the first")
+	quickOpenCmd.Flags().BoolVar(&quickOpenRefresh, "refresh", false, "rebuild the title index from the server first")
+	quickOpenCmd.Flags().BoolVar(&quickOpenDownload, "download", false, "download the document instead of opening it")
+	quickOpenCmd.Flags().BoolVar(&quickOpenOriginal, "original", false, "with --download, download the original file")
+}
+
+// titleIndex is the local list of document titles quick open matches against
+type titleIndex struct {
+	Updated   time.Time    `json:"updated"`
+	Documents []titleEntry `json:"documents"`
+}
+
+type titleEntry struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+// titleIndexKey returns the cache key of the index of the active profile
+// and server, as --url or PAPERLESS_URL may point it elsewhere
+func titleIndexKey(client *api.Client) string {
+	sum := sha256.Sum256([]byte(config.ActiveProfile() + "\x00" + client.BaseURL()))
+	return "titles-" + hex.EncodeToString(sum[:8])
+}
+
+// loadTitleIndex reads the title index, returning nil if there is none
+func loadTitleIndex(client *api.Client, store *cache.Dir) *titleIndex {
+	data, ok := store.Get(titleIndexKey(client))
+	if !ok {
+		return nil
+	}
+	var index titleIndex
+	if json.Unmarshal(data, &index) != nil {
+		return nil
+	}
+	return &index
+}
+
+// buildTitleIndex fetches the titles of all documents and stores them
+func buildTitleIndex(client *api.Client, store *cache.Dir) (*titleIndex, error) {
+	if !isQuiet() {
+		fmt.Fprintln(os.Stderr, "Building title index...")
+	}
+
+	index := &titleIndex{Updated: time.Now(), Documents: []titleEntry{}}
+	params := api.DocumentListParams{
+		Limit:           100,
+		Ordering:        "id",
+		Fields:          []string{"id", "title"},
+		TruncateContent: true,
+	}
+	for page := 1; ; page++ {
+		params.Page = page
+		result, err := client.ListDocuments(params)
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range result.Results {
+			index.Documents = append(index.Documents, titleEntry{ID: doc.ID, Title: doc.Title})
+		}
+		if result.Next == "" {
+			break
+		}
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.Put(titleIndexKey(client), data); err != nil {
+		return nil, fmt.Errorf("failed to save title index: %w", err)
+	}
+	return index, nil
+}
+
+// titleMatch is an index entry with how well it matched
+type titleMatch struct {
+	titleEntry
+	Score int `json:"score"`
+}
+
+// matchTitles returns the entries matching all words, best first. Each
+// word scores by how it matches a word of the title: fully, as its prefix,
+// anywhere in it, or with letters in between.
+func matchTitles(entries []titleEntry, words []string) []titleMatch {
+	if len(words) == 1 {
+		if id, err := strconv.Atoi(words[0]); err == nil {
+			for _, e := range entries {
+				if e.ID == id {
+					return []titleMatch{{titleEntry: e, Score: 100}}
+				}
+			}
+		}
+	}
+
+	var matches []titleMatch
+	for _, e := range entries {
+		titleWords := strings.FieldsFunc(strings.ToLower(e.Title), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+
+		total := 0
+		for _, w := range words {
+			best := 0
+			for _, tw := range titleWords {
+				score := 0
+				switch {
+				case tw == w:
+					score = 4
+				case strings.HasPrefix(tw, w):
+					score = 3
+				case strings.Contains(tw, w):
+					score = 2
+				case isSubsequence(w, tw):
+					score = 1
+				}
+				best = max(best, score)
+			}
+			if best == 0 {
+				total = 0
+				break
+			}
+			total += best
+		}
+		if total > 0 {
+			matches = append(matches, titleMatch{titleEntry: e, Score: total})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID > matches[j].ID
+	})
+	return matches
+}
+
+// pickMatch lets the user choose one of the best matches on stderr
+func pickMatch(matches []titleMatch) (*titleMatch, error) {
+	if len(matches) > 9 {
+		matches = matches[:9]
+	}
+	for i, m := range matches {
+		fmt.Fprintf(os.Stderr, "  %d) %s (%d)\n", i+1, m.Title, m.ID)
+	}
+	fmt.Fprintf(os.Stderr, "Which document? (1-%d): ", len(matches))
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	n, convErr := strconv.Atoi(strings.TrimSpace(line))
+	if convErr != nil || n < 1 || n > len(matches) {
+		if err != nil {
+			return nil, fmt.Errorf("no document chosen: %w", err)
+		}
+		return nil, fmt.Errorf("no document chosen")
+	}
+	return &matches[n-1], nil
+}
+
+// openBrowser opens a URL with the desktop's default handler
+func openBrowser(url string) error {
+	var c *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		c = exec.Command("open", url)
+	case "windows":
+		c = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		c = exec.Command("xdg-open", url)
+	}
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", url, err)
+	}
+	return nil
+}
+
+func runQuickOpen(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return err
+	}
+	store := cache.New(dir)
+
+	var index *titleIndex
+	if !quickOpenRefresh {
+		index = loadTitleIndex(client, store)
+	}
+	if index == nil {
+		if index, err = buildTitleIndex(client, store); err != nil {
+			return err
+		}
+	}
+
+	words := strings.Fields(strings.ToLower(strings.Join(args, " ")))
+	matches := matchTitles(index.Documents, words)
+
+	if isJSON() {
+		if len(matches) > 10 {
+			matches = matches[:10]
+		}
+		if matches == nil {
+			matches = []titleMatch{}
+		}
+		return printJSON(matches)
+	}
+
+	if len(matches) == 0 {
+		return fmt.Errorf("no document title matches %q (index from %s, --refresh to update it)",
+			strings.Join(args, " "), index.Updated.Format("2006-01-02 15:04"))
+	}
+
+	match := &matches[0]
+	if quickOpenPick && len(matches) > 1 {
+		if match, err = pickMatch(matches); err != nil {
+			return err
+		}
+	}
+
+	if quickOpenDownload {
+		// Cancel on Ctrl-C so the temporary file is still cleaned up
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		outputPath, info, err := downloadToFile(ctx, client, match.ID, quickOpenOriginal, func(filename string) string {
+			if filename != "" {
+				return filename
+			}
+			return fmt.Sprintf("document_%d.pdf", match.ID)
+		})
+		if err != nil {
+			return err
+		}
+		if !isQuiet() {
+			fmt.Printf("Downloaded %s to %s (%d bytes)\n", match.Title, outputPath, info.Written)
+		}
+		return nil
+	}
+
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Opening %d: %s\n", match.ID, match.Title)
+	}
+	return openBrowser(client.DocumentURL(match.ID))
+}
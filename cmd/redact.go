@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+	"github.com/spf13/cobra"
+)
+
+var pdfRedactCmd = &cobra.Command{
+	Use:   "redact <file>",
+	Short: "Make a redacted copy of a PDF",
+	Long: `Write a copy of a PDF with black boxes over text matching --pattern and over
+the given --area rectangles.
+
+Pages are rendered to images and the boxes painted into them, so the copy
+contains no text, metadata, attachments or form data that could reveal what
+was covered. It isn't searchable and is bigger than the original.
+
+Patterns are regular expressions matched against each line of text and need
+pdftotext (poppler-utils). An area is PAGE:X,Y,W,H in points (1/72 inch)
+from the top left corner of the page, with PAGE "*" for every page. Scanned
+pages without a text layer can only be redacted by area.
+
+Example:
+  paperless pdf redact statement.pdf --pattern '\b\d{2}/\d{6}\b'
+  paperless pdf redact letter.pdf --pattern 'IBAN:? [A-Z0-9 ]+' -o shareable.pdf
+  paperless pdf redact scan.pdf --area 1:40,60,200,30`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPDFRedact,
+}
+
+var (
+	redactPatterns []string
+	redactAreas    []string
+	redactOutput   string
+	redactDPI      int
+)
+
+func init() {
+	pdfCmd.AddCommand(pdfRedactCmd)
+
+	pdfRedactCmd.Flags().StringArrayVar(&redactPatterns, "pattern", nil, "regular expression of text to cover (repeatable)")
+	pdfRedactCmd.Flags().StringArrayVar(&redactAreas, "area", nil, "rectangle to cover as PAGE:X,Y,W,H in points from the top left (repeatable)")
+	pdfRedactCmd.Flags().StringVarP(&redactOutput, "output", "o", "", "output path (default <file>_redacted.pdf)")
+	pdfRedactCmd.Flags().IntVar(&redactDPI, "dpi", 150, "resolution of the rendered pages")
+}
+
+// redaction is a rectangle to cover, in points from the top left of a page
+type redaction struct {
+	Page int     `json:"page"`
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+	W    float64 `json:"w"`
+	H    float64 `json:"h"`
+	// Text is the matched text, empty for areas
+	Text string `json:"text,omitempty"`
+}
+
+// parseRedactArea parses PAGE:X,Y,W,H; page 0 stands for every page
+func parseRedactArea(s string) (redaction, error) {
+	page, rect, ok := strings.Cut(s, ":")
+	parts := strings.Split(rect, ",")
+	if !ok || len(parts) != 4 {
+		return redaction{}, fmt.Errorf("invalid area %q: use PAGE:X,Y,W,H", s)
+	}
+
+	var r redaction
+	if page != "*" {
+		n, err := strconv.Atoi(page)
+		if err != nil || n < 1 {
+			return redaction{}, fmt.Errorf("invalid area %q: page must be a number or *", s)
+		}
+		r.Page = n
+	}
+	for i, field := range []*float64{&r.X, &r.Y, &r.W, &r.H} {
+		v, err := strconv.ParseFloat(strings.TrimSpace(parts[i]), 64)
+		if err != nil || v < 0 {
+			return redaction{}, fmt.Errorf("invalid area %q: %q is not a size in points", s, parts[i])
+		}
+		*field = v
+	}
+	return r, nil
+}
+
+// bboxWord is a word of pdftotext -bbox-layout output
+type bboxWord struct {
+	XMin float64 `xml:"xMin,attr"`
+	YMin float64 `xml:"yMin,attr"`
+	XMax float64 `xml:"xMax,attr"`
+	YMax float64 `xml:"yMax,attr"`
+	Text string  `xml:",chardata"`
+}
+
+// bboxDoc is the pdftotext -bbox-layout output
+type bboxDoc struct {
+	Pages []struct {
+		Lines []struct {
+			Words []bboxWord `xml:"word"`
+		} `xml:"flow>block>line"`
+	} `xml:"body>doc>page"`
+}
+
+// findTextRedactions locates text matching the patterns with pdftotext
+func findTextRedactions(path string, patterns []*regexp.Regexp) ([]redaction, error) {
+	if _, err := exec.LookPath("pdftotext"); err != nil {
+		return nil, fmt.Errorf("--pattern needs pdftotext (poppler-utils) to locate text")
+	}
+	out, err := exec.Command("pdftotext", "-bbox-layout", path, "-").Output()
+	if err != nil {
+		return nil, fmt.Errorf("pdftotext failed: %w", err)
+	}
+
+	var doc bboxDoc
+	if err := xml.Unmarshal(out, &doc); err != nil {
+		return nil, fmt.Errorf("reading pdftotext output: %w", err)
+	}
+
+	var found []redaction
+	for i, page := range doc.Pages {
+		for _, line := range page.Lines {
+			found = append(found, matchLine(i+1, line.Words, patterns)...)
+		}
+	}
+	return found, nil
+}
+
+// matchLine matches the patterns against a line of words joined by spaces
+// and returns a rectangle for each matched part of a word. Partially matched
+// words are cut proportionally to their characters.
+func matchLine(page int, words []bboxWord, patterns []*regexp.Regexp) []redaction {
+	var text strings.Builder
+	starts := make([]int, len(words))
+	for i, w := range words {
+		if i > 0 {
+			text.WriteByte(' ')
+		}
+		starts[i] = text.Len()
+		text.WriteString(w.Text)
+	}
+	line := text.String()
+
+	var found []redaction
+	for _, re := range patterns {
+		for _, m := range re.FindAllStringIndex(line, -1) {
+			if m[0] == m[1] {
+				continue
+			}
+			for i, w := range words {
+				start, end := max(m[0], starts[i]), min(m[1], starts[i]+len(w.Text))
+				if start >= end {
+					continue
+				}
+				runes := float64(len([]rune(w.Text)))
+				from := float64(len([]rune(w.Text[:start-starts[i]]))) / runes
+				to := float64(len([]rune(w.Text[:end-starts[i]]))) / runes
+				width := w.XMax - w.XMin
+				found = append(found, redaction{
+					Page: page,
+					X:    w.XMin + from*width,
+					Y:    w.YMin,
+					W:    (to - from) * width,
+					H:    w.YMax - w.YMin,
+					Text: line[m[0]:m[1]],
+				})
+			}
+		}
+	}
+	return found
+}
+
+// redactedPage is a rendered page with the redactions painted in
+type redactedPage struct {
+	jpeg          []byte
+	width, height int
+}
+
+// redactPage renders a page and paints black boxes over the redactions
+func redactPage(renderer, path string, page, dpi int, boxes []redaction) (*redactedPage, error) {
+	dir, err := os.MkdirTemp("", "paperless-redact-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	pngPath := filepath.Join(dir, "page.png")
+	if err := renderPage(renderer, path, page, dpi, pngPath); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(pngPath)
+	if err != nil {
+		return nil, err
+	}
+	src, err := png.Decode(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("page %d: %w", page, err)
+	}
+
+	img := image.NewRGBA(src.Bounds())
+	draw.Draw(img, img.Bounds(), src, src.Bounds().Min, draw.Src)
+
+	// A point of padding covers glyph parts outside the reported box
+	scale := float64(dpi) / 72
+	for _, b := range boxes {
+		rect := image.Rect(
+			int((b.X-1)*scale), int((b.Y-1)*scale),
+			int((b.X+b.W+1)*scale+0.5), int((b.Y+b.H+1)*scale+0.5),
+		).Add(img.Bounds().Min)
+		draw.Draw(img, rect.Intersect(img.Bounds()), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+		return nil, err
+	}
+	return &redactedPage{jpeg: buf.Bytes(), width: img.Bounds().Dx(), height: img.Bounds().Dy()}, nil
+}
+
+// writeImagePDF writes a PDF with one JPEG image filling each page
+func writeImagePDF(path string, pages []*redactedPage, dpi int) error {
+	pw := newPDFWriter()
+
+	// Objects 1 and 2 are the catalog and page tree, then three per page
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 3+3*i)
+	}
+	pw.object("<< /Type /Catalog /Pages 2 0 R >>")
+	pw.object("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	for i, p := range pages {
+		n := 3 + 3*i
+		w, h := float64(p.width)*72/float64(dpi), float64(p.height)*72/float64(dpi)
+		content := fmt.Sprintf("q %.2f 0 0 %.2f 0 0 cm /Im0 Do Q", w, h)
+		pw.object("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /XObject << /Im0 %d 0 R >> >> /Contents %d 0 R >>", w, h, n+2, n+1)
+		pw.object("<< /Length %d >>\nstream\n%s\nendstream", len(content), content)
+		pw.object("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n%s\nendstream",
+			p.width, p.height, len(p.jpeg), p.jpeg)
+	}
+
+	return os.WriteFile(path, pw.bytes(), 0644)
+}
+
+func runPDFRedact(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	if len(redactPatterns) == 0 && len(redactAreas) == 0 {
+		return fmt.Errorf("nothing to redact: give --pattern or --area")
+	}
+	if redactDPI < 1 {
+		return fmt.Errorf("invalid resolution: %d", redactDPI)
+	}
+
+	var patterns []*regexp.Regexp
+	for _, p := range redactPatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		patterns = append(patterns, re)
+	}
+	var areas []redaction
+	for _, a := range redactAreas {
+		r, err := parseRedactArea(a)
+		if err != nil {
+			return err
+		}
+		areas = append(areas, r)
+	}
+
+	renderer, err := pageRenderer()
+	if err != nil {
+		return err
+	}
+
+	f, r, err := pdf.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open PDF: %w", err)
+	}
+	numPages := r.NumPage()
+	f.Close()
+
+	var redactions []redaction
+	if len(patterns) > 0 {
+		if redactions, err = findTextRedactions(path, patterns); err != nil {
+			return err
+		}
+		if len(redactions) == 0 && len(areas) == 0 {
+			return fmt.Errorf("no text matches the patterns, nothing was written (scanned pages without a text layer need --area)")
+		}
+	}
+	for _, a := range areas {
+		if a.Page > numPages {
+			return fmt.Errorf("area on page %d, but the PDF has %d page(s)", a.Page, numPages)
+		}
+		if a.Page != 0 {
+			redactions = append(redactions, a)
+			continue
+		}
+		for page := 1; page <= numPages; page++ {
+			a.Page = page
+			redactions = append(redactions, a)
+		}
+	}
+
+	pages := make([]*redactedPage, numPages)
+	for page := 1; page <= numPages; page++ {
+		var boxes []redaction
+		for _, red := range redactions {
+			if red.Page == page {
+				boxes = append(boxes, red)
+			}
+		}
+		if pages[page-1], err = redactPage(renderer, path, page, redactDPI, boxes); err != nil {
+			return err
+		}
+	}
+
+	outputPath := redactOutput
+	if outputPath == "" {
+		outputPath = strings.TrimSuffix(path, filepath.Ext(path)) + "_redacted.pdf"
+	}
+	if err := writeImagePDF(outputPath, pages, redactDPI); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputPath, err)
+	}
+
+	if isJSON() {
+		if redactions == nil {
+			redactions = []redaction{}
+		}
+		return printJSON(map[string]interface{}{"output": outputPath, "redactions": redactions})
+	}
+
+	if !isQuiet() {
+		touched := make(map[int]bool)
+		for _, red := range redactions {
+			touched[red.Page] = true
+		}
+		fmt.Printf("Covered %d box(es) on %d of %d page(s), saved to %s\n", len(redactions), len(touched), numPages, outputPath)
+	}
+
+	return nil
+}
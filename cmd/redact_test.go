@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"math"
+	"regexp"
+	"testing"
+)
+
+func TestParseRedactArea(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    redaction
+		wantErr bool
+	}{
+		{in: "1:40,60,200,30", want: redaction{Page: 1, X: 40, Y: 60, W: 200, H: 30}},
+		{in: "3:0.5, 1 ,2.25,4", want: redaction{Page: 3, X: 0.5, Y: 1, W: 2.25, H: 4}},
+		{in: "*:0,0,595,842", want: redaction{Page: 0, X: 0, Y: 0, W: 595, H: 842}},
+
+		{in: "0:1,2,3,4", wantErr: true},
+		{in: "-1:1,2,3,4", wantErr: true},
+		{in: "x:1,2,3,4", wantErr: true},
+		{in: "1,2,3,4", wantErr: true},
+		{in: "1:1,2,3", wantErr: true},
+		{in: "1:1,2,3,4,5", wantErr: true},
+		{in: "1:1,2,3,-4", wantErr: true},
+		{in: "1:1,2,three,4", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseRedactArea(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseRedactArea(%q) = %+v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseRedactArea(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseRedactArea(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+// word returns a word 10 points wide per character, on a line from y 100
+// to 112
+func word(text string, x float64) bboxWord {
+	return bboxWord{XMin: x, YMin: 100, XMax: x + 10*float64(len([]rune(text))), YMax: 112, Text: text}
+}
+
+func TestMatchLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []bboxWord
+		patterns []string
+		want     []redaction
+	}{
+		{
+			name:     "whole word",
+			words:    []bboxWord{word("hello", 0), word("world", 60)},
+			patterns: []string{"world"},
+			want:     []redaction{{X: 60, W: 50, Text: "world"}},
+		},
+		{
+			name:     "part of a word",
+			words:    []bboxWord{word("hello", 0)},
+			patterns: []string{"ell"},
+			want:     []redaction{{X: 10, W: 30, Text: "ell"}},
+		},
+		{
+			name:     "multi-byte word",
+			words:    []bboxWord{word("Größe", 0)},
+			patterns: []string{"öß"},
+			want:     []redaction{{X: 20, W: 20, Text: "öß"}},
+		},
+		{
+			name:     "multi-byte words before the match",
+			words:    []bboxWord{word("Straße", 0), word("Überweisung", 70), word("€100", 190)},
+			patterns: []string{`\d+`},
+			want:     []redaction{{X: 200, W: 30, Text: "100"}},
+		},
+		{
+			name:     "match spanning several words",
+			words:    []bboxWord{word("IBAN", 0), word("DE89", 50), word("3704", 100), word("0044", 150)},
+			patterns: []string{`DE\d\d \d{4} \d\d`},
+			want: []redaction{
+				{X: 50, W: 40, Text: "DE89 3704 00"},
+				{X: 100, W: 40, Text: "DE89 3704 00"},
+				{X: 150, W: 20, Text: "DE89 3704 00"},
+			},
+		},
+		{
+			name:     "several matches and patterns",
+			words:    []bboxWord{word("a1", 0), word("b2", 30), word("a3", 60)},
+			patterns: []string{`a\d`, `b\d`},
+			want: []redaction{
+				{X: 0, W: 20, Text: "a1"},
+				{X: 60, W: 20, Text: "a3"},
+				{X: 30, W: 20, Text: "b2"},
+			},
+		},
+		{
+			name:     "no match",
+			words:    []bboxWord{word("hello", 0)},
+			patterns: []string{"world"},
+		},
+		{
+			name:     "empty matches are ignored",
+			words:    []bboxWord{word("hello", 0)},
+			patterns: []string{"x*"},
+		},
+	}
+
+	for _, tt := range tests {
+		patterns := make([]*regexp.Regexp, len(tt.patterns))
+		for i, p := range tt.patterns {
+			patterns[i] = regexp.MustCompile(p)
+		}
+
+		got := matchLine(2, tt.words, patterns)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %d rectangles %+v, want %d", tt.name, len(got), got, len(tt.want))
+			continue
+		}
+		for i, want := range tt.want {
+			want.Page, want.Y, want.H = 2, 100, 12
+			if !sameRedaction(got[i], want) {
+				t.Errorf("%s: rectangle %d = %+v, want %+v", tt.name, i, got[i], want)
+			}
+		}
+	}
+}
+
+// sameRedaction compares redactions, allowing for rounding of the cuts
+func sameRedaction(a, b redaction) bool {
+	near := func(x, y float64) bool { return math.Abs(x-y) < 1e-9 }
+	return a.Page == b.Page && a.Text == b.Text &&
+		near(a.X, b.X) && near(a.Y, b.Y) && near(a.W, b.W) && near(a.H, b.H)
+}
@@ -0,0 +1,372 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var retentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Enforce retention periods",
+	Long:  `Find documents that are past their retention period and move them to the trash.`,
+}
+
+var retentionApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Trash documents past their retention period",
+	Long: `List documents past the retention periods in a rules file and move them to
+the trash after confirmation. Trashed documents can be restored in the web
+UI until the server empties the trash, so this needs Paperless-ngx >= 2.10.
+
+A rule matches documents with all of its tags and its document type and
+correspondent, if given. Periods count from the created date, in years (y),
+months (m), weeks (w) or days (d); with year_end they start at the end of
+the created year, as many legal retention periods do. A document matched by
+several rules is kept for the longest of them. Documents with one of the
+hold_tags are never trashed, nor are documents on legal hold (see 'config
+set hold') unless --override-hold is given.
+
+  hold_tags: [tax-audit]
+  rules:
+    - name: Payslips
+      tags: [payslip]
+      keep: 6y
+    - document_type: Invoice
+      keep: 10y
+      year_end: true
+
+Example:
+  paperless retention apply --rules retention.yaml --dry-run
+  paperless retention apply --rules retention.yaml
+  paperless retention apply --rules retention.yaml --json --dry-run`,
+	RunE: runRetentionApply,
+}
+
+var (
+	retentionRules  string
+	retentionDryRun bool
+	retentionForce  bool
+)
+
+func init() {
+	rootCmd.AddCommand(retentionCmd)
+	retentionCmd.AddCommand(retentionApplyCmd)
+
+	retentionApplyCmd.Flags().StringVar(&retentionRules, "rules", "", "YAML file with the retention rules")
+	retentionApplyCmd.Flags().BoolVar(&retentionDryRun, "dry-run", false, "only list documents past retention")
+	retentionApplyCmd.Flags().BoolVarP(&retentionForce, "force", "f", false, "skip confirmation")
+	addOverrideHoldFlag(retentionApplyCmd)
+	retentionApplyCmd.MarkFlagRequired("rules")
+}
+
+// retentionPolicy is the content of a rules file
+type retentionPolicy struct {
+	HoldTags []string        `yaml:"hold_tags"`
+	Rules    []retentionRule `yaml:"rules"`
+}
+
+// retentionRule keeps matching documents for a period
+type retentionRule struct {
+	Name          string   `yaml:"name"`
+	Tags          []string `yaml:"tags"`
+	DocumentType  string   `yaml:"document_type"`
+	Correspondent string   `yaml:"correspondent"`
+	Keep          string   `yaml:"keep"`
+	YearEnd       bool     `yaml:"year_end"`
+
+	years, months, days int
+	tagIDs              []int
+	typeID, corrID      *int
+}
+
+// String describes the rule for output
+func (r retentionRule) String() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	var parts []string
+	if len(r.Tags) > 0 {
+		parts = append(parts, "tags "+strings.Join(r.Tags, "+"))
+	}
+	if r.DocumentType != "" {
+		parts = append(parts, "type "+r.DocumentType)
+	}
+	if r.Correspondent != "" {
+		parts = append(parts, "correspondent "+r.Correspondent)
+	}
+	return strings.Join(parts, ", ") + " (" + r.Keep + ")"
+}
+
+// parseRetentionPeriod parses periods like 10y, 6m, 2w or 30d
+func parseRetentionPeriod(s string) (years, months, days int, err error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 {
+		return 0, 0, 0, fmt.Errorf("invalid retention period: %q (use e.g. 10y, 6m, 2w or 30d)", s)
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return 0, 0, 0, fmt.Errorf("invalid retention period: %q (use e.g. 10y, 6m, 2w or 30d)", s)
+	}
+	switch s[len(s)-1] {
+	case 'y':
+		return n, 0, 0, nil
+	case 'm':
+		return 0, n, 0, nil
+	case 'w':
+		return 0, 0, 7 * n, nil
+	case 'd':
+		return 0, 0, n, nil
+	}
+	return 0, 0, 0, fmt.Errorf("invalid retention period: %q (use e.g. 10y, 6m, 2w or 30d)", s)
+}
+
+// loadRetentionPolicy reads a rules file and resolves its names to IDs
+func loadRetentionPolicy(client *api.Client, path string) (*retentionPolicy, []int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var policy retentionPolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(policy.Rules) == 0 {
+		return nil, nil, fmt.Errorf("%s has no rules", path)
+	}
+
+	var holdIDs []int
+	for _, name := range policy.HoldTags {
+		tag, err := client.FindTagByName(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		holdIDs = append(holdIDs, tag.ID)
+	}
+
+	for i := range policy.Rules {
+		r := &policy.Rules[i]
+		// A rule without criteria would match the whole archive
+		if len(r.Tags) == 0 && r.DocumentType == "" && r.Correspondent == "" {
+			return nil, nil, fmt.Errorf("rule %d needs tags, document_type or correspondent", i+1)
+		}
+		if r.years, r.months, r.days, err = parseRetentionPeriod(r.Keep); err != nil {
+			return nil, nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		for _, name := range r.Tags {
+			tag, err := client.FindTagByName(name)
+			if err != nil {
+				return nil, nil, fmt.Errorf("rule %d: %w", i+1, err)
+			}
+			r.tagIDs = append(r.tagIDs, tag.ID)
+		}
+		if r.DocumentType != "" {
+			dt, err := client.FindDocumentTypeByName(r.DocumentType)
+			if err != nil {
+				return nil, nil, fmt.Errorf("rule %d: %w", i+1, err)
+			}
+			r.typeID = &dt.ID
+		}
+		if r.Correspondent != "" {
+			corr, err := client.FindCorrespondentByName(r.Correspondent)
+			if err != nil {
+				return nil, nil, fmt.Errorf("rule %d: %w", i+1, err)
+			}
+			r.corrID = &corr.ID
+		}
+	}
+
+	return &policy, holdIDs, nil
+}
+
+// matches reports whether the rule applies to doc
+func (r retentionRule) matches(doc api.Document) bool {
+	for _, id := range r.tagIDs {
+		if !slices.Contains(doc.Tags, id) {
+			return false
+		}
+	}
+	if r.typeID != nil && (doc.DocumentType == nil || *doc.DocumentType != *r.typeID) {
+		return false
+	}
+	if r.corrID != nil && (doc.Correspondent == nil || *doc.Correspondent != *r.corrID) {
+		return false
+	}
+	return true
+}
+
+// expires returns the last day a document created on the given day has to
+// be kept under the rule
+func (r retentionRule) expires(created time.Time) time.Time {
+	start := created
+	if r.YearEnd {
+		start = time.Date(created.Year(), time.December, 31, 0, 0, 0, 0, time.Local)
+	}
+	return start.AddDate(r.years, r.months, r.days)
+}
+
+// retentionEntry is a document past its retention period
+type retentionEntry struct {
+	ID        int    `json:"id"`
+	Title     string `json:"title"`
+	Created   string `json:"created"`
+	KeptUntil string `json:"kept_until"`
+	Rule      string `json:"rule"`
+}
+
+func runRetentionApply(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	if !retentionDryRun {
+		if err := client.RequireFeature(api.FeatureTrash); err != nil {
+			return fmt.Errorf("%w; use --dry-run to only list documents", err)
+		}
+	}
+
+	policy, holdIDs, err := loadRetentionPolicy(client, retentionRules)
+	if err != nil {
+		return err
+	}
+
+	hold, err := loadLegalHold(client)
+	if err != nil {
+		return err
+	}
+	if !overrideHold && hold.tagID != 0 {
+		holdIDs = append(holdIDs, hold.tagID)
+	}
+
+	today := time.Now()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local)
+
+	// Each rule fetches its candidates; the server's filters only narrow
+	// them down, the decision is made below against all rules
+	candidates := make(map[int]api.Document)
+	for _, r := range policy.Rules {
+		cutoff := today.AddDate(-r.years, -r.months, -r.days+1)
+		docs, err := listAllDocuments(client, api.DocumentListParams{
+			Tags:          r.Tags,
+			DocumentType:  r.DocumentType,
+			Correspondent: r.Correspondent,
+			CreatedBefore: cutoff.Format("2006-01-02"),
+			TagIDsNone:    holdIDs,
+		})
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			candidates[doc.ID] = doc
+		}
+	}
+
+	var entries []retentionEntry
+	onHold := 0
+	for _, doc := range candidates {
+		if slices.ContainsFunc(holdIDs, func(id int) bool { return slices.Contains(doc.Tags, id) }) {
+			continue
+		}
+		if !overrideHold && hold.holds(doc) {
+			onHold++
+			continue
+		}
+
+		// The created date is a calendar day, don't let time zones shift it
+		created, err := time.ParseInLocation("2006-01-02", doc.CreatedDate, time.Local)
+		if err != nil {
+			c := doc.Created.Local()
+			created = time.Date(c.Year(), c.Month(), c.Day(), 0, 0, 0, 0, time.Local)
+		}
+
+		// The longest period of all matching rules applies
+		var governing *retentionRule
+		var expires time.Time
+		for i, r := range policy.Rules {
+			if !r.matches(doc) {
+				continue
+			}
+			if e := r.expires(created); governing == nil || e.After(expires) {
+				governing, expires = &policy.Rules[i], e
+			}
+		}
+		if governing == nil || !expires.Before(today) {
+			continue
+		}
+
+		entries = append(entries, retentionEntry{
+			ID:        doc.ID,
+			Title:     doc.Title,
+			Created:   created.Format("2006-01-02"),
+			KeptUntil: expires.Format("2006-01-02"),
+			Rule:      governing.String(),
+		})
+	}
+	hold.reportSkipped(onHold)
+	slices.SortFunc(entries, func(a, b retentionEntry) int {
+		return cmp.Or(strings.Compare(a.Created, b.Created), cmp.Compare(a.ID, b.ID))
+	})
+
+	if len(entries) == 0 {
+		if isJSON() {
+			return printJSON(entries)
+		}
+		if !isQuiet() {
+			fmt.Println("No documents past retention")
+		}
+		return nil
+	}
+	if isJSON() && retentionDryRun {
+		return printJSON(entries)
+	}
+
+	if !isJSON() {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTITLE\tCREATED\tKEPT UNTIL\tRULE")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", e.ID, truncate(e.Title, 40), e.Created, e.KeptUntil, e.Rule)
+		}
+		w.Flush()
+	}
+
+	if retentionDryRun {
+		if !isQuiet() {
+			fmt.Fprintf(os.Stderr, "\n%d document(s) past retention (dry run)\n", len(entries))
+		}
+		return nil
+	}
+
+	if !retentionForce {
+		if !confirmAction(fmt.Sprintf("Move %d document(s) to the trash?", len(entries))) {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	ids := make([]int, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	if err := client.BulkEditDocuments(ids, api.BulkDelete()); err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(entries)
+	}
+	if !isQuiet() {
+		fmt.Printf("Moved %d document(s) to the trash\n", len(entries))
+	}
+
+	return nil
+}
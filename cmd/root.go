@@ -2,17 +2,30 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 
+	"github.com/julianfbeck/paperless-cli/internal/config"
 	"github.com/spf13/cobra"
 )
 
 var (
-	jsonOutput bool
-	quietMode  bool
-	noColor    bool
-	urlFlag    string
-	version    = "dev"
+	jsonOutput  bool
+	quietMode   bool
+	noColor     bool
+	urlFlag     string
+	debugFlag   bool
+	traceFlag   bool
+	caFileFlag  string
+	insecure    bool
+	clientCert  string
+	clientKey   string
+	maxPar      int
+	noCache     bool
+	profileFlag string
+	noFilter    bool
+	noProgress  bool
+	version     = "dev"
 )
 
 var rootCmd = &cobra.Command{
@@ -21,12 +34,58 @@ var rootCmd = &cobra.Command{
 	Long: `A command-line interface for managing documents in Paperless-ngx.
 
 Set PAPERLESS_URL and PAPERLESS_TOKEN environment variables for authentication,
-or use 'paperless config set-url' and 'paperless config set-token' to save them.`,
+or use 'paperless config set-url' and 'paperless config set-token' to save them.
+
+Further instances are configured as profiles and selected with --profile or
+PAPERLESS_PROFILE, e.g. 'paperless --profile business config set-url <url>'.
+
+A .paperless.yaml in the current directory or a parent sets the profile and
+upload defaults for a project:
+
+  profile: home
+  tags: [taxes, "2024"]
+  correspondent: Tax Office
+  document_type: Tax Return
+  storage_path: Taxes
+  title: "Taxes 2024: {name}"`,
 	Version: version,
+	// Only the profile name is chosen here; the config file is read and the
+	// client set up by the commands that need them, so completion and local
+	// commands stay fast
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := selectProfile(); err != nil {
+			return err
+		}
+		markStartup("flags")
+		return nil
+	},
+}
+
+// selectProfile applies --profile, PAPERLESS_PROFILE or the profile of the
+// workspace file, in that order
+func selectProfile() error {
+	ws, err := currentWorkspace()
+	if err != nil {
+		return err
+	}
+	name := firstNonEmpty(profileFlag, os.Getenv("PAPERLESS_PROFILE"))
+	if name == "" && ws != nil {
+		name = ws.Profile
+	}
+	config.UseProfile(name)
+	return nil
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	markStartup("init")
+	nameFlagsInErrors(rootCmd)
+	err := rootCmd.Execute()
+	markStartup("run")
+	printStartupProfile()
+	if err != nil {
+		if errors.Is(err, errInterrupted) {
+			os.Exit(exitInterrupted)
+		}
 		os.Exit(1)
 	}
 }
@@ -36,6 +95,19 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&quietMode, "quiet", "q", false, "suppress non-essential output")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable color output")
 	rootCmd.PersistentFlags().StringVarP(&urlFlag, "url", "u", "", "Paperless server URL (overrides env/config)")
+	rootCmd.PersistentFlags().BoolVar(&debugFlag, "debug", false, "log HTTP requests to stderr")
+	rootCmd.PersistentFlags().BoolVar(&traceFlag, "trace", false, "log HTTP requests with redacted headers and bodies to stderr")
+	rootCmd.PersistentFlags().StringVar(&caFileFlag, "ca-file", "", "PEM file with additional trusted CA certificates")
+	rootCmd.PersistentFlags().BoolVar(&insecure, "insecure", false, "skip TLS certificate verification (unsafe)")
+	rootCmd.PersistentFlags().StringVar(&clientCert, "client-cert", "", "PEM client certificate for mutual TLS")
+	rootCmd.PersistentFlags().StringVar(&clientKey, "client-key", "", "PEM private key for --client-cert")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "always refetch tags, correspondents and types instead of revalidating the local cache")
+	rootCmd.PersistentFlags().IntVar(&maxPar, "max-parallel", 8, "upper limit for concurrent requests in bulk operations")
+	rootCmd.PersistentFlags().BoolVar(&noFilter, "no-filter", false, "ignore the profile's document filter")
+	rootCmd.PersistentFlags().BoolVar(&noProgress, "no-progress", false, "don't draw progress bars (they're only drawn on terminals)")
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "configured instance to use (default: PAPERLESS_PROFILE or the top-level settings)")
+	rootCmd.PersistentFlags().BoolVar(&profileStartup, "profile-startup", false, "print how long each startup phase took to stderr")
+	rootCmd.PersistentFlags().MarkHidden("profile-startup")
 }
 
 func isJSON() bool {
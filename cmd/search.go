@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/julianfbeck/paperless-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search documents, tags, correspondents and more",
+	Long: `Search like the search bar of the web interface: documents by title and
+content, and tags, correspondents, document types, storage paths, saved
+views and custom fields by name. Needs Paperless-ngx 2.3 or newer.
+
+Results are grouped by kind in the order of the web interface: documents,
+saved views, tags, correspondents, document types, storage paths and custom
+fields. --json gives them as one list.
+
+With --all-profiles, every configured profile is searched at the same time
+and each result is labeled with its profile.
+
+Example:
+  paperless search invoice
+  paperless search --all-profiles "ACME"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSearch,
+}
+
+var searchAutocompleteCmd = &cobra.Command{
+	Use:   "autocomplete <prefix>",
+	Short: "Suggest search words starting with a prefix",
+	Long: `Suggest words from the search index that complete the last word of the
+prefix, most common first, like the web interface's search field. The
+earlier words of the prefix are kept.
+
+The --query flags of other commands complete the same way in shells with
+completion installed (see 'paperless completion --help').
+
+Example:
+  paperless search autocomplete inv
+  paperless search autocomplete "acme inv" --limit 5`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSearchAutocomplete,
+}
+
+var (
+	searchAllProfiles bool
+	autocompleteLimit int
+)
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.AddCommand(searchAutocompleteCmd)
+
+	searchCmd.Flags().BoolVar(&searchAllProfiles, "all-profiles", false, "search every configured profile")
+	searchAutocompleteCmd.Flags().IntVar(&autocompleteLimit, "limit", 10, "maximum number of suggestions")
+}
+
+// autocompleteQuery completes the last word of query from the search index
+// and returns the whole queries
+func autocompleteQuery(client *api.Client, query string, limit int) ([]string, error) {
+	head, word := "", query
+	if i := strings.LastIndexAny(query, " \t"); i >= 0 {
+		head, word = query[:i+1], query[i+1:]
+	}
+	if word == "" {
+		return nil, nil
+	}
+
+	words, err := client.SearchAutocomplete(word, limit)
+	if err != nil {
+		return nil, err
+	}
+	for i, w := range words {
+		words[i] = head + w
+	}
+	return words, nil
+}
+
+// completeQuery completes --query flags from the search index
+func completeQuery(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if err := selectProfile(); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	client, err := getClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	words, err := autocompleteQuery(client, toComplete, 20)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return words, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// searchHit is one object found by a global search
+type searchHit struct {
+	Profile string `json:"profile,omitempty"`
+	Type    string `json:"type"`
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+}
+
+// searchGroups are the kinds of search hits with their headings, in the
+// order of the web interface's search bar
+var searchGroups = []struct{ kind, title string }{
+	{"document", "Documents"},
+	{"view", "Saved views"},
+	{"tag", "Tags"},
+	{"correspondent", "Correspondents"},
+	{"type", "Document types"},
+	{"storage path", "Storage paths"},
+	{"custom field", "Custom fields"},
+}
+
+// searchHits flattens a global search result
+func searchHits(profile string, result *api.GlobalSearchResult) []searchHit {
+	var hits []searchHit
+	add := func(kind string, id int, name string) {
+		hits = append(hits, searchHit{Profile: profile, Type: kind, ID: id, Name: name})
+	}
+	for _, d := range result.Documents {
+		add("document", d.ID, d.Title)
+	}
+	for _, v := range result.SavedViews {
+		add("view", v.ID, v.Name)
+	}
+	for _, t := range result.Tags {
+		add("tag", t.ID, t.Name)
+	}
+	for _, c := range result.Correspondents {
+		add("correspondent", c.ID, c.Name)
+	}
+	for _, t := range result.DocumentTypes {
+		add("type", t.ID, t.Name)
+	}
+	for _, sp := range result.StoragePaths {
+		add("storage path", sp.ID, sp.Name)
+	}
+	for _, f := range result.CustomFields {
+		add("custom field", f.ID, f.Name)
+	}
+	return hits
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	query := args[0]
+
+	var hits []searchHit
+	failed := 0
+	if searchAllProfiles {
+		if urlFlag != "" {
+			return fmt.Errorf("--url can't be combined with --all-profiles")
+		}
+		names, err := config.ProfileNames()
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("no profiles configured")
+		}
+
+		results := make([][]searchHit, len(names))
+		errs := make([]error, len(names))
+		var wg sync.WaitGroup
+		for i, name := range names {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				settings, err := config.Settings(name)
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				client, err := newClient(name, settings)
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				result, err := client.GlobalSearch(query)
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				results[i] = searchHits(name, result)
+			}()
+		}
+		wg.Wait()
+
+		for i, name := range names {
+			if errs[i] != nil {
+				failed++
+				fmt.Fprintf(os.Stderr, "profile %s: %v\n", name, errs[i])
+				continue
+			}
+			hits = append(hits, results[i]...)
+		}
+	} else {
+		client, err := getClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.GlobalSearch(query)
+		if err != nil {
+			return err
+		}
+		hits = searchHits("", result)
+	}
+
+	if isJSON() {
+		if hits == nil {
+			hits = []searchHit{}
+		}
+		if err := printJSON(hits); err != nil {
+			return err
+		}
+	} else if len(hits) == 0 {
+		fmt.Println("No results")
+	} else {
+		printSearchGroups(hits)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("search failed on %d profile(s)", failed)
+	}
+	return nil
+}
+
+// printSearchGroups prints hits under a heading per kind, like the web
+// interface's search bar. Hits of all profiles are grouped together.
+func printSearchGroups(hits []searchHit) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	printed := false
+	for _, group := range searchGroups {
+		var rows []searchHit
+		for _, h := range hits {
+			if h.Type == group.kind {
+				rows = append(rows, h)
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		if printed {
+			fmt.Fprintln(w)
+		}
+		printed = true
+		fmt.Fprintf(w, "%s (%d)\n", group.title, len(rows))
+		for _, h := range rows {
+			if searchAllProfiles {
+				fmt.Fprintf(w, "  %s\t%d\t%s\n", h.Profile, h.ID, fit(h.Name, 60))
+			} else {
+				fmt.Fprintf(w, "  %d\t%s\n", h.ID, fit(h.Name, 60))
+			}
+		}
+	}
+	w.Flush()
+}
+
+func runSearchAutocomplete(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	suggestions, err := autocompleteQuery(client, args[0], autocompleteLimit)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		if suggestions == nil {
+			suggestions = []string{}
+		}
+		return printJSON(suggestions)
+	}
+
+	for _, s := range suggestions {
+		fmt.Println(s)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+)
+
+// snippetWidth is how much of a hit's highlights is shown without --wide
+const snippetWidth = 120
+
+var htmlTagRe = regexp.MustCompile(`<[^>]*>`)
+
+// stripHighlights turns the HTML highlights of a search hit into plain
+// text on one line
+func stripHighlights(s string) string {
+	s = html.UnescapeString(htmlTagRe.ReplaceAllString(s, ""))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// printSearchHits prints search results as a table with the matching
+// passages under each document. Rows have fixed widths, as the passages
+// break up the table.
+func printSearchHits(columns []tableColumn[api.Document], docs []api.Document) {
+	fmt.Println(fixedRow(columns, func(col tableColumn[api.Document]) string { return columnHeader(col.name) }))
+	for _, doc := range docs {
+		fmt.Println(fixedRow(columns, func(col tableColumn[api.Document]) string { return col.value(doc) }))
+		if doc.SearchHit == nil {
+			continue
+		}
+		if text := stripHighlights(doc.SearchHit.Highlights); text != "" {
+			fmt.Printf("    %s\n", fit(text, snippetWidth))
+		}
+		if text := stripHighlights(doc.SearchHit.NoteHighlights); text != "" {
+			fmt.Printf("    Notes: %s\n", fit(text, snippetWidth))
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var configServerCmd = &cobra.Command{
+	Use:   "server",
+	Short: "Manage the server's application settings",
+	Long: `Show and change settings of the Paperless instance itself, such as the OCR
+language and mode or the app title, which otherwise are set in the web UI
+under Configuration. They need a superuser token and Paperless-ngx >= 2.3.
+
+With --ui, the web UI settings of the token's user are used instead, e.g.
+dark_mode.enabled or date_display_locale. Nested UI settings are addressed
+with dotted keys.`,
+}
+
+var configServerGetCmd = &cobra.Command{
+	Use:   "get [key]...",
+	Short: "Show server settings",
+	Long: `Show all server settings or only the given keys. Unset settings fall back
+to the server's environment configuration and are shown as "-".
+
+The --json output can be saved, edited and applied again with
+'config server set --file', to keep the settings under version control.
+
+Example:
+  paperless config server get
+  paperless config server get language app_title
+  paperless config server get --json > paperless-settings.json
+  paperless config server get --ui`,
+	RunE: runConfigServerGet,
+}
+
+var configServerSetCmd = &cobra.Command{
+	Use:   "set [key=value]...",
+	Short: "Change server settings",
+	Long: `Change server settings given as key=value pairs or in a YAML or JSON file.
+Values are converted to the type of the current value; "null" unsets a
+setting so the server's environment configuration applies again. Settings
+that already have the given value aren't sent.
+
+Example:
+  paperless config server set language=deu+eng mode=skip
+  paperless config server set app_title="Family Archive"
+  paperless config server set --file paperless-settings.json
+  paperless config server set --ui dark_mode.enabled=true`,
+	RunE: runConfigServerSet,
+}
+
+var (
+	serverConfigUI   bool
+	serverConfigFile string
+)
+
+func init() {
+	configCmd.AddCommand(configServerCmd)
+	configServerCmd.AddCommand(configServerGetCmd)
+	configServerCmd.AddCommand(configServerSetCmd)
+
+	configServerCmd.PersistentFlags().BoolVar(&serverConfigUI, "ui", false, "use the web UI settings of the token's user")
+	configServerSetCmd.Flags().StringVar(&serverConfigFile, "file", "", "YAML or JSON file with the settings to apply")
+}
+
+// flattenSettings turns nested settings into dotted keys
+func flattenSettings(prefix string, settings map[string]any, out map[string]any) {
+	for key, value := range settings {
+		if nested, ok := value.(map[string]any); ok && len(nested) > 0 {
+			flattenSettings(prefix+key+".", nested, out)
+			continue
+		}
+		out[prefix+key] = value
+	}
+}
+
+// lookupSetting returns the value of a dotted key
+func lookupSetting(settings map[string]any, key string) (any, bool) {
+	var current any = settings
+	for _, part := range strings.Split(key, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// storeSetting sets a dotted key, creating nested maps as needed
+func storeSetting(settings map[string]any, key string, value any) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		nested, ok := settings[part].(map[string]any)
+		if !ok {
+			nested = make(map[string]any)
+			settings[part] = nested
+		}
+		settings = nested
+	}
+	settings[parts[len(parts)-1]] = value
+}
+
+// parseSettingValue converts a command line value to the type of the
+// current value. Unknown types are guessed from the value itself.
+func parseSettingValue(raw string, current any) (any, error) {
+	if raw == "null" {
+		return nil, nil
+	}
+	switch current.(type) {
+	case string:
+		return raw, nil
+	case bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false")
+		}
+		return b, nil
+	case float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number")
+		}
+		return n, nil
+	}
+
+	var value any
+	if err := yaml.Unmarshal([]byte(raw), &value); err != nil || value == nil {
+		return raw, nil
+	}
+	return value, nil
+}
+
+// sameSetting reports whether two values encode to the same JSON, so that
+// e.g. an int from YAML equals the float64 the server sent
+func sameSetting(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
+
+// formatSetting renders a value for the table
+func formatSetting(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "-"
+	case string:
+		if v == "" {
+			return `""`
+		}
+		return v
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// loadServerSettings fetches the settings selected by --ui as a plain map
+func loadServerSettings() (map[string]any, func(map[string]any) (map[string]any, error), error) {
+	client, err := getClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if serverConfigUI {
+		ui, err := client.GetUISettings()
+		if err != nil {
+			return nil, nil, err
+		}
+		if ui.Settings == nil {
+			ui.Settings = make(map[string]any)
+		}
+		save := func(settings map[string]any) (map[string]any, error) {
+			return settings, client.SaveUISettings(settings)
+		}
+		return ui.Settings, save, nil
+	}
+
+	cfg, err := client.GetAppConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	save := func(updates map[string]any) (map[string]any, error) {
+		return client.UpdateAppConfig(cfg.ID(), updates)
+	}
+	return cfg, save, nil
+}
+
+func runConfigServerGet(cmd *cobra.Command, args []string) error {
+	settings, _, err := loadServerSettings()
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		selected := make(map[string]any)
+		for _, key := range args {
+			value, ok := lookupSetting(settings, key)
+			if !ok {
+				return fmt.Errorf("unknown setting: %s", key)
+			}
+			selected[key] = value
+		}
+		settings = selected
+	}
+
+	if isJSON() {
+		return printJSON(settings)
+	}
+
+	flat := make(map[string]any)
+	flattenSettings("", settings, flat)
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE")
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s\t%s\n", key, truncate(formatSetting(flat[key]), 60))
+	}
+	w.Flush()
+
+	return nil
+}
+
+func runConfigServerSet(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && serverConfigFile == "" {
+		return fmt.Errorf("no settings given: pass key=value pairs or --file")
+	}
+
+	var fromFile map[string]any
+	if serverConfigFile != "" {
+		data, err := os.ReadFile(serverConfigFile)
+		if err != nil {
+			return err
+		}
+		if err := yaml.Unmarshal(data, &fromFile); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", serverConfigFile, err)
+		}
+	}
+
+	settings, save, err := loadServerSettings()
+	if err != nil {
+		return err
+	}
+
+	// Pairs are collected as dotted keys, later ones winning over the file
+	wanted := make(map[string]any)
+	flattenSettings("", fromFile, wanted)
+	for _, arg := range args {
+		key, raw, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid setting %q: use key=value", arg)
+		}
+		current, _ := lookupSetting(settings, key)
+		value, err := parseSettingValue(raw, current)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		wanted[key] = value
+	}
+	// The ID identifies the configuration and can't be changed
+	delete(wanted, "id")
+
+	changed := make(map[string]any)
+	for key, value := range wanted {
+		current, ok := lookupSetting(settings, key)
+		if !ok && !serverConfigUI {
+			return fmt.Errorf("unknown setting: %s", key)
+		}
+		if ok && sameSetting(current, value) {
+			continue
+		}
+		changed[key] = value
+	}
+
+	if len(changed) == 0 {
+		if !isQuiet() {
+			fmt.Println("Settings are up to date")
+		}
+		return nil
+	}
+
+	// Application settings are patched; UI settings are saved as a whole
+	updates := changed
+	if serverConfigUI {
+		for key, value := range changed {
+			storeSetting(settings, key, value)
+		}
+		updates = settings
+	}
+
+	result, err := save(updates)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(result)
+	}
+
+	if !isQuiet() {
+		keys := make([]string, 0, len(changed))
+		for key := range changed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("%s set to: %s\n", key, formatSetting(changed[key]))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"text/tabwriter"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var docsSetFieldCmd = &cobra.Command{
+	Use:   "set-field",
+	Short: "Set custom fields on all matching documents",
+	Long: `Set one or more custom fields on every document matching the filters.
+
+Values are validated against each field's type before anything is changed.
+An empty value clears the field. Servers from 2.15 on apply the change with
+bulk edits of up to 100 documents; older servers are updated document by
+document (see --max-parallel). Documents on legal hold (see 'config set
+hold') are skipped unless --override-hold is given.
+
+Example:
+  paperless documents set-field --query "receipt laptop" --field warranty_until=2026-05-01 --dry-run
+  paperless documents set-field --tag bills --correspondent ACME --field Paid=yes --yes`,
+	Args: cobra.NoArgs,
+	RunE: runDocsSetField,
+}
+
+var (
+	setFieldQuery         string
+	setFieldTags          []string
+	setFieldCorrespondent string
+	setFieldDocType       string
+	setFieldAll           bool
+	setFieldValues        []string
+	setFieldDryRun        bool
+	setFieldYes           bool
+)
+
+func init() {
+	documentsCmd.AddCommand(docsSetFieldCmd)
+
+	docsSetFieldCmd.Flags().StringVar(&setFieldQuery, "query", "", "search query")
+	docsSetFieldCmd.RegisterFlagCompletionFunc("query", completeQuery)
+	docsSetFieldCmd.Flags().StringArrayVar(&setFieldTags, "tag", nil, "filter by tag (repeatable)")
+	docsSetFieldCmd.Flags().StringVar(&setFieldCorrespondent, "correspondent", "", "filter by correspondent")
+	docsSetFieldCmd.Flags().StringVar(&setFieldDocType, "type", "", "filter by document type")
+	docsSetFieldCmd.Flags().BoolVar(&setFieldAll, "all", false, "apply to all documents when no filter is given")
+	docsSetFieldCmd.Flags().StringArrayVar(&setFieldValues, "field", nil, "custom field to set: <name|id>=<value> (repeatable)")
+	docsSetFieldCmd.Flags().BoolVar(&setFieldDryRun, "dry-run", false, "show matching documents without changing them")
+	docsSetFieldCmd.Flags().BoolVarP(&setFieldYes, "yes", "y", false, "skip confirmation")
+	addOverrideHoldFlag(docsSetFieldCmd)
+	docsSetFieldCmd.MarkFlagRequired("field")
+}
+
+func runDocsSetField(cmd *cobra.Command, args []string) error {
+	filtered := setFieldQuery != "" || len(setFieldTags) > 0 || setFieldCorrespondent != "" || setFieldDocType != ""
+	if !filtered && !setFieldAll {
+		return fmt.Errorf("no filter given; use --query, --tag, --correspondent, --type or --all")
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	values, err := parseFieldAssignments(client, setFieldValues)
+	if err != nil {
+		return err
+	}
+
+	params := api.DocumentListParams{
+		Query:         setFieldQuery,
+		Tags:          setFieldTags,
+		Correspondent: setFieldCorrespondent,
+		DocumentType:  setFieldDocType,
+	}
+
+	hold, err := loadLegalHold(client)
+	if err != nil {
+		return err
+	}
+
+	if setFieldDryRun {
+		return previewSetField(client, params, values, hold)
+	}
+
+	params.Limit = 1
+	result, err := client.ListDocuments(params)
+	if err != nil {
+		return err
+	}
+	ids, err := hold.exclude(client, result.All)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		fmt.Println("No documents found")
+		return nil
+	}
+
+	if !setFieldYes && !confirmAction(fmt.Sprintf("Set %d custom field(s) on %d document(s)?", len(values), len(ids))) {
+		fmt.Println("Cancelled")
+		return nil
+	}
+
+	var failures map[int]error
+	if client.Supports(api.FeatureBulkCustomFieldValues) {
+		failures = bulkEditBatches(client, ids, api.BulkModifyCustomFields(values, nil))
+	} else {
+		failures = setFieldsOneByOne(client, ids, values)
+	}
+
+	return reportBulkUpdate(len(ids), failures)
+}
+
+// previewSetField lists the documents a set-field run would change
+func previewSetField(client *api.Client, params api.DocumentListParams, values map[int]any, hold *legalHold) error {
+	params.Limit = 100
+	params.Ordering = "id"
+
+	var docs []api.Document
+	for page := 1; ; page++ {
+		params.Page = page
+		result, err := client.ListDocuments(params)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, result.Results...)
+		if result.Next == "" {
+			break
+		}
+	}
+	if !overrideHold {
+		before := len(docs)
+		docs = slices.DeleteFunc(docs, hold.holds)
+		hold.reportSkipped(before - len(docs))
+	}
+
+	fields := api.MergeCustomFields(nil, values)
+	if isJSON() {
+		return printJSON(map[string]interface{}{
+			"dry_run":   true,
+			"fields":    fields,
+			"documents": docs,
+		})
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE")
+	for _, doc := range docs {
+		fmt.Fprintf(w, "%d\t%s\n", doc.ID, truncate(doc.Title, 60))
+	}
+	w.Flush()
+
+	fmt.Fprintf(os.Stderr, "\nDry run: would set %s on %d document(s)\n", strings.Join(setFieldValues, ", "), len(docs))
+
+	return nil
+}
+
+// setFieldsOneByOne updates each document separately, for servers whose bulk
+// edit can't set field values
+func setFieldsOneByOne(client *api.Client, ids []int, values map[int]any) map[int]error {
+	progress, done := newCountProgressBar("Updating")
+	defer done()
+
+	var updated atomic.Int64
+	errs := forEachParallel(ids, maxPar, func(i int, id int) error {
+		_, err := client.SetCustomFields(id, values)
+		if err == nil && progress != nil {
+			progress(updated.Add(1), int64(len(ids)))
+		}
+		return err
+	})
+
+	failures := make(map[int]error)
+	for i, err := range errs {
+		if err != nil {
+			failures[ids[i]] = err
+		}
+	}
+	return failures
+}
+
+// reportBulkUpdate tells how many of total documents were updated and why
+// the others failed
+func reportBulkUpdate(total int, failures map[int]error) error {
+	failed := make([]int, 0, len(failures))
+	for id := range failures {
+		failed = append(failed, id)
+	}
+	sort.Ints(failed)
+
+	if isJSON() {
+		errs := make(map[string]string, len(failures))
+		for _, id := range failed {
+			errs[fmt.Sprint(id)] = failures[id].Error()
+		}
+		if err := printJSON(map[string]interface{}{
+			"updated": total - len(failed),
+			"failed":  errs,
+		}); err != nil {
+			return err
+		}
+	} else {
+		for _, id := range failed {
+			fmt.Fprintf(os.Stderr, "document %d: %v\n", id, failures[id])
+		}
+		if !isQuiet() {
+			fmt.Printf("Updated %d of %d document(s)\n", total-len(failed), total)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to update %d document(s)", len(failed))
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var docsShareCmd = &cobra.Command{
+	Use:   "share <id>",
+	Short: "Create a public share link for a document",
+	Long: `Create a share link for a document and print its public URL. Anyone with
+the URL can download the file without logging in, so set an expiry.
+
+--expires takes a duration like 12h, 7d or 2w, a date (YYYY-MM-DD) or
+"never".
+
+Example:
+  paperless documents share 123 --expires 7d
+  paperless documents share 123 --expires 2025-01-31 --original`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDocsShare,
+}
+
+var shareCmd = &cobra.Command{
+	Use:   "share",
+	Short: "Manage share links",
+	Long:  `List and revoke public share links of documents.`,
+}
+
+var shareListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List share links",
+	Long: `List share links with their document, expiry and public URL.
+
+Example:
+  paperless share list
+  paperless share list --document 123`,
+	Args: cobra.NoArgs,
+	RunE: runShareList,
+}
+
+var shareRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>...",
+	Short: "Revoke share links",
+	Long: `Delete share links so their URLs stop working. Links are given by ID or
+slug, as shown by "paperless share list".
+
+Example:
+  paperless share revoke 5
+  paperless share revoke 5 6 -f`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runShareRevoke,
+}
+
+var (
+	shareExpires  string
+	shareOriginal bool
+	shareDocument int
+	shareForce    bool
+)
+
+func init() {
+	documentsCmd.AddCommand(docsShareCmd)
+	rootCmd.AddCommand(shareCmd)
+	shareCmd.AddCommand(shareListCmd)
+	shareCmd.AddCommand(shareRevokeCmd)
+
+	docsShareCmd.Flags().StringVar(&shareExpires, "expires", "never", "when the link expires: duration (7d, 12h), date (YYYY-MM-DD) or never")
+	docsShareCmd.Flags().BoolVar(&shareOriginal, "original", false, "share the original file instead of the archived PDF")
+
+	shareListCmd.Flags().IntVar(&shareDocument, "document", 0, "only links of this document")
+	addTableFlags(shareListCmd, shareColumns)
+
+	shareRevokeCmd.Flags().BoolVarP(&shareForce, "force", "f", false, "skip confirmation")
+}
+
+// parseExpiry parses --expires into a point in time, or nil for never
+func parseExpiry(s string) (*time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "never" {
+		return nil, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		// Valid through the whole day
+		t = t.AddDate(0, 0, 1).Add(-time.Second)
+		return &t, nil
+	}
+
+	unit := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}[s[len(s)-1]]
+	var d time.Duration
+	if unit != 0 {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiry: %s", s)
+		}
+		d = time.Duration(n) * unit
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return nil, fmt.Errorf("invalid expiry: %s (use e.g. 7d, 12h, 2025-01-31 or never)", s)
+		}
+	}
+	if d <= 0 {
+		return nil, fmt.Errorf("expiry must be in the future: %s", s)
+	}
+
+	t := time.Now().Add(d)
+	return &t, nil
+}
+
+// shareLinkOutput is a share link with its public URL
+type shareLinkOutput struct {
+	api.ShareLink
+	URL string `json:"url"`
+}
+
+func runDocsShare(cmd *cobra.Command, args []string) error {
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid document ID: %s", args[0])
+	}
+	expiration, err := parseExpiry(shareExpires)
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	version := api.ShareArchive
+	if shareOriginal {
+		version = api.ShareOriginal
+	}
+	link, err := client.CreateShareLink(id, expiration, version)
+	if err != nil {
+		return err
+	}
+
+	out := shareLinkOutput{ShareLink: *link, URL: client.ShareURL(*link)}
+	if isJSON() {
+		return printJSON(out)
+	}
+
+	fmt.Println(out.URL)
+	if !isQuiet() {
+		if link.Expiration != nil {
+			fmt.Fprintf(os.Stderr, "Expires %s\n", link.Expiration.Local().Format("2006-01-02 15:04"))
+		} else {
+			fmt.Fprintln(os.Stderr, "Never expires; revoke with: paperless share revoke", link.ID)
+		}
+	}
+
+	return nil
+}
+
+// shareColumns are the columns of the share links list
+var shareColumns = []tableColumn[shareLinkOutput]{
+	{name: "id", value: func(l shareLinkOutput) string { return strconv.Itoa(l.ID) }},
+	{name: "document", value: func(l shareLinkOutput) string { return strconv.Itoa(l.Document) }},
+	{name: "file", value: func(l shareLinkOutput) string { return l.FileVersion }},
+	{name: "created", value: func(l shareLinkOutput) string { return l.Created.Local().Format("2006-01-02 15:04") }},
+	{name: "expires", value: func(l shareLinkOutput) string {
+		if l.Expiration == nil {
+			return "never"
+		}
+		expires := l.Expiration.Local().Format("2006-01-02 15:04")
+		if l.Expiration.Before(time.Now()) {
+			expires += " (expired)"
+		}
+		return expires
+	}},
+	{name: "url", value: func(l shareLinkOutput) string { return l.URL }},
+}
+
+func runShareList(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	columns, err := selectColumns(shareColumns, "id,document,file,expires,url")
+	if err != nil {
+		return err
+	}
+
+	var links []api.ShareLink
+	if shareDocument > 0 {
+		if links, err = client.DocumentShareLinks(shareDocument); err != nil {
+			return err
+		}
+	} else {
+		result, err := client.ListShareLinks(api.ListParams{})
+		if err != nil {
+			return err
+		}
+		links = result.Results
+	}
+
+	out := make([]shareLinkOutput, len(links))
+	for i, link := range links {
+		out[i] = shareLinkOutput{ShareLink: link, URL: client.ShareURL(link)}
+	}
+
+	if isJSON() {
+		return printJSON(out)
+	}
+
+	if len(out) == 0 {
+		fmt.Println("No share links")
+		return nil
+	}
+
+	printTable(columns, out)
+
+	return nil
+}
+
+func runShareRevoke(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	// Slugs need the list to find their IDs
+	var all []api.ShareLink
+	ids := make([]int, len(args))
+	for i, arg := range args {
+		if id, err := strconv.Atoi(arg); err == nil {
+			ids[i] = id
+			continue
+		}
+		if all == nil {
+			result, err := client.ListShareLinks(api.ListParams{})
+			if err != nil {
+				return err
+			}
+			all = result.Results
+		}
+		for _, link := range all {
+			if link.Slug == arg {
+				ids[i] = link.ID
+			}
+		}
+		if ids[i] == 0 {
+			return fmt.Errorf("share link not found: %s", arg)
+		}
+	}
+
+	if !shareForce && !confirmAction(fmt.Sprintf("Revoke %d share link(s)?", len(ids))) {
+		fmt.Println("Cancelled")
+		return nil
+	}
+
+	for _, id := range ids {
+		if err := client.DeleteShareLink(id); err != nil {
+			return err
+		}
+		if !isQuiet() {
+			fmt.Printf("Revoked share link %d\n", id)
+		}
+	}
+
+	return nil
+}
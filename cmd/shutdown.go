@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// exitInterrupted is the exit status of a command stopped by Ctrl-C, so
+// scripts can tell an interrupted run from a failed one
+const exitInterrupted = 130
+
+// errInterrupted is returned by commands that stopped early on Ctrl-C
+var errInterrupted = errors.New("interrupted")
+
+// shutdown lets long-running commands stop cleanly. The first Ctrl-C (or
+// SIGTERM) only sets a flag the command checks between items, so the item in
+// flight is finished and progress can be saved; a second one exits at once,
+// or cancels context if the command uses it.
+type shutdown struct {
+	signals   chan os.Signal
+	stopping  atomic.Bool
+	aborting  atomic.Bool
+	abortable atomic.Bool
+	ctx       context.Context
+	cancel    context.CancelFunc
+	done      chan struct{}
+	once      sync.Once
+}
+
+// handleShutdown installs the signal handler until release is called
+func handleShutdown() *shutdown {
+	s := &shutdown{signals: make(chan os.Signal, 3), done: make(chan struct{})}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	signal.Notify(s.signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		for {
+			select {
+			case <-s.signals:
+			case <-s.done:
+				return
+			}
+			if s.stopping.Swap(true) {
+				// The command cleans up after its context is cancelled; a
+				// third Ctrl-C doesn't wait for that
+				if s.abortable.Load() && !s.aborting.Swap(true) {
+					fmt.Fprintln(os.Stderr, "\nAborting, press Ctrl-C again to exit at once")
+					s.cancel()
+					continue
+				}
+				fmt.Fprintln(os.Stderr, "\nAborted")
+				os.Exit(exitInterrupted)
+			}
+			fmt.Fprintln(os.Stderr, "\nStopping after the current item, press Ctrl-C again to abort")
+		}
+	}()
+
+	return s
+}
+
+// requested reports whether the command should stop
+func (s *shutdown) requested() bool {
+	return s.stopping.Load()
+}
+
+// context returns a context the second Ctrl-C cancels, instead of exiting,
+// so the item in flight can be aborted without leaving partial files behind
+func (s *shutdown) context() context.Context {
+	s.abortable.Store(true)
+	return s.ctx
+}
+
+// release restores the default signal handling. It may be called more
+// than once, e.g. before prompting and again deferred.
+func (s *shutdown) release() {
+	s.once.Do(func() {
+		signal.Stop(s.signals)
+		close(s.done)
+		s.cancel()
+	})
+}
+
+// interrupted prints why the command stopped and returns errInterrupted,
+// without the error and usage text cobra shows for other errors
+func interrupted(cmd *cobra.Command, format string, a ...interface{}) error {
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, format+"\n", a...)
+	}
+	return errInterrupted
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// slowListing is how long a document listing may take before it counts as
+// slow
+const slowListing = 5 * time.Second
+
+// narrowPageSize is the page size --auto falls back to for slow listings
+const narrowPageSize = 10
+
+// tableFields are the document fields the list tables show, so nothing else
+// needs to be fetched for them. selectDocumentColumns sets them from
+// --columns.
+var tableFields = []string{"id", "title", "created", "created_date", "tags"}
+
+var (
+	fetchFields   []string
+	fetchTruncate bool
+	fetchAuto     bool
+)
+
+// addFetchFlags adds the flags that make document listings lighter on huge
+// archives
+func addFetchFlags(c *cobra.Command) {
+	c.Flags().StringSliceVar(&fetchFields, "fields", nil, "only fetch these document fields, e.g. id,title (for --json)")
+	c.Flags().BoolVar(&fetchTruncate, "truncate-content", false, "only fetch the start of each document's content")
+	c.Flags().BoolVar(&fetchAuto, "auto", false,
+		fmt.Sprintf("retry with truncated content and %d per page when listing takes over %s", narrowPageSize, slowListing))
+}
+
+// fetchDocuments lists documents with the fetch flags applied. Tables only
+// fetch the fields they show. A slow listing is retried narrower with --auto,
+// otherwise it gets a warning.
+func fetchDocuments(client *api.Client, params api.DocumentListParams) (*api.PaginatedResponse[api.Document], error) {
+	params.TruncateContent = params.TruncateContent || fetchTruncate
+	switch {
+	case len(fetchFields) > 0:
+		params.Fields = fetchFields
+	case !isJSON():
+		params.Fields = tableFields
+	}
+
+	if !fetchAuto {
+		start := time.Now()
+		result, err := client.ListDocuments(params)
+		if elapsed := time.Since(start); err == nil && elapsed > slowListing && !isQuiet() {
+			fmt.Fprintf(os.Stderr, "Warning: listing documents took %s. --fields, --truncate-content or a lower --limit make it faster, --auto falls back to them when it's slow.\n",
+				elapsed.Round(100*time.Millisecond))
+		}
+		return result, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), slowListing)
+	defer cancel()
+	result, err := client.ListDocumentsContext(ctx, params)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return result, err
+	}
+
+	// Keep the page starting at the same document
+	narrowed := params
+	narrowed.TruncateContent = true
+	if narrowed.Limit == 0 || narrowed.Limit > narrowPageSize {
+		narrowed.Limit = narrowPageSize
+		if params.Page > 1 && params.Limit > 0 {
+			narrowed.Page = (params.Page-1)*params.Limit/narrowPageSize + 1
+		}
+	}
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Listing documents took over %s, retrying with truncated content and %d per page\n",
+			slowListing, narrowed.Limit)
+	}
+	return client.ListDocuments(narrowed)
+}
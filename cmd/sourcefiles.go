@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// sourcePolicy says what happens to a local file after uploading it
+type sourcePolicy struct {
+	// action is "keep", "delete" or "move"
+	action string
+	// dir is the target directory of "move"
+	dir string
+}
+
+// String renders the policy the way it's given on the command line
+func (p sourcePolicy) String() string {
+	if p.action == "move" {
+		return "move:" + p.dir
+	}
+	return p.action
+}
+
+// parseSourcePolicy parses "keep", "delete" or "move:DIR". Delete is only
+// accepted if allowDelete is set.
+func parseSourcePolicy(flag, value string, allowDelete bool) (sourcePolicy, error) {
+	switch {
+	case value == "" || value == "keep":
+		return sourcePolicy{action: "keep"}, nil
+	case value == "delete" && allowDelete:
+		return sourcePolicy{action: "delete"}, nil
+	case strings.HasPrefix(value, "move:"):
+		dir := strings.TrimPrefix(value, "move:")
+		if dir == "" {
+			return sourcePolicy{}, fmt.Errorf("--%s: move needs a directory, e.g. move:./done", flag)
+		}
+		return sourcePolicy{action: "move", dir: dir}, nil
+	}
+	if allowDelete {
+		return sourcePolicy{}, fmt.Errorf("--%s must be keep, delete or move:DIR, got %q", flag, value)
+	}
+	return sourcePolicy{}, fmt.Errorf("--%s must be keep or move:DIR, got %q", flag, value)
+}
+
+// apply handles filePath according to the policy
+func (p sourcePolicy) apply(filePath string) error {
+	switch p.action {
+	case "delete":
+		if err := os.Remove(filePath); err != nil {
+			return err
+		}
+		if !isQuiet() {
+			fmt.Fprintf(os.Stderr, "Deleted %s\n", filePath)
+		}
+	case "move":
+		target, err := moveFile(filePath, p.dir)
+		if err != nil {
+			return err
+		}
+		if !isQuiet() {
+			fmt.Fprintf(os.Stderr, "Moved %s to %s\n", filePath, target)
+		}
+	}
+	return nil
+}
+
+// uploadSourcePolicies are applied to each file by uploadFile, except
+// consumed, which applies once the server has consumed the file
+var uploadSourcePolicies struct {
+	success  sourcePolicy
+	failure  sourcePolicy
+	consumed sourcePolicy
+}
+
+// setSourcePolicies parses the --on-success and --on-failure flags into
+// uploadSourcePolicies
+func setSourcePolicies(onSuccess, onFailure string) error {
+	var err error
+	if uploadSourcePolicies.success, err = parseSourcePolicy("on-success", onSuccess, true); err != nil {
+		return err
+	}
+	if uploadSourcePolicies.failure, err = parseSourcePolicy("on-failure", onFailure, false); err != nil {
+		return err
+	}
+	return nil
+}
+
+// setConsumedPolicy sets the policy for consumed files from --delete-after
+// and --move-to
+func setConsumedPolicy(deleteAfter bool, moveTo string) {
+	switch {
+	case deleteAfter:
+		uploadSourcePolicies.consumed = sourcePolicy{action: "delete"}
+	case moveTo != "":
+		uploadSourcePolicies.consumed = sourcePolicy{action: "move", dir: moveTo}
+	default:
+		uploadSourcePolicies.consumed = sourcePolicy{action: "keep"}
+	}
+}
+
+// moveFile moves filePath into dir, creating dir if needed. If the name is
+// taken, a number is added: "scan.pdf" becomes "scan (1).pdf".
+func moveFile(filePath, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	base := filepath.Base(filePath)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	for n := 0; ; n++ {
+		name := base
+		if n > 0 {
+			name = stem + " (" + strconv.Itoa(n) + ")" + ext
+		}
+		target := filepath.Join(dir, name)
+
+		// Link or create exclusively so an existing file is never replaced.
+		// Hard links don't work across file systems and on some shares.
+		err := os.Link(filePath, target)
+		if err != nil && !os.IsExist(err) {
+			err = copyExclusive(filePath, target)
+		} else if err == nil {
+			err = os.Remove(filePath)
+		}
+		if os.IsExist(err) {
+			continue
+		}
+		return target, err
+	}
+}
+
+// copyExclusive copies src to a new file dst and removes src. It fails if
+// dst exists.
+func copyExclusive(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	in.Close()
+	return os.Remove(src)
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var docsSplitCmd = &cobra.Command{
+	Use:   "split <id>",
+	Short: "Split a document into several",
+	Long: `Split a document into new documents, one per comma-separated page range,
+to break apart a batch scan that contained several letters.
+
+The server does the split in the background and consumes each part like an
+upload; the parts keep the original's metadata. Pages left out of --pages
+are dropped. --delete-originals deletes the original once the parts exist;
+it asks for confirmation first and refuses a document on legal hold (see
+'config set hold') unless --override-hold is given.
+
+Example:
+  paperless documents split 42 --pages 1-3,4-7
+  paperless documents split 42 --pages 1,2-5,6 --delete-originals --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDocsSplit,
+}
+
+var (
+	splitPages           string
+	splitDeleteOriginals bool
+	splitForce           bool
+)
+
+func init() {
+	documentsCmd.AddCommand(docsSplitCmd)
+
+	docsSplitCmd.Flags().StringVar(&splitPages, "pages", "", `page ranges of the new documents, like "1-3,4-7"`)
+	docsSplitCmd.Flags().BoolVar(&splitDeleteOriginals, "delete-originals", false, "delete the original afterwards")
+	docsSplitCmd.Flags().BoolVarP(&splitForce, "force", "f", false, "skip confirmation")
+	addOverrideHoldFlag(docsSplitCmd)
+	docsSplitCmd.MarkFlagRequired("pages")
+}
+
+func runDocsSplit(cmd *cobra.Command, args []string) error {
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid document ID: %s", args[0])
+	}
+
+	ranges, err := parsePageRanges(splitPages)
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	if err := client.RequireFeature(api.FeaturePDFEditing); err != nil {
+		return err
+	}
+
+	doc, err := client.GetDocument(id)
+	if err != nil {
+		return err
+	}
+	if doc.PageCount != nil {
+		for _, r := range ranges {
+			if r[1] > *doc.PageCount {
+				return fmt.Errorf("page %d is out of range; document %d has %d page(s)", r[1], id, *doc.PageCount)
+			}
+		}
+	}
+
+	if splitDeleteOriginals {
+		hold, err := loadLegalHold(client)
+		if err != nil {
+			return err
+		}
+		if !overrideHold && hold.holds(*doc) {
+			return fmt.Errorf("document %d is on legal hold (%s) and can't be deleted; pass --override-hold or drop --delete-originals", id, hold.name)
+		}
+
+		msg := fmt.Sprintf("Split %q into %d document(s) and delete the original?", doc.Title, len(ranges))
+		if !splitForce && !confirmAction(msg) {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	pages := formatPageRanges(ranges)
+	if err := client.BulkEditDocuments([]int{id}, api.BulkSplit(pages, splitDeleteOriginals)); err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(map[string]interface{}{
+			"document":         id,
+			"pages":            pages,
+			"delete_originals": splitDeleteOriginals,
+			"queued":           true,
+		})
+	}
+	if !isQuiet() {
+		fmt.Printf("Split of document %d into %d document(s) queued\n", id, len(ranges))
+	}
+	return nil
+}
+
+// parsePageRanges parses page ranges like "1-3,4,5-7" into first and last
+// pages. The ranges must be in order and not overlap.
+func parsePageRanges(s string) ([][2]int, error) {
+	var ranges [][2]int
+	last := 0
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		from, to, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil || first < 1 {
+			return nil, fmt.Errorf("invalid page range: %q", part)
+		}
+		end := first
+		if isRange {
+			if end, err = strconv.Atoi(strings.TrimSpace(to)); err != nil || end < first {
+				return nil, fmt.Errorf("invalid page range: %q", part)
+			}
+		}
+		if first <= last {
+			return nil, fmt.Errorf("page ranges must be in order and not overlap: %q", s)
+		}
+		ranges = append(ranges, [2]int{first, end})
+		last = end
+	}
+	return ranges, nil
+}
+
+// formatPageRanges formats ranges the way the server expects them
+func formatPageRanges(ranges [][2]int) string {
+	parts := make([]string, len(ranges))
+	for i, r := range ranges {
+		if r[0] == r[1] {
+			parts[i] = strconv.Itoa(r[0])
+		} else {
+			parts[i] = fmt.Sprintf("%d-%d", r[0], r[1])
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParsePageRanges(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    [][2]int
+		wantErr bool
+	}{
+		{in: "1-3,4,5-7", want: [][2]int{{1, 3}, {4, 4}, {5, 7}}},
+		{in: "2", want: [][2]int{{2, 2}}},
+		{in: " 2 - 4 , 6 ", want: [][2]int{{2, 4}, {6, 6}}},
+		{in: "3-3", want: [][2]int{{3, 3}}},
+		{in: "1,3-4,9", want: [][2]int{{1, 1}, {3, 4}, {9, 9}}},
+
+		// N-M with M < N
+		{in: "3-1", wantErr: true},
+		{in: "1,5-4", wantErr: true},
+
+		// Overlapping ranges
+		{in: "1-3,2-5", wantErr: true},
+		{in: "1-3,3", wantErr: true},
+		{in: "2,2", wantErr: true},
+
+		// Out of order
+		{in: "4,2", wantErr: true},
+		{in: "5-7,1-3", wantErr: true},
+
+		// Malformed
+		{in: "", wantErr: true},
+		{in: "0", wantErr: true},
+		{in: "0-2", wantErr: true},
+		{in: "-3", wantErr: true},
+		{in: "3-", wantErr: true},
+		{in: "a", wantErr: true},
+		{in: "1,,2", wantErr: true},
+		{in: "1-2-3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parsePageRanges(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parsePageRanges(%q) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parsePageRanges(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("parsePageRanges(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatPageRanges(t *testing.T) {
+	for _, in := range []string{"1-3,4,5-7", "2", "1,3-4,9"} {
+		ranges, err := parsePageRanges(in)
+		if err != nil {
+			t.Fatalf("parsePageRanges(%q) failed: %v", in, err)
+		}
+		if got := formatPageRanges(ranges); got != in {
+			t.Errorf("formatPageRanges(%v) = %q, want %q", ranges, got, in)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// pdfTool returns the installed command to combine PDFs with; what names
+// the feature needing it for the error message
+func pdfTool(what string) (string, error) {
+	for _, name := range []string{"qpdf", "pdftk"} {
+		if _, err := exec.LookPath(name); err == nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("%s needs qpdf or pdftk to be installed", what)
+}
+
+// runPDFTool runs qpdf or pdftk and returns its standard output. qpdf exits
+// with 3 for warnings, which still produce the output.
+func runPDFTool(tool string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	c := exec.Command(tool, args...)
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		if exit, ok := err.(*exec.ExitError); !ok || tool != "qpdf" || exit.ExitCode() != 3 {
+			return nil, fmt.Errorf("%s failed: %s", tool, strings.TrimSpace(firstNonEmpty(stderr.String(), string(out), err.Error())))
+		}
+	}
+	return out, nil
+}
+
+// expandStamp fills the {date} and {id} placeholders of a stamp text
+func expandStamp(text string, id int) string {
+	return strings.NewReplacer(
+		"{date}", time.Now().Format("2006-01-02"),
+		"{id}", strconv.Itoa(id),
+	).Replace(text)
+}
+
+// pageMediaBoxes returns the MediaBox of each page, following inheritance
+// from the page tree
+func pageMediaBoxes(path string) ([][4]float64, error) {
+	f, r, err := pdf.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	boxes := make([][4]float64, r.NumPage())
+	for i := range boxes {
+		boxes[i] = [4]float64{0, 0, 612, 792}
+		for node := r.Page(i + 1).V; !node.IsNull(); node = node.Key("Parent") {
+			if box := node.Key("MediaBox"); box.Len() == 4 {
+				for j := range boxes[i] {
+					boxes[i][j] = box.Index(j).Float64()
+				}
+				break
+			}
+		}
+	}
+	return boxes, nil
+}
+
+// pdfText encodes s as a PDF string in WinAnsiEncoding. Characters outside
+// Latin-1 are replaced by "?".
+func pdfText(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0xff || (r >= 0x7f && r < 0xa0):
+			b.WriteByte('?')
+		default:
+			b.WriteByte(byte(r))
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// stampPages builds a PDF with the text diagonally across pages of the given
+// sizes, to be laid over the document
+func stampPages(text string, boxes [][4]float64) []byte {
+	pw := newPDFWriter()
+
+	// Objects 1-4 are the catalog, page tree, font and transparency, then
+	// two per page
+	kids := make([]string, len(boxes))
+	for i := range boxes {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	pw.object("<< /Type /Catalog /Pages 2 0 R >>")
+	pw.object("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(boxes))
+	pw.object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	pw.object("<< /Type /ExtGState /ca 0.3 >>")
+
+	// Helvetica Bold averages about 0.6 em per character
+	chars := float64(len([]rune(text)))
+	for i, box := range boxes {
+		w, h := box[2]-box[0], box[3]-box[1]
+		angle := math.Atan2(h, w)
+		cos, sin := math.Cos(angle), math.Sin(angle)
+		size := math.Min(72, 0.8*math.Hypot(w, h)/(0.6*chars))
+		width := 0.6 * chars * size
+
+		// Center the baseline, moved down by a third of the size so the
+		// letters rather than the baseline are centered
+		x := box[0] + w/2 - cos*width/2 + sin*size/3
+		y := box[1] + h/2 - sin*width/2 - cos*size/3
+
+		content := fmt.Sprintf("q /GS0 gs 0.5 0 0 rg BT /F1 %.1f Tf %.4f %.4f %.4f %.4f %.2f %.2f Tm %s Tj ET Q",
+			size, cos, sin, -sin, cos, x, y, pdfText(text))
+		pw.object("<< /Type /Page /Parent 2 0 R /MediaBox [%g %g %g %g] /Resources << /Font << /F1 3 0 R >> /ExtGState << /GS0 4 0 R >> >> /Contents %d 0 R >>",
+			box[0], box[1], box[2], box[3], 6+2*i)
+		pw.object("<< /Length %d >>\nstream\n%s\nendstream", len(content), content)
+	}
+
+	return pw.bytes()
+}
+
+// stampPDF lays text over every page of the PDF at path, replacing it
+func stampPDF(tool, path, text string) error {
+	boxes, err := pageMediaBoxes(path)
+	if err != nil {
+		return fmt.Errorf("failed to read PDF: %w", err)
+	}
+
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".paperless-stamp-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	stampPath := filepath.Join(dir, "stamp.pdf")
+	if err := os.WriteFile(stampPath, stampPages(text, boxes), 0644); err != nil {
+		return err
+	}
+
+	outPath := filepath.Join(dir, "out.pdf")
+	if tool == "qpdf" {
+		_, err = runPDFTool("qpdf", path, "--overlay", stampPath, "--", outPath)
+	} else {
+		_, err = runPDFTool("pdftk", path, "multistamp", stampPath, "output", outPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(outPath, path)
+}
+
+// stampDownload stamps a downloaded file if it is a PDF. If stamping fails,
+// the file is removed so it can't be passed on unstamped by mistake.
+func stampDownload(tool, path, text string, id int) error {
+	head := make([]byte, 5)
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	_, err = f.Read(head)
+	f.Close()
+	if err != nil || !bytes.Equal(head, []byte("%PDF-")) {
+		if !isQuiet() {
+			fmt.Fprintf(os.Stderr, "%s isn't a PDF, not stamped\n", path)
+		}
+		return nil
+	}
+
+	if err := stampPDF(tool, path, expandStamp(text, id)); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to stamp %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// processStart is taken when the cmd package is initialized, which is after
+// the runtime and dependencies are set up but before the command tree is built
+var processStart = time.Now()
+
+// profileStartup prints how long each startup phase took
+var profileStartup bool
+
+// startupMark is the end of a startup phase
+type startupMark struct {
+	phase string
+	at    time.Time
+}
+
+var startupMarks []startupMark
+
+// markStartup records that a startup phase ended. Marks are cheap enough to
+// take unconditionally; they are only printed with --profile-startup.
+func markStartup(phase string) {
+	startupMarks = append(startupMarks, startupMark{phase, time.Now()})
+}
+
+// printStartupProfile writes the duration of each phase to stderr
+func printStartupProfile() {
+	if !profileStartup {
+		return
+	}
+	prev := processStart
+	for _, m := range startupMarks {
+		fmt.Fprintf(os.Stderr, "startup: %-8s %8.2fms\n", m.phase, float64(m.at.Sub(prev).Microseconds())/1000)
+		prev = m.at
+	}
+	fmt.Fprintf(os.Stderr, "startup: %-8s %8.2fms\n", "total", float64(prev.Sub(processStart).Microseconds())/1000)
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:     "status",
+	Aliases: []string{"doctor"},
+	Short:   "Check server connectivity and token permissions",
+	Long: `Check that the configured server is reachable and probe which parts of
+the API the token is allowed to use. If the server has update checking
+enabled, the latest Paperless-ngx release is shown as well.
+
+Read access is checked for each object type, and write access is checked by
+creating and deleting a temporary tag. Any failing check lists the CLI
+commands that will not work with the current token.
+
+Example:
+  paperless status
+  paperless doctor --json`,
+	RunE: runStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
+
+// permissionCheck is the outcome of a single status probe
+type permissionCheck struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Denied  bool   `json:"denied,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Affects string `json:"affects,omitempty"`
+}
+
+// readProbes lists the endpoints checked for read access and the commands depending on them
+var readProbes = []struct {
+	name    string
+	path    string
+	affects string
+}{
+	{"read documents", "/api/documents/?page_size=1", "documents list/search/get/download/content"},
+	{"read tags", "/api/tags/?page_size=1", "tags list/get, --tag name resolution"},
+	{"read correspondents", "/api/correspondents/?page_size=1", "correspondents list/get, --correspondent name resolution"},
+	{"read document types", "/api/document_types/?page_size=1", "types list/get, --type name resolution"},
+	{"read storage paths", "/api/storage_paths/?page_size=1", "storage list/get"},
+	{"read saved views", "/api/saved_views/?page_size=1", "views list/get"},
+	{"read tasks", "/api/tasks/", "tasks status"},
+	{"read statistics", "/api/statistics/", "stats"},
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	version := ""
+	var unsupported []string
+	if v, err := client.ServerVersion(); err == nil {
+		version = v.String()
+		for _, f := range api.Features() {
+			if !client.Supports(f) {
+				unsupported = append(unsupported, fmt.Sprintf("%s (>= %s)", f, api.RequiredVersion(f)))
+			}
+		}
+	}
+
+	// The update check is informational; servers may have it disabled
+	var remote *api.RemoteVersion
+	if r, err := client.GetRemoteVersion(); err == nil && r.Checked() {
+		remote = r
+	}
+
+	var checks []permissionCheck
+	for _, probe := range readProbes {
+		checks = append(checks, newPermissionCheck(probe.name, probe.affects, client.CheckAccess(probe.path)))
+	}
+	checks = append(checks, checkWriteAccess(client))
+
+	failed := 0
+	denied := 0
+	for _, c := range checks {
+		if !c.OK {
+			failed++
+		}
+		if c.Denied {
+			denied++
+		}
+	}
+
+	if isJSON() {
+		if err := printJSON(map[string]interface{}{
+			"url":         client.BaseURL(),
+			"version":     version,
+			"api_version": client.APIVersion(),
+			"api_max":     client.ServerAPIVersion(),
+			"latest":      remote,
+			"unsupported": unsupported,
+			"checks":      checks,
+		}); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("Server:  %s\n", client.BaseURL())
+		if version != "" {
+			fmt.Printf("Version: %s\n", version)
+		} else {
+			fmt.Println("Version: unknown")
+		}
+		switch {
+		case remote == nil:
+		case remote.UpdateAvailable:
+			fmt.Printf("Latest:  %s (update available)\n", remote.Version)
+		default:
+			fmt.Printf("Latest:  %s (up to date)\n", remote.Version)
+		}
+		if max := client.ServerAPIVersion(); max > 0 {
+			fmt.Printf("API:     v%d (server supports up to v%d)\n", client.APIVersion(), max)
+		}
+		if len(unsupported) > 0 {
+			fmt.Printf("Missing: %s\n", strings.Join(unsupported, ", "))
+		}
+		fmt.Println()
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CHECK\tSTATUS\tDETAILS")
+		for _, c := range checks {
+			status := "ok"
+			details := ""
+			if c.Denied {
+				status = "denied"
+				details = "will not work: " + c.Affects
+			} else if !c.OK {
+				status = "error"
+				details = truncate(c.Error, 60)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", c.Name, status, details)
+		}
+		w.Flush()
+
+		if !isQuiet() && denied > 0 {
+			fmt.Fprintf(os.Stderr, "\nWarning: the token lacks %d permission(s); the commands listed above will fail\n", denied)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+
+	return nil
+}
+
+// checkWriteAccess creates and deletes a scratch tag to detect read-only tokens
+func checkWriteAccess(client *api.Client) permissionCheck {
+	const affects = "create/edit/delete commands, documents edit"
+
+	name := fmt.Sprintf("paperless-cli-check-%d", time.Now().Unix())
+	tag, err := client.CreateTag(name, "", nil)
+	if err != nil {
+		return newPermissionCheck("write tags", affects, err)
+	}
+
+	return newPermissionCheck("write tags", affects, client.DeleteTag(tag.ID))
+}
+
+func newPermissionCheck(name, affects string, err error) permissionCheck {
+	check := permissionCheck{Name: name, OK: err == nil}
+	if err != nil {
+		check.Error = err.Error()
+		check.Denied = api.IsForbidden(err)
+		check.Affects = affects
+	}
+	return check
+}
@@ -2,10 +2,10 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"maps"
 	"strconv"
-	"text/tabwriter"
 
+	"github.com/julianfbeck/paperless-cli/internal/api"
 	"github.com/spf13/cobra"
 )
 
@@ -13,7 +13,7 @@ var storageCmd = &cobra.Command{
 	Use:     "storage",
 	Aliases: []string{"paths", "storage-paths"},
 	Short:   "Manage storage paths",
-	Long:    `List, create, and delete storage paths.`,
+	Long:    `List, create, edit, and delete storage paths.`,
 }
 
 var storageListCmd = &cobra.Command{
@@ -41,14 +41,29 @@ Example:
 var storageCreateCmd = &cobra.Command{
 	Use:   "create <name> <path>",
 	Short: "Create a new storage path",
-	Long: `Create a new storage path.
+	Long: `Create a new storage path. With --match and --matching-algorithm, new documents
+matching the text are filed under it automatically.
 
 Example:
-  paperless storage create "Archive" "archive/{{ created_year }}"`,
+  paperless storage create "Archive" "archive/{{ created_year }}"
+  paperless storage create "Insurance" "insurance/{{ title }}" --match "policy insurance" --matching-algorithm any`,
 	Args: cobra.ExactArgs(2),
 	RunE: runStorageCreate,
 }
 
+var storageEditCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Edit a storage path",
+	Long: `Edit a storage path's name, path template or matching.
+
+Example:
+  paperless storage edit 5 --name "Old Archive"
+  paperless storage edit 5 --path "archive/{{ created_year }}/{{ title }}"
+  paperless storage edit 5 --match "^INV-" --matching-algorithm regex --insensitive=false`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStorageEdit,
+}
+
 var storageDeleteCmd = &cobra.Command{
 	Use:   "delete <id>",
 	Short: "Delete a storage path",
@@ -61,25 +76,55 @@ Example:
 	RunE: runStorageDelete,
 }
 
-var storageForce bool
+var (
+	storageForce     bool
+	storageListName  string
+	storageOwnership ownershipFlags
+	storageMatching  matchingFlags
+	storageEditName  string
+	storageEditPath  string
+)
 
 func init() {
 	rootCmd.AddCommand(storageCmd)
 	storageCmd.AddCommand(storageListCmd)
 	storageCmd.AddCommand(storageGetCmd)
 	storageCmd.AddCommand(storageCreateCmd)
+	storageCmd.AddCommand(storageEditCmd)
 	storageCmd.AddCommand(storageDeleteCmd)
 
+	storageListCmd.Flags().StringVar(&storageListName, "name", "", "only storage paths whose name contains this text")
+	addTableFlags(storageListCmd, storageColumns)
+	storageOwnership.register(storageCreateCmd)
+	storageMatching.register(storageCreateCmd)
+	storageEditCmd.Flags().StringVar(&storageEditName, "name", "", "new name")
+	storageEditCmd.Flags().StringVar(&storageEditPath, "path", "", "new path template")
+	storageMatching.register(storageEditCmd)
+	storageOwnership.register(storageEditCmd)
 	storageDeleteCmd.Flags().BoolVarP(&storageForce, "force", "f", false, "skip confirmation")
 }
 
+// storageColumns are the columns of the storage paths list
+var storageColumns = []tableColumn[api.StoragePath]{
+	{name: "id", value: func(sp api.StoragePath) string { return strconv.Itoa(sp.ID) }},
+	{name: "name", value: func(sp api.StoragePath) string { return sp.Name }},
+	{name: "path", value: func(sp api.StoragePath) string { return fit(sp.Path, 40) }},
+	{name: "docs", value: func(sp api.StoragePath) string { return strconv.Itoa(sp.DocumentCount) }},
+	{name: "match", value: func(sp api.StoragePath) string { return fit(sp.Match, 30) }},
+}
+
 func runStorageList(cmd *cobra.Command, args []string) error {
 	client, err := getClient()
 	if err != nil {
 		return err
 	}
 
-	result, err := client.ListStoragePaths()
+	columns, err := selectColumns(storageColumns, "id,name,path,docs")
+	if err != nil {
+		return err
+	}
+
+	result, err := client.ListStoragePaths(api.ListParams{NameContains: storageListName})
 	if err != nil {
 		return err
 	}
@@ -93,12 +138,7 @@ func runStorageList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tNAME\tPATH\tDOCS")
-	for _, sp := range result.Results {
-		fmt.Fprintf(w, "%d\t%s\t%s\t%d\n", sp.ID, sp.Name, truncate(sp.Path, 40), sp.DocumentCount)
-	}
-	w.Flush()
+	printTable(columns, result.Results)
 
 	return nil
 }
@@ -127,6 +167,7 @@ func runStorageGet(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Name:      %s\n", sp.Name)
 	fmt.Printf("Path:      %s\n", sp.Path)
 	fmt.Printf("Slug:      %s\n", sp.Slug)
+	fmt.Printf("Matching:  %s\n", matchingLabel(sp.MatchingAlgo, sp.Match, sp.IsInsensitive))
 	fmt.Printf("Documents: %d\n", sp.DocumentCount)
 
 	return nil
@@ -138,10 +179,24 @@ func runStorageCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	sp, err := client.CreateStoragePath(args[0], args[1])
+	fields, err := storageMatching.updates(cmd)
+	if err != nil {
+		return err
+	}
+	owner, err := storageOwnership.resolve(client)
+	if err != nil {
+		return err
+	}
+
+	sp, err := client.CreateStoragePath(args[0], args[1], fields)
 	if err != nil {
 		return err
 	}
+	if updates := owner.updates(nil, false); len(updates) > 0 {
+		if sp, err = client.UpdateStoragePath(sp.ID, updates); err != nil {
+			return err
+		}
+	}
 
 	if isJSON() {
 		return printJSON(sp)
@@ -154,6 +209,62 @@ func runStorageCreate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runStorageEdit(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid storage path ID: %s", args[0])
+	}
+
+	updates, err := storageMatching.updates(cmd)
+	if err != nil {
+		return err
+	}
+	if storageEditName != "" {
+		updates["name"] = storageEditName
+	}
+	if storageEditPath != "" {
+		updates["path"] = storageEditPath
+	}
+
+	owner, err := storageOwnership.resolve(client)
+	if err != nil {
+		return err
+	}
+	var current *api.ObjectPermissions
+	if owner.shares != nil {
+		sp, err := client.GetStoragePath(id)
+		if err != nil {
+			return err
+		}
+		current = sp.Permissions
+	}
+	maps.Copy(updates, owner.updates(current, false))
+
+	if len(updates) == 0 {
+		return fmt.Errorf("no changes specified")
+	}
+
+	sp, err := client.UpdateStoragePath(id, updates)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(sp)
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Updated storage path %d\n", id)
+	}
+
+	return nil
+}
+
 func runStorageDelete(cmd *cobra.Command, args []string) error {
 	client, err := getClient()
 	if err != nil {
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var docsSuggestCmd = &cobra.Command{
+	Use:   "suggest <id>",
+	Short: "Show suggested metadata for a document",
+	Long: `Show the correspondent, document type, storage path, tags and dates that
+Paperless suggests for a document, next to its current values.
+
+--apply sets the first suggested correspondent, type and storage path and
+adds all suggested tags. --interactive asks about each change and offers
+the suggested dates as created date.
+
+Example:
+  paperless documents suggest 123
+  paperless documents suggest 123 --apply
+  paperless documents suggest 123 -i`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDocsSuggest,
+}
+
+var (
+	suggestApply       bool
+	suggestInteractive bool
+)
+
+func init() {
+	documentsCmd.AddCommand(docsSuggestCmd)
+
+	docsSuggestCmd.Flags().BoolVar(&suggestApply, "apply", false, "apply all suggestions except dates")
+	docsSuggestCmd.Flags().BoolVarP(&suggestInteractive, "interactive", "i", false, "ask before applying each suggestion")
+	docsSuggestCmd.MarkFlagsMutuallyExclusive("apply", "interactive")
+}
+
+// suggestedObjects are the suggestions of one kind with their names
+type suggestedObjects struct {
+	Current   []namedObject `json:"current"`
+	Suggested []namedObject `json:"suggested"`
+}
+
+func runDocsSuggest(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid document ID: %s", args[0])
+	}
+
+	doc, err := client.GetDocument(id)
+	if err != nil {
+		return err
+	}
+	suggestions, err := client.GetDocumentSuggestions(id)
+	if err != nil {
+		return err
+	}
+
+	w := &uploadWizard{client: client}
+	if err := w.loadChoices(); err != nil {
+		return err
+	}
+	paths, err := client.ListStoragePaths(api.ListParams{})
+	if err != nil {
+		return err
+	}
+	var storagePaths []namedObject
+	for _, sp := range paths.Results {
+		storagePaths = append(storagePaths, namedObject{sp.ID, sp.Name})
+	}
+
+	named := func(objs []namedObject, ids []int) []namedObject {
+		result := []namedObject{}
+		for _, id := range ids {
+			result = append(result, namedObject{id, objectName(objs, &id)})
+		}
+		return result
+	}
+	single := func(id *int) []int {
+		if id == nil {
+			return nil
+		}
+		return []int{*id}
+	}
+
+	kinds := []struct {
+		key  string
+		objs suggestedObjects
+	}{
+		{"correspondent", suggestedObjects{named(w.correspondents, single(doc.Correspondent)), named(w.correspondents, suggestions.Correspondents)}},
+		{"document_type", suggestedObjects{named(w.types, single(doc.DocumentType)), named(w.types, suggestions.DocumentTypes)}},
+		{"storage_path", suggestedObjects{named(storagePaths, single(doc.StoragePath)), named(storagePaths, suggestions.StoragePaths)}},
+		{"tags", suggestedObjects{named(w.tags, doc.Tags), named(w.tags, suggestions.Tags)}},
+	}
+
+	if !isJSON() {
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "FIELD\tCURRENT\tSUGGESTED")
+		for _, k := range kinds {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", k.key, objectNames(k.objs.Current), objectNames(k.objs.Suggested))
+		}
+		fmt.Fprintf(tw, "created\t%s\t%s\n", doc.CreatedDate, strings.Join(suggestions.Dates, ", "))
+		tw.Flush()
+	}
+
+	updates := make(map[string]interface{})
+	if suggestApply || suggestInteractive {
+		for _, k := range kinds {
+			if k.key == "tags" {
+				var add []int
+				for _, tag := range k.objs.Suggested {
+					if slices.Contains(doc.Tags, tag.ID) {
+						continue
+					}
+					if suggestInteractive && !confirmAction(fmt.Sprintf("Add tag %s?", tag.Name)) {
+						continue
+					}
+					add = append(add, tag.ID)
+				}
+				if len(add) > 0 {
+					updates["tags"] = append(append([]int(nil), doc.Tags...), add...)
+				}
+				continue
+			}
+
+			if len(k.objs.Suggested) == 0 {
+				continue
+			}
+			best := k.objs.Suggested[0]
+			if len(k.objs.Current) > 0 && k.objs.Current[0].ID == best.ID {
+				continue
+			}
+			if suggestInteractive && !confirmAction(fmt.Sprintf("Set %s to %s?", strings.ReplaceAll(k.key, "_", " "), best.Name)) {
+				continue
+			}
+			updates[k.key] = best.ID
+		}
+
+		if suggestInteractive {
+			if date := chooseSuggestedDate(suggestions.Dates, doc.CreatedDate); date != "" {
+				updates["created_date"] = date
+			}
+		}
+	}
+
+	if len(updates) > 0 {
+		if _, err := client.UpdateDocument(id, updates); err != nil {
+			return err
+		}
+	}
+
+	if isJSON() {
+		result := map[string]interface{}{"id": id}
+		for _, k := range kinds {
+			result[k.key] = k.objs
+		}
+		dates := suggestions.Dates
+		if dates == nil {
+			dates = []string{}
+		}
+		result["created"] = map[string]interface{}{"current": doc.CreatedDate, "suggested": dates}
+		if suggestApply || suggestInteractive {
+			result["applied"] = updates
+		}
+		return printJSON(result)
+	}
+
+	if !isQuiet() && (suggestApply || suggestInteractive) {
+		if len(updates) == 0 {
+			fmt.Println("Nothing to change")
+		} else {
+			fmt.Printf("Updated document %d\n", id)
+		}
+	}
+
+	return nil
+}
+
+// chooseSuggestedDate asks which suggested date to use as created date and
+// returns "" to keep the current one
+func chooseSuggestedDate(dates []string, current string) string {
+	var choices []string
+	for _, d := range dates {
+		if d != current {
+			choices = append(choices, d)
+		}
+	}
+	if len(choices) == 0 || quietMode {
+		return ""
+	}
+
+	for i, d := range choices {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, d)
+	}
+	fmt.Fprintf(os.Stderr, "Set created date (1-%d, empty to keep %s): ", len(choices), current)
+	var response string
+	fmt.Scanln(&response)
+	n, err := strconv.Atoi(response)
+	if err != nil || n < 1 || n > len(choices) {
+		return ""
+	}
+	return choices[n-1]
+}
+
+// objectNames joins the names of objs for display
+func objectNames(objs []namedObject) string {
+	names := make([]string, len(objs))
+	for i, o := range objs {
+		names[i] = o.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// Flags of the list tables. Only one command runs at a time, so they share
+// the variables.
+var (
+	tableColumns []string
+	tableWide    bool
+)
+
+// tableColumn is a column of a list table
+type tableColumn[T any] struct {
+	// name is what --columns selects the column by; upper-cased it's the
+	// header
+	name  string
+	value func(T) string
+	// width is the column's width in tables that are printed page by page
+	// and can't fit the columns to their content
+	width int
+}
+
+// addTableFlags adds --columns and --wide to a list command, completing the
+// names of its columns
+func addTableFlags[T any](c *cobra.Command, columns []tableColumn[T]) {
+	names := columnNames(columns)
+	c.Flags().StringSliceVar(&tableColumns, "columns", nil, "table columns to show, from "+strings.Join(names, ","))
+	c.Flags().BoolVar(&tableWide, "wide", false, "don't truncate long values in the table")
+	c.RegisterFlagCompletionFunc("columns", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		// Complete the last of the comma-separated names
+		i := strings.LastIndex(toComplete, ",")
+		var completions []string
+		for _, name := range names {
+			completions = append(completions, toComplete[:i+1]+name)
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	})
+}
+
+func columnNames[T any](columns []tableColumn[T]) []string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.name
+	}
+	return names
+}
+
+// selectColumns returns the columns given with --columns, in that order,
+// or else those named by defaults
+func selectColumns[T any](columns []tableColumn[T], defaults string) ([]tableColumn[T], error) {
+	names := tableColumns
+	if len(names) == 0 {
+		names = strings.Split(defaults, ",")
+	}
+
+	selected := make([]tableColumn[T], 0, len(names))
+	for _, name := range names {
+		i := slices.IndexFunc(columns, func(col tableColumn[T]) bool {
+			return col.name == strings.ToLower(strings.TrimSpace(name))
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("unknown column: %s (available: %s)", name, strings.Join(columnNames(columns), ", "))
+		}
+		selected = append(selected, columns[i])
+	}
+	return selected, nil
+}
+
+// columnHeader is the header of a column, like "STORAGE PATH"
+func columnHeader(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", " "))
+}
+
+// printTable prints rows with the columns, fitted to their content
+func printTable[T any](columns []tableColumn[T], rows []T) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = columnHeader(col.name)
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+
+	values := make([]string, len(columns))
+	for _, row := range rows {
+		for i, col := range columns {
+			values[i] = col.value(row)
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	w.Flush()
+}
+
+// fixedRow formats a table row with the columns' fixed widths, for tables
+// printed page by page. The last column isn't padded.
+func fixedRow[T any](columns []tableColumn[T], value func(tableColumn[T]) string) string {
+	var b strings.Builder
+	for i, col := range columns {
+		if i == len(columns)-1 {
+			b.WriteString(value(col))
+			break
+		}
+		fmt.Fprintf(&b, "%-*s  ", col.width, value(col))
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// fit truncates s to max characters unless --wide is given
+func fit(s string, max int) string {
+	if tableWide {
+		return s
+	}
+	return truncate(s, max)
+}
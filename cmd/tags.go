@@ -2,10 +2,10 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"maps"
 	"strconv"
-	"text/tabwriter"
 
+	"github.com/julianfbeck/paperless-cli/internal/api"
 	"github.com/spf13/cobra"
 )
 
@@ -40,11 +40,14 @@ Example:
 var tagsCreateCmd = &cobra.Command{
 	Use:   "create <name>",
 	Short: "Create a new tag",
-	Long: `Create a new tag.
+	Long: `Create a new tag. With --match and --matching-algorithm, new documents
+matching the text are tagged automatically; --inbox tags every new document.
 
 Example:
   paperless tags create "receipts"
-  paperless tags create "important" --color "#ff0000"`,
+  paperless tags create "important" --color "#ff0000" --text-color "#ffffff"
+  paperless tags create "insurance" --match "policy insurance" --matching-algorithm any
+  paperless tags create "new" --inbox`,
 	Args: cobra.ExactArgs(1),
 	RunE: runTagsCreate,
 }
@@ -56,7 +59,9 @@ var tagsEditCmd = &cobra.Command{
 
 Example:
   paperless tags edit 5 --name "new name"
-  paperless tags edit 5 --color "#00ff00"`,
+  paperless tags edit 5 --color "#00ff00"
+  paperless tags edit 5 --match "^INV-" --matching-algorithm regex --insensitive=false
+  paperless tags edit 5 --inbox=false`,
 	Args: cobra.ExactArgs(1),
 	RunE: runTagsEdit,
 }
@@ -77,6 +82,12 @@ var (
 	tagColor      string
 	tagName       string
 	tagForce      bool
+	tagOwnership  ownershipFlags
+	tagMatching   matchingFlags
+	tagTextColor  string
+	tagInbox      bool
+
+	tagsListName string
 )
 
 func init() {
@@ -87,19 +98,44 @@ func init() {
 	tagsCmd.AddCommand(tagsEditCmd)
 	tagsCmd.AddCommand(tagsDeleteCmd)
 
+	tagsListCmd.Flags().StringVar(&tagsListName, "name", "", "only tags whose name contains this text")
+	addTableFlags(tagsListCmd, tagColumns)
 	tagsCreateCmd.Flags().StringVar(&tagColor, "color", "", "tag color (hex, e.g. #ff0000)")
+	tagsCreateCmd.Flags().StringVar(&tagTextColor, "text-color", "", "text color (hex, e.g. #ffffff)")
+	tagsCreateCmd.Flags().BoolVar(&tagInbox, "inbox", false, "add the tag to every new document")
+	tagMatching.register(tagsCreateCmd)
 	tagsEditCmd.Flags().StringVar(&tagName, "name", "", "new name")
 	tagsEditCmd.Flags().StringVar(&tagColor, "color", "", "new color (hex)")
+	tagsEditCmd.Flags().StringVar(&tagTextColor, "text-color", "", "new text color (hex)")
+	tagsEditCmd.Flags().BoolVar(&tagInbox, "inbox", false, "add the tag to every new document, --inbox=false to stop")
+	tagMatching.register(tagsEditCmd)
+	tagOwnership.register(tagsCreateCmd)
+	tagOwnership.register(tagsEditCmd)
 	tagsDeleteCmd.Flags().BoolVarP(&tagForce, "force", "f", false, "skip confirmation")
 }
 
+// tagColumns are the columns of the tags list
+var tagColumns = []tableColumn[api.Tag]{
+	{name: "id", value: func(t api.Tag) string { return strconv.Itoa(t.ID) }},
+	{name: "name", value: func(t api.Tag) string { return t.Name }},
+	{name: "color", value: func(t api.Tag) string { return t.Color }},
+	{name: "docs", value: func(t api.Tag) string { return strconv.Itoa(t.DocumentCount) }},
+	{name: "match", value: func(t api.Tag) string { return fit(t.Match, 30) }},
+	{name: "inbox", value: func(t api.Tag) string { return yesNo(t.IsInboxTag) }},
+}
+
 func runTagsList(cmd *cobra.Command, args []string) error {
 	client, err := getClient()
 	if err != nil {
 		return err
 	}
 
-	result, err := client.ListTags()
+	columns, err := selectColumns(tagColumns, "id,name,color,docs")
+	if err != nil {
+		return err
+	}
+
+	result, err := client.ListTags(api.ListParams{NameContains: tagsListName})
 	if err != nil {
 		return err
 	}
@@ -113,12 +149,7 @@ func runTagsList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tNAME\tCOLOR\tDOCS")
-	for _, tag := range result.Results {
-		fmt.Fprintf(w, "%d\t%s\t%s\t%d\n", tag.ID, tag.Name, tag.Color, tag.DocumentCount)
-	}
-	w.Flush()
+	printTable(columns, result.Results)
 
 	return nil
 }
@@ -147,6 +178,7 @@ func runTagsGet(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Name:      %s\n", tag.Name)
 	fmt.Printf("Slug:      %s\n", tag.Slug)
 	fmt.Printf("Color:     %s\n", tag.Color)
+	fmt.Printf("Matching:  %s\n", matchingLabel(tag.MatchingAlgo, tag.Match, tag.IsInsensitive))
 	fmt.Printf("Documents: %d\n", tag.DocumentCount)
 	fmt.Printf("Inbox:     %t\n", tag.IsInboxTag)
 
@@ -159,10 +191,24 @@ func runTagsCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	tag, err := client.CreateTag(args[0], tagColor)
+	fields, err := tagFields(cmd)
 	if err != nil {
 		return err
 	}
+	owner, err := tagOwnership.resolve(client)
+	if err != nil {
+		return err
+	}
+
+	tag, err := client.CreateTag(args[0], tagColor, fields)
+	if err != nil {
+		return err
+	}
+	if updates := owner.updates(nil, false); len(updates) > 0 {
+		if tag, err = client.UpdateTag(tag.ID, updates); err != nil {
+			return err
+		}
+	}
 
 	if isJSON() {
 		return printJSON(tag)
@@ -175,6 +221,22 @@ func runTagsCreate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// tagFields returns the API fields of the matching, --text-color and --inbox
+// flags that were given
+func tagFields(cmd *cobra.Command) (map[string]interface{}, error) {
+	fields, err := tagMatching.updates(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if tagTextColor != "" {
+		fields["text_color"] = tagTextColor
+	}
+	if cmd.Flags().Changed("inbox") {
+		fields["is_inbox_tag"] = tagInbox
+	}
+	return fields, nil
+}
+
 func runTagsEdit(cmd *cobra.Command, args []string) error {
 	client, err := getClient()
 	if err != nil {
@@ -186,7 +248,10 @@ func runTagsEdit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid tag ID: %s", args[0])
 	}
 
-	updates := make(map[string]interface{})
+	updates, err := tagFields(cmd)
+	if err != nil {
+		return err
+	}
 	if tagName != "" {
 		updates["name"] = tagName
 	}
@@ -194,6 +259,20 @@ func runTagsEdit(cmd *cobra.Command, args []string) error {
 		updates["color"] = tagColor
 	}
 
+	owner, err := tagOwnership.resolve(client)
+	if err != nil {
+		return err
+	}
+	var current *api.ObjectPermissions
+	if owner.shares != nil {
+		obj, err := client.GetTag(id)
+		if err != nil {
+			return err
+		}
+		current = obj.Permissions
+	}
+	maps.Copy(updates, owner.updates(current, false))
+
 	if len(updates) == 0 {
 		return fmt.Errorf("no changes specified")
 	}
@@ -2,7 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/julianfbeck/paperless-cli/internal/api"
 	"github.com/spf13/cobra"
 )
 
@@ -23,9 +29,110 @@ Example:
 	RunE: runTasksStatus,
 }
 
+var tasksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recent tasks",
+	Long: `List background tasks, newest first. Consumption tasks show the uploaded
+file and the document it became, or why it failed.
+
+Example:
+  paperless tasks list
+  paperless tasks list --status failure
+  paperless tasks list --status failure --acknowledged=false --json`,
+	Args: cobra.NoArgs,
+	RunE: runTasksList,
+}
+
+var (
+	tasksStatus       string
+	tasksType         string
+	tasksAcknowledged bool
+	tasksLimit        int
+)
+
+// taskStatuses are the states a task can be in
+var taskStatuses = []string{"PENDING", "STARTED", "SUCCESS", "FAILURE", "REVOKED"}
+
 func init() {
 	rootCmd.AddCommand(tasksCmd)
 	tasksCmd.AddCommand(tasksStatusCmd)
+	tasksCmd.AddCommand(tasksListCmd)
+
+	tasksListCmd.Flags().StringVar(&tasksStatus, "status", "", "filter by status: pending, started, success, failure or revoked")
+	tasksListCmd.Flags().StringVar(&tasksType, "type", "", "filter by task type, e.g. auto_task or manual_task")
+	tasksListCmd.Flags().BoolVar(&tasksAcknowledged, "acknowledged", false, "filter by whether the task was dismissed in the web UI")
+	tasksListCmd.Flags().IntVar(&tasksLimit, "limit", 25, "maximum number of tasks to show (0 for all)")
+	addTableFlags(tasksListCmd, taskColumns)
+}
+
+// taskColumns are the columns of the tasks list
+var taskColumns = []tableColumn[api.Task]{
+	{name: "task-id", value: func(t api.Task) string { return t.TaskID }},
+	{name: "status", value: func(t api.Task) string { return t.Status }},
+	{name: "created", value: func(t api.Task) string { return formatTaskTime(t.DateCreated) }},
+	{name: "done", value: func(t api.Task) string { return formatTaskTime(t.DateDone) }},
+	{name: "type", value: func(t api.Task) string { return t.Type }},
+	{name: "file", value: func(t api.Task) string { return fit(t.TaskFileName, 30) }},
+	{name: "document", value: func(t api.Task) string { return firstNonEmpty(t.RelatedDoc, "-") }},
+	{name: "result", value: func(t api.Task) string { return fit(strings.ReplaceAll(t.Result, "\n", " "), 50) }},
+}
+
+func runTasksList(cmd *cobra.Command, args []string) error {
+	columns, err := selectColumns(taskColumns, "task-id,status,created,file,document,result")
+	if err != nil {
+		return err
+	}
+
+	params := api.TaskListParams{Type: tasksType}
+	if tasksStatus != "" {
+		params.Status = strings.ToUpper(tasksStatus)
+		if !slices.Contains(taskStatuses, params.Status) {
+			return fmt.Errorf("invalid status %q: use pending, started, success, failure or revoked", tasksStatus)
+		}
+	}
+	if cmd.Flags().Changed("acknowledged") {
+		params.Acknowledged = &tasksAcknowledged
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	tasks, err := client.ListTasks(params)
+	if err != nil {
+		return err
+	}
+	total := len(tasks)
+	if tasksLimit > 0 && len(tasks) > tasksLimit {
+		tasks = tasks[:tasksLimit]
+	}
+
+	if isJSON() {
+		return printJSON(tasks)
+	}
+
+	if len(tasks) == 0 {
+		fmt.Println("No tasks found")
+		return nil
+	}
+
+	printTable(columns, tasks)
+
+	if !isQuiet() && total > len(tasks) {
+		fmt.Fprintf(os.Stderr, "\nShowing %d of %d tasks\n", len(tasks), total)
+	}
+
+	return nil
+}
+
+// formatTaskTime shortens a task timestamp to local minutes
+func formatTaskTime(s string) string {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return s
+	}
+	return t.Local().Format("2006-01-02 15:04")
 }
 
 func runTasksStatus(cmd *cobra.Command, args []string) error {
@@ -60,3 +167,34 @@ func runTasksStatus(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// waitForDocument polls a consumption task until it finishes and returns the
+// ID of the document it created
+func waitForDocument(client *api.Client, taskID string, timeout, interval time.Duration) (int, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		task, err := client.GetTask(taskID)
+		if err == nil {
+			switch task.Status {
+			case "SUCCESS":
+				id, err := strconv.Atoi(task.RelatedDoc)
+				if err != nil {
+					return 0, fmt.Errorf("task %s finished without a document: %s", taskID, task.Result)
+				}
+				return id, nil
+			case "FAILURE", "REVOKED":
+				return 0, fmt.Errorf("consumption failed: %s", task.Result)
+			}
+		}
+
+		// The task may not be registered yet right after the upload, so
+		// lookup errors are retried until the deadline
+		if time.Now().After(deadline) {
+			if err != nil {
+				return 0, err
+			}
+			return 0, fmt.Errorf("timed out waiting for task %s (status: %s)", taskID, task.Status)
+		}
+		time.Sleep(interval)
+	}
+}
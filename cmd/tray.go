@@ -0,0 +1,303 @@
+//go:build tray
+
+// The tray needs fyne.io/systray, which the default build leaves out:
+//
+//	go get fyne.io/systray && go build -tags tray
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+
+	"fyne.io/systray"
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var trayCmd = &cobra.Command{
+	Use:   "tray",
+	Short: "Show the inbox and recent documents in the system tray",
+	Long: `Run as a system tray (menu bar) companion showing the number of documents
+in the inbox and the most recently added documents, refreshed every
+--refresh. Clicking a document opens it in the web UI.
+
+With --watch, the directory is watched for new files as by 'paperless
+watch' (with its watch_rules), and the menu can pause and resume it.
+
+This command only exists in builds with the tray tag:
+  go get fyne.io/systray && go build -tags tray
+
+Example:
+  paperless tray
+  paperless tray --watch ~/scans --refresh 30s`,
+	Args: cobra.NoArgs,
+	RunE: runTray,
+}
+
+var (
+	trayRefresh time.Duration
+	trayWatch   string
+)
+
+// trayRecent is the number of recent documents in the menu
+const trayRecent = 5
+
+func init() {
+	rootCmd.AddCommand(trayCmd)
+
+	trayCmd.Flags().DurationVar(&trayRefresh, "refresh", time.Minute, "how often to update the inbox count and recent documents")
+	trayCmd.Flags().StringVar(&trayWatch, "watch", "", "watch this directory for new files, with pause and resume in the menu")
+}
+
+func runTray(cmd *cobra.Command, args []string) error {
+	if trayRefresh <= 0 {
+		return fmt.Errorf("--refresh must be positive")
+	}
+	if trayWatch != "" {
+		if info, err := os.Stat(trayWatch); err != nil || !info.IsDir() {
+			return fmt.Errorf("not a directory: %s", trayWatch)
+		}
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	t := &tray{client: client}
+	systray.Run(t.ready, t.exit)
+	return nil
+}
+
+// tray is the state of the tray menu
+type tray struct {
+	client *api.Client
+
+	inbox   *systray.MenuItem
+	recent  []*systray.MenuItem
+	pause   *systray.MenuItem
+	refresh *systray.MenuItem
+	quit    *systray.MenuItem
+
+	mu        sync.Mutex
+	recentIDs []int
+	watch     *exec.Cmd
+}
+
+// ready builds the menu and starts the refresh loop
+func (t *tray) ready() {
+	systray.SetIcon(trayIcon())
+	systray.SetTitle("Paperless")
+	systray.SetTooltip("Paperless")
+
+	t.inbox = systray.AddMenuItem("Inbox: …", "Open the inbox in the web UI")
+	recent := systray.AddMenuItem("Recent documents", "")
+	t.recentIDs = make([]int, trayRecent)
+	for i := range trayRecent {
+		item := recent.AddSubMenuItem("", "Open in the web UI")
+		item.Hide()
+		t.recent = append(t.recent, item)
+		go t.onClick(item, func() { t.openRecent(i) })
+	}
+	systray.AddSeparator()
+
+	web := systray.AddMenuItem("Open web UI", "")
+	go t.onClick(web, func() { t.open(t.client.BaseURL()) })
+	go t.onClick(t.inbox, func() { t.open(t.client.BaseURL() + "/documents?is_in_inbox=1") })
+
+	if trayWatch != "" {
+		t.pause = systray.AddMenuItem("Pause watch folder", "Stop uploading new files from "+trayWatch)
+		go t.onClick(t.pause, t.toggleWatch)
+		t.startWatch()
+	}
+
+	systray.AddSeparator()
+	t.refresh = systray.AddMenuItem("Refresh", "")
+	t.quit = systray.AddMenuItem("Quit", "")
+	go t.onClick(t.quit, systray.Quit)
+
+	go func() {
+		for {
+			t.update()
+			select {
+			case <-t.refresh.ClickedCh:
+			case <-time.After(trayRefresh):
+			}
+		}
+	}()
+}
+
+// exit stops the watch folder when the tray quits
+func (t *tray) exit() {
+	t.stopWatch()
+}
+
+// onClick runs f for every click on item
+func (t *tray) onClick(item *systray.MenuItem, f func()) {
+	for range item.ClickedCh {
+		f()
+	}
+}
+
+// update fetches the inbox count and the recent documents
+func (t *tray) update() {
+	stats, err := t.client.GetStatistics()
+	if err != nil {
+		t.inbox.SetTitle("Inbox: unavailable")
+		systray.SetTooltip(fmt.Sprintf("Paperless: %v", err))
+		return
+	}
+	inbox, _ := stats["documents_inbox"].(float64)
+	t.inbox.SetTitle(fmt.Sprintf("Inbox: %.0f document(s)", inbox))
+	systray.SetTooltip(fmt.Sprintf("Paperless: %.0f in the inbox", inbox))
+	if inbox > 0 {
+		systray.SetTitle(fmt.Sprintf("Paperless (%.0f)", inbox))
+	} else {
+		systray.SetTitle("Paperless")
+	}
+
+	result, err := t.client.ListDocuments(api.DocumentListParams{
+		Fields:   []string{"id", "title"},
+		Ordering: "-added",
+		Limit:    trayRecent,
+		Page:     1,
+	})
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, item := range t.recent {
+		if i >= len(result.Results) {
+			t.recentIDs[i] = 0
+			item.Hide()
+			continue
+		}
+		doc := result.Results[i]
+		t.recentIDs[i] = doc.ID
+		item.SetTitle(truncate(doc.Title, 50))
+		item.Show()
+	}
+}
+
+// openRecent opens the i-th recent document in the web UI
+func (t *tray) openRecent(i int) {
+	t.mu.Lock()
+	id := t.recentIDs[i]
+	t.mu.Unlock()
+	if id != 0 {
+		t.open(t.client.DocumentURL(id))
+	}
+}
+
+// open opens url in the browser, reporting failures in the tooltip
+func (t *tray) open(url string) {
+	if err := openBrowser(url); err != nil {
+		systray.SetTooltip(err.Error())
+	}
+}
+
+// toggleWatch pauses or resumes the watch folder
+func (t *tray) toggleWatch() {
+	t.mu.Lock()
+	running := t.watch != nil
+	t.mu.Unlock()
+
+	if running {
+		t.stopWatch()
+		t.pause.SetTitle("Resume watch folder")
+	} else {
+		t.startWatch()
+		t.pause.SetTitle("Pause watch folder")
+	}
+}
+
+// startWatch runs 'paperless watch' on the watch folder in the background
+func (t *tray) startWatch() {
+	exe, err := os.Executable()
+	if err != nil {
+		systray.SetTooltip(err.Error())
+		return
+	}
+	args := []string{"watch", trayWatch}
+	if profileFlag != "" {
+		args = append(args, "--profile", profileFlag)
+	}
+	c := exec.Command(exe, args...)
+	c.Stdout, c.Stderr = os.Stdout, os.Stderr
+	if err := c.Start(); err != nil {
+		systray.SetTooltip(fmt.Sprintf("watch folder: %v", err))
+		return
+	}
+
+	t.mu.Lock()
+	t.watch = c
+	t.mu.Unlock()
+
+	go func() {
+		c.Wait()
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.watch == c {
+			// It stopped by itself, e.g. because the directory went away
+			t.watch = nil
+			t.pause.SetTitle("Resume watch folder")
+		}
+	}()
+}
+
+// stopWatch stops the watch folder, letting an upload in flight finish
+// where the platform allows
+func (t *tray) stopWatch() {
+	t.mu.Lock()
+	c := t.watch
+	t.watch = nil
+	t.mu.Unlock()
+	if c == nil {
+		return
+	}
+
+	if runtime.GOOS == "windows" || c.Process.Signal(os.Interrupt) != nil {
+		c.Process.Kill()
+	}
+}
+
+// trayIcon draws the tray icon: a white page on a green square
+func trayIcon() []byte {
+	const size = 32
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	green := color.RGBA{0x17, 0x54, 0x1f, 0xff}
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	for y := range size {
+		for x := range size {
+			c := green
+			if x >= 9 && x < 23 && y >= 6 && y < 26 {
+				c = white
+			}
+			img.Set(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	if runtime.GOOS != "windows" {
+		return buf.Bytes()
+	}
+
+	// Windows wants an ICO file, which may hold a PNG
+	ico := []byte{0, 0, 1, 0, 1, 0, size, size, 0, 0, 1, 0, 32, 0}
+	n, offset := buf.Len(), 22
+	ico = append(ico, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+	ico = append(ico, byte(offset), 0, 0, 0)
+	return append(ico, buf.Bytes()...)
+}
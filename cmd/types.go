@@ -2,10 +2,10 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"maps"
 	"strconv"
-	"text/tabwriter"
 
+	"github.com/julianfbeck/paperless-cli/internal/api"
 	"github.com/spf13/cobra"
 )
 
@@ -41,10 +41,12 @@ Example:
 var typesCreateCmd = &cobra.Command{
 	Use:   "create <name>",
 	Short: "Create a new document type",
-	Long: `Create a new document type.
+	Long: `Create a new document type. With --match and --matching-algorithm, new
+documents matching the text are assigned to it automatically.
 
 Example:
-  paperless types create "Invoice"`,
+  paperless types create "Invoice"
+  paperless types create "Invoice" --match "invoice bill" --matching-algorithm any`,
 	Args: cobra.ExactArgs(1),
 	RunE: runTypesCreate,
 }
@@ -55,7 +57,8 @@ var typesEditCmd = &cobra.Command{
 	Long: `Edit a document type's properties.
 
 Example:
-  paperless types edit 5 --name "New Name"`,
+  paperless types edit 5 --name "New Name"
+  paperless types edit 5 --matching-algorithm auto`,
 	Args: cobra.ExactArgs(1),
 	RunE: runTypesEdit,
 }
@@ -73,8 +76,12 @@ Example:
 }
 
 var (
-	typeName  string
-	typeForce bool
+	typeName      string
+	typeForce     bool
+	typeOwnership ownershipFlags
+	typeMatching  matchingFlags
+
+	typesListName string
 )
 
 func init() {
@@ -85,17 +92,36 @@ func init() {
 	typesCmd.AddCommand(typesEditCmd)
 	typesCmd.AddCommand(typesDeleteCmd)
 
+	typesListCmd.Flags().StringVar(&typesListName, "name", "", "only document types whose name contains this text")
+	addTableFlags(typesListCmd, typeColumns)
 	typesEditCmd.Flags().StringVar(&typeName, "name", "", "new name")
+	typeMatching.register(typesCreateCmd)
+	typeMatching.register(typesEditCmd)
+	typeOwnership.register(typesCreateCmd)
+	typeOwnership.register(typesEditCmd)
 	typesDeleteCmd.Flags().BoolVarP(&typeForce, "force", "f", false, "skip confirmation")
 }
 
+// typeColumns are the columns of the document types list
+var typeColumns = []tableColumn[api.DocumentType]{
+	{name: "id", value: func(dt api.DocumentType) string { return strconv.Itoa(dt.ID) }},
+	{name: "name", value: func(dt api.DocumentType) string { return dt.Name }},
+	{name: "docs", value: func(dt api.DocumentType) string { return strconv.Itoa(dt.DocumentCount) }},
+	{name: "match", value: func(dt api.DocumentType) string { return fit(dt.Match, 30) }},
+}
+
 func runTypesList(cmd *cobra.Command, args []string) error {
 	client, err := getClient()
 	if err != nil {
 		return err
 	}
 
-	result, err := client.ListDocumentTypes()
+	columns, err := selectColumns(typeColumns, "id,name,docs")
+	if err != nil {
+		return err
+	}
+
+	result, err := client.ListDocumentTypes(api.ListParams{NameContains: typesListName})
 	if err != nil {
 		return err
 	}
@@ -109,12 +135,7 @@ func runTypesList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tNAME\tDOCS")
-	for _, dt := range result.Results {
-		fmt.Fprintf(w, "%d\t%s\t%d\n", dt.ID, dt.Name, dt.DocumentCount)
-	}
-	w.Flush()
+	printTable(columns, result.Results)
 
 	return nil
 }
@@ -142,6 +163,7 @@ func runTypesGet(cmd *cobra.Command, args []string) error {
 	fmt.Printf("ID:        %d\n", dt.ID)
 	fmt.Printf("Name:      %s\n", dt.Name)
 	fmt.Printf("Slug:      %s\n", dt.Slug)
+	fmt.Printf("Matching:  %s\n", matchingLabel(dt.MatchingAlgo, dt.Match, dt.IsInsensitive))
 	fmt.Printf("Documents: %d\n", dt.DocumentCount)
 
 	return nil
@@ -153,10 +175,24 @@ func runTypesCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	dt, err := client.CreateDocumentType(args[0])
+	fields, err := typeMatching.updates(cmd)
+	if err != nil {
+		return err
+	}
+	owner, err := typeOwnership.resolve(client)
+	if err != nil {
+		return err
+	}
+
+	dt, err := client.CreateDocumentType(args[0], fields)
 	if err != nil {
 		return err
 	}
+	if updates := owner.updates(nil, false); len(updates) > 0 {
+		if dt, err = client.UpdateDocumentType(dt.ID, updates); err != nil {
+			return err
+		}
+	}
 
 	if isJSON() {
 		return printJSON(dt)
@@ -180,11 +216,28 @@ func runTypesEdit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid document type ID: %s", args[0])
 	}
 
-	updates := make(map[string]interface{})
+	updates, err := typeMatching.updates(cmd)
+	if err != nil {
+		return err
+	}
 	if typeName != "" {
 		updates["name"] = typeName
 	}
 
+	owner, err := typeOwnership.resolve(client)
+	if err != nil {
+		return err
+	}
+	var current *api.ObjectPermissions
+	if owner.shares != nil {
+		obj, err := client.GetDocumentType(id)
+		if err != nil {
+			return err
+		}
+		current = obj.Permissions
+	}
+	maps.Copy(updates, owner.updates(current, false))
+
 	if len(updates) == 0 {
 		return fmt.Errorf("no changes specified")
 	}
@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var usersCmd = &cobra.Command{
+	Use:     "users",
+	Aliases: []string{"user"},
+	Short:   "Manage user accounts",
+	Long: `List, create, edit, and deactivate user accounts. Needs a token of a user
+with permission to manage users.`,
+}
+
+var usersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all users",
+	Long: `List all user accounts.
+
+Example:
+  paperless users list
+  paperless users list --name anna --json`,
+	Args: cobra.NoArgs,
+	RunE: runUsersList,
+}
+
+var usersGetCmd = &cobra.Command{
+	Use:   "get <id|username>",
+	Short: "Get user details",
+	Long: `Get detailed information about a user, including permissions.
+
+Example:
+  paperless users get 3
+  paperless users get anna`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUsersGet,
+}
+
+var usersCreateCmd = &cobra.Command{
+	Use:   "create <username>",
+	Short: "Create a new user",
+	Long: `Create a new user account. Pass the password with --password-stdin to keep
+it out of the shell history and process list.
+
+Example:
+  paperless users create anna --email anna@example.com
+  echo "$PASSWORD" | paperless users create anna --password-stdin --superuser`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUsersCreate,
+}
+
+var usersEditCmd = &cobra.Command{
+	Use:   "edit <id|username>",
+	Short: "Edit a user",
+	Long: `Edit a user account. Only the given flags are changed.
+
+Example:
+  paperless users edit anna --email anna@example.org
+  paperless users edit anna --active          # reactivate
+  echo "$PASSWORD" | paperless users edit anna --password-stdin`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUsersEdit,
+}
+
+var usersDeactivateCmd = &cobra.Command{
+	Use:   "deactivate <id|username>...",
+	Short: "Deactivate users",
+	Long: `Deactivate user accounts so they can no longer log in or use their tokens.
+Their documents and permissions are kept; reactivate with
+"paperless users edit <user> --active".
+
+Example:
+  paperless users deactivate anna
+  paperless users deactivate 4 5`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runUsersDeactivate,
+}
+
+var (
+	userListName      string
+	userEmail         string
+	userFirstName     string
+	userLastName      string
+	userPassword      string
+	userPasswordStdin bool
+	userSuperuser     bool
+	userStaff         bool
+	userActive        bool
+)
+
+func init() {
+	rootCmd.AddCommand(usersCmd)
+	usersCmd.AddCommand(usersListCmd)
+	usersCmd.AddCommand(usersGetCmd)
+	usersCmd.AddCommand(usersCreateCmd)
+	usersCmd.AddCommand(usersEditCmd)
+	usersCmd.AddCommand(usersDeactivateCmd)
+
+	usersListCmd.Flags().StringVar(&userListName, "name", "", "only users whose username contains this text")
+	addTableFlags(usersListCmd, userColumns)
+
+	for _, c := range []*cobra.Command{usersCreateCmd, usersEditCmd} {
+		c.Flags().StringVar(&userEmail, "email", "", "email address")
+		c.Flags().StringVar(&userFirstName, "first-name", "", "first name")
+		c.Flags().StringVar(&userLastName, "last-name", "", "last name")
+		c.Flags().StringVar(&userPassword, "password", "", "password (prefer --password-stdin)")
+		c.Flags().BoolVar(&userPasswordStdin, "password-stdin", false, "read the password from stdin")
+		c.Flags().BoolVar(&userSuperuser, "superuser", false, "grant all permissions")
+		c.Flags().BoolVar(&userStaff, "staff", false, "allow access to the Django admin")
+	}
+	usersEditCmd.Flags().BoolVar(&userActive, "active", true, "allow the user to log in")
+}
+
+// findUser resolves a user ID or username
+func findUser(client *api.Client, arg string) (*api.User, error) {
+	if id, err := strconv.Atoi(arg); err == nil {
+		return client.GetUser(id)
+	}
+	return client.FindUserByName(arg)
+}
+
+// userFields collects the user properties given on the command line
+func userFields(cmd *cobra.Command) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	for _, f := range []struct {
+		flag, key string
+		value     interface{}
+	}{
+		{"email", "email", userEmail},
+		{"first-name", "first_name", userFirstName},
+		{"last-name", "last_name", userLastName},
+		{"superuser", "is_superuser", userSuperuser},
+		{"staff", "is_staff", userStaff},
+		{"active", "is_active", userActive},
+	} {
+		if cmd.Flags().Changed(f.flag) {
+			fields[f.key] = f.value
+		}
+	}
+
+	if userPasswordStdin {
+		if userPassword != "" {
+			return nil, fmt.Errorf("--password and --password-stdin can't be combined")
+		}
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		password := strings.TrimRight(line, "\r\n")
+		if password == "" {
+			if err != nil {
+				return nil, fmt.Errorf("reading password: %w", err)
+			}
+			return nil, fmt.Errorf("empty password on stdin")
+		}
+		fields["password"] = password
+	} else if userPassword != "" {
+		fields["password"] = userPassword
+	}
+
+	return fields, nil
+}
+
+// userColumns are the columns of the users list
+var userColumns = []tableColumn[api.User]{
+	{name: "id", value: func(u api.User) string { return strconv.Itoa(u.ID) }},
+	{name: "username", value: func(u api.User) string { return u.Username }},
+	{name: "name", value: func(u api.User) string { return strings.TrimSpace(u.FirstName + " " + u.LastName) }},
+	{name: "email", value: func(u api.User) string { return u.Email }},
+	{name: "role", value: func(u api.User) string { return userRole(u) }},
+	{name: "active", value: func(u api.User) string { return yesNo(u.IsActive) }},
+	{name: "joined", value: func(u api.User) string { return u.DateJoined.Format("2006-01-02") }},
+	{name: "groups", value: func(u api.User) string { return strconv.Itoa(len(u.Groups)) }},
+}
+
+func runUsersList(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	columns, err := selectColumns(userColumns, "id,username,name,email,role,active")
+	if err != nil {
+		return err
+	}
+
+	result, err := client.ListUsers(api.ListParams{NameContains: userListName})
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(result)
+	}
+
+	if len(result.Results) == 0 {
+		fmt.Println("No users found")
+		return nil
+	}
+
+	printTable(columns, result.Results)
+
+	return nil
+}
+
+// userRole summarizes a user's admin flags
+func userRole(u api.User) string {
+	switch {
+	case u.IsSuperuser:
+		return "superuser"
+	case u.IsStaff:
+		return "staff"
+	}
+	return "user"
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func runUsersGet(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	u, err := findUser(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(u)
+	}
+
+	fmt.Printf("ID:          %d\n", u.ID)
+	fmt.Printf("Username:    %s\n", u.Username)
+	if name := strings.TrimSpace(u.FirstName + " " + u.LastName); name != "" {
+		fmt.Printf("Name:        %s\n", name)
+	}
+	if u.Email != "" {
+		fmt.Printf("Email:       %s\n", u.Email)
+	}
+	fmt.Printf("Role:        %s\n", userRole(*u))
+	fmt.Printf("Active:      %s\n", yesNo(u.IsActive))
+	if !u.DateJoined.IsZero() {
+		fmt.Printf("Joined:      %s\n", u.DateJoined.Format("2006-01-02"))
+	}
+	if len(u.Groups) > 0 {
+		fmt.Printf("Groups:      %s\n", strings.Trim(fmt.Sprint(u.Groups), "[]"))
+	}
+	if len(u.UserPermissions) > 0 {
+		fmt.Printf("Permissions: %s\n", strings.Join(u.UserPermissions, ", "))
+	}
+	if len(u.InheritedPermissions) > 0 {
+		fmt.Printf("Inherited:   %s\n", strings.Join(u.InheritedPermissions, ", "))
+	}
+
+	return nil
+}
+
+func runUsersCreate(cmd *cobra.Command, args []string) error {
+	fields, err := userFields(cmd)
+	if err != nil {
+		return err
+	}
+	password, _ := fields["password"].(string)
+	delete(fields, "password")
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	u, err := client.CreateUser(args[0], password, fields)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(u)
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Created user %d: %s\n", u.ID, u.Username)
+	}
+
+	return nil
+}
+
+func runUsersEdit(cmd *cobra.Command, args []string) error {
+	updates, err := userFields(cmd)
+	if err != nil {
+		return err
+	}
+	if len(updates) == 0 {
+		return fmt.Errorf("no changes specified")
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	u, err := findUser(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	u, err = client.UpdateUser(u.ID, updates)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(u)
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Updated user %d: %s\n", u.ID, u.Username)
+	}
+
+	return nil
+}
+
+func runUsersDeactivate(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		u, err := findUser(client, arg)
+		if err != nil {
+			return err
+		}
+		if _, err := client.DeactivateUser(u.ID); err != nil {
+			return err
+		}
+		if !isQuiet() {
+			fmt.Printf("Deactivated user %d: %s\n", u.ID, u.Username)
+		}
+	}
+
+	return nil
+}
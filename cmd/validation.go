@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// fieldFlags lists the flags that set API fields whose flag isn't simply
+// the field name with dashes
+var fieldFlags = map[string][]string{
+	"archive_serial_number": {"asn", "asn-start"},
+	"document_type":         {"type"},
+	"data_type":             {"type"},
+	"tags":                  {"tag", "add-tag"},
+	"custom_fields":         {"field", "set-field"},
+	"created_date":          {"created"},
+	"set_permissions":       {"share-with-user", "share-with-group"},
+	"is_active":             {"active"},
+	"is_staff":              {"staff"},
+	"is_superuser":          {"superuser"},
+	"permissions":           {"permission"},
+	"user_permissions":      {"permission"},
+	"extra_data":            {"option", "currency"},
+	"select_options":        {"option"},
+	"default_currency":      {"currency"},
+	"filter_rules":          {"rule"},
+	"sort_field":            {"sort"},
+	"show_on_dashboard":     {"dashboard"},
+	"show_in_sidebar":       {"sidebar"},
+	"is_insensitive":        {"insensitive"},
+}
+
+// fieldFlag names an API field by the command's flag that sets it, or by
+// the field itself if there is none
+func fieldFlag(cmd *cobra.Command, field string) string {
+	candidates := append([]string{strings.ReplaceAll(field, "_", "-")}, fieldFlags[field]...)
+	var names []string
+	for _, name := range candidates {
+		if cmd.Flags().Lookup(name) != nil {
+			names = append(names, "--"+name)
+		}
+	}
+	if len(names) == 0 {
+		return field
+	}
+	return strings.Join(names, "/")
+}
+
+// nameFlagsInErrors makes the validation errors of every command below c
+// name the offending flags instead of API fields
+func nameFlagsInErrors(c *cobra.Command) {
+	if run := c.RunE; run != nil {
+		c.RunE = func(cmd *cobra.Command, args []string) error {
+			err := run(cmd, args)
+			var apiErr *api.APIError
+			if errors.As(err, &apiErr) {
+				apiErr.FieldName = func(field string) string { return fieldFlag(cmd, field) }
+			}
+			return err
+		}
+	}
+	for _, sub := range c.Commands() {
+		nameFlagsInErrors(sub)
+	}
+}
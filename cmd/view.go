@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var docsViewCmd = &cobra.Command{
+	Use:   "view <id>...",
+	Short: "Open documents in the web UI",
+	Long: `Open the documents' pages of the web UI in the default browser. IDs can
+be given as ranges like 100-120 and comma-separated lists.
+
+On remote machines, e.g. over ssh, --print-url prints the URLs instead.
+
+Example:
+  paperless documents view 123
+  paperless documents view 123 --print-url`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDocsView,
+}
+
+var viewPrintURL bool
+
+func init() {
+	documentsCmd.AddCommand(docsViewCmd)
+
+	docsViewCmd.Flags().BoolVar(&viewPrintURL, "print-url", false, "print the URLs instead of opening them")
+}
+
+func runDocsView(cmd *cobra.Command, args []string) error {
+	ids, err := parseDocumentIDs(args)
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	urls := make([]string, len(ids))
+	for i, id := range ids {
+		urls[i] = client.DocumentURL(id)
+	}
+
+	if isJSON() {
+		out := make([]map[string]interface{}, len(ids))
+		for i, id := range ids {
+			out[i] = map[string]interface{}{"id": id, "url": urls[i]}
+		}
+		return printJSON(out)
+	}
+	if viewPrintURL {
+		for _, url := range urls {
+			fmt.Println(url)
+		}
+		return nil
+	}
+
+	for _, url := range urls {
+		if err := openBrowser(url); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"strconv"
+	"strings"
 	"text/tabwriter"
 
+	"github.com/julianfbeck/paperless-cli/internal/api"
 	"github.com/spf13/cobra"
 )
 
@@ -13,7 +16,7 @@ var viewsCmd = &cobra.Command{
 	Use:     "views",
 	Aliases: []string{"saved-views"},
 	Short:   "Manage saved views",
-	Long:    `List and view saved views.`,
+	Long:    `List, create, edit, and delete saved views.`,
 }
 
 var viewsListCmd = &cobra.Command{
@@ -38,10 +41,214 @@ Example:
 	RunE: runViewsGet,
 }
 
+var viewsCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a saved view",
+	Long: `Create a saved view from the same filters as 'documents list'. Documents
+must match all of them. Further rules of the web UI's filter editor can be
+added with --rule, by rule type name or number and value (see 'views get'
+of a view made in the web UI for examples).
+
+Example:
+  paperless views create "Open invoices" --type Invoice --tag unpaid --sidebar
+  paperless views create "ACME 2024" --correspondent ACME --created-after 2023-12-31 --created-before 2025-01-01
+  paperless views create "Inbox" --rule is_in_inbox --dashboard --sort added`,
+	Args: cobra.ExactArgs(1),
+	RunE: runViewsCreate,
+}
+
+var viewsEditCmd = &cobra.Command{
+	Use:   "edit <id|name>",
+	Short: "Edit a saved view",
+	Long: `Edit a saved view. Filter flags replace all of the view's rules.
+
+Example:
+  paperless views edit 5 --name "Unpaid invoices"
+  paperless views edit "Open invoices" --tag unpaid --tag 2024
+  paperless views edit 5 --dashboard=false --sidebar`,
+	Args: cobra.ExactArgs(1),
+	RunE: runViewsEdit,
+}
+
+var viewsDeleteCmd = &cobra.Command{
+	Use:   "delete <id|name>",
+	Short: "Delete a saved view",
+	Long: `Delete a saved view. The documents it shows are not affected.
+
+Example:
+  paperless views delete 5
+  paperless views delete "Open invoices" --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runViewsDelete,
+}
+
+var viewsRunCmd = &cobra.Command{
+	Use:   "run <id|name>",
+	Short: "List the documents of a saved view",
+	Long: `List the documents a saved view shows, in the view's sort order, like
+opening it in the web UI.
+
+Example:
+  paperless views run 5
+  paperless views run "Open invoices" --limit 100
+  paperless views run Inbox --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runViewsRun,
+}
+
+var (
+	viewRunLimit int
+	viewRunPage  int
+)
+
+var (
+	viewName      string
+	viewDashboard bool
+	viewSidebar   bool
+	viewSort      string
+	viewEditSort  string
+	viewFilters   viewFilterFlags
+	viewForce     bool
+)
+
 func init() {
 	rootCmd.AddCommand(viewsCmd)
 	viewsCmd.AddCommand(viewsListCmd)
 	viewsCmd.AddCommand(viewsGetCmd)
+	viewsCmd.AddCommand(viewsCreateCmd)
+	viewsCmd.AddCommand(viewsEditCmd)
+	viewsCmd.AddCommand(viewsDeleteCmd)
+	viewsCmd.AddCommand(viewsRunCmd)
+
+	addTableFlags(viewsListCmd, viewColumns)
+	for _, c := range []*cobra.Command{viewsCreateCmd, viewsEditCmd} {
+		viewFilters.register(c)
+		c.Flags().BoolVar(&viewDashboard, "dashboard", false, "show on the dashboard")
+		c.Flags().BoolVar(&viewSidebar, "sidebar", false, "show in the sidebar")
+	}
+	viewsCreateCmd.Flags().StringVar(&viewSort, "sort", "-created", `sort field, prefixed with "-" for descending order`)
+	viewsEditCmd.Flags().StringVar(&viewEditSort, "sort", "", `sort field, prefixed with "-" for descending order`)
+	viewsEditCmd.Flags().StringVar(&viewName, "name", "", "new name")
+	viewsDeleteCmd.Flags().BoolVarP(&viewForce, "force", "f", false, "skip confirmation")
+	viewsRunCmd.Flags().IntVar(&viewRunLimit, "limit", 25, "max results")
+	viewsRunCmd.Flags().IntVar(&viewRunPage, "page", 1, "page number")
+	addFetchFlags(viewsRunCmd)
+	addTableFlags(viewsRunCmd, documentColumns(nil))
+}
+
+// viewFilterFlags are the 'documents list' filters that define a saved
+// view, plus raw filter rules
+type viewFilterFlags struct {
+	query         string
+	tags          []string
+	correspondent string
+	docType       string
+	createdAfter  string
+	createdBefore string
+	rules         []string
+}
+
+func (f *viewFilterFlags) register(c *cobra.Command) {
+	c.Flags().StringVar(&f.query, "query", "", "search query")
+	c.RegisterFlagCompletionFunc("query", completeQuery)
+	c.Flags().StringArrayVar(&f.tags, "tag", nil, "filter by tag (repeatable)")
+	c.Flags().StringVar(&f.correspondent, "correspondent", "", "filter by correspondent")
+	c.Flags().StringVar(&f.docType, "type", "", "filter by document type")
+	c.Flags().StringVar(&f.createdAfter, "created-after", "", "filter by creation date (YYYY-MM-DD, 30d, last month, 2024-Q1, ...)")
+	c.Flags().StringVar(&f.createdBefore, "created-before", "", "filter by creation date (YYYY-MM-DD, 30d, last month, 2024-Q1, ...)")
+	c.Flags().StringArrayVar(&f.rules, "rule", nil, "filter rule: <type>[=<value>], type by name or number (repeatable)")
+}
+
+func (f *viewFilterFlags) isSet() bool {
+	return f.query != "" || len(f.tags) > 0 || f.correspondent != "" || f.docType != "" ||
+		f.createdAfter != "" || f.createdBefore != "" || len(f.rules) > 0
+}
+
+// resolve turns the filters into rules, looking up the names they give
+func (f *viewFilterFlags) resolve(client *api.Client) ([]api.FilterRule, error) {
+	// Relative dates are fixed when the view is saved
+	createdAfter, createdBefore := f.createdAfter, f.createdBefore
+	if err := resolveDateFilters(&createdAfter, &createdBefore); err != nil {
+		return nil, err
+	}
+
+	ids, err := resolveUploadParams(client, f.correspondent, f.docType, "", f.tags)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []api.FilterRule{}
+	if f.query != "" {
+		rules = append(rules, api.NewFilterRule(api.FilterFulltextQuery, f.query))
+	}
+	for _, id := range ids.Tags {
+		rules = append(rules, api.NewFilterRule(api.FilterHasTag, strconv.Itoa(id)))
+	}
+	if ids.Correspondent != nil {
+		rules = append(rules, api.NewFilterRule(api.FilterCorrespondentIs, strconv.Itoa(*ids.Correspondent)))
+	}
+	if ids.DocumentType != nil {
+		rules = append(rules, api.NewFilterRule(api.FilterDocumentTypeIs, strconv.Itoa(*ids.DocumentType)))
+	}
+	if createdAfter != "" {
+		rules = append(rules, api.NewFilterRule(api.FilterCreatedAfter, createdAfter))
+	}
+	if createdBefore != "" {
+		rules = append(rules, api.NewFilterRule(api.FilterCreatedBefore, createdBefore))
+	}
+
+	for _, arg := range f.rules {
+		name, value, hasValue := strings.Cut(arg, "=")
+		ruleType, ok := api.ParseFilterRuleType(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown filter rule type: %s", name)
+		}
+		rule := api.FilterRule{RuleType: ruleType}
+		if hasValue {
+			rule.Value = &value
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// viewSortFields returns the sort_field and sort_reverse of a view from a
+// sort flag like "-created"
+func viewSortFields(sort string) map[string]interface{} {
+	field, reverse := strings.CutPrefix(sort, "-")
+	return map[string]interface{}{"sort_field": field, "sort_reverse": reverse}
+}
+
+// findSavedView resolves a saved view ID or name
+func findSavedView(client *api.Client, arg string) (*api.SavedView, error) {
+	if id, err := strconv.Atoi(arg); err == nil {
+		return client.GetSavedView(id)
+	}
+	return client.FindSavedViewByName(arg)
+}
+
+// viewColumns are the columns of the saved views list
+var viewColumns = []tableColumn[api.SavedView]{
+	{name: "id", value: func(sv api.SavedView) string { return strconv.Itoa(sv.ID) }},
+	{name: "name", value: func(sv api.SavedView) string { return sv.Name }},
+	{name: "dashboard", value: func(sv api.SavedView) string { return yesOrEmpty(sv.ShowOnDashboard) }},
+	{name: "sidebar", value: func(sv api.SavedView) string { return yesOrEmpty(sv.ShowInSidebar) }},
+	{name: "sort", value: func(sv api.SavedView) string {
+		if sv.SortReverse && sv.SortField != "" {
+			return "-" + sv.SortField
+		}
+		return sv.SortField
+	}},
+	{name: "rules", value: func(sv api.SavedView) string { return strconv.Itoa(len(sv.FilterRules)) }},
+}
+
+// yesOrEmpty shows flags that are mostly off, leaving the column quiet
+func yesOrEmpty(b bool) string {
+	if b {
+		return "yes"
+	}
+	return ""
 }
 
 func runViewsList(cmd *cobra.Command, args []string) error {
@@ -50,7 +257,12 @@ func runViewsList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	result, err := client.ListSavedViews()
+	columns, err := selectColumns(viewColumns, "id,name,dashboard,sidebar")
+	if err != nil {
+		return err
+	}
+
+	result, err := client.ListSavedViews(api.ListParams{})
 	if err != nil {
 		return err
 	}
@@ -64,20 +276,7 @@ func runViewsList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tNAME\tDASHBOARD\tSIDEBAR")
-	for _, sv := range result.Results {
-		dashboard := ""
-		sidebar := ""
-		if sv.ShowOnDashboard {
-			dashboard = "yes"
-		}
-		if sv.ShowInSidebar {
-			sidebar = "yes"
-		}
-		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", sv.ID, sv.Name, dashboard, sidebar)
-	}
-	w.Flush()
+	printTable(columns, result.Results)
 
 	return nil
 }
@@ -107,6 +306,172 @@ func runViewsGet(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Dashboard: %t\n", sv.ShowOnDashboard)
 	fmt.Printf("Sidebar:   %t\n", sv.ShowInSidebar)
 	fmt.Printf("Sort:      %s (reverse: %t)\n", sv.SortField, sv.SortReverse)
+	if len(sv.FilterRules) > 0 {
+		fmt.Println("Rules:")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, rule := range sv.FilterRules {
+			value := ""
+			if rule.Value != nil {
+				value = *rule.Value
+			}
+			fmt.Fprintf(w, "  %s\t%s\n", api.FilterRuleTypeName(rule.RuleType), value)
+		}
+		w.Flush()
+	}
 
 	return nil
 }
+
+func runViewsCreate(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	rules, err := viewFilters.resolve(client)
+	if err != nil {
+		return err
+	}
+
+	fields := viewSortFields(viewSort)
+	fields["show_on_dashboard"] = viewDashboard
+	fields["show_in_sidebar"] = viewSidebar
+
+	sv, err := client.CreateSavedView(args[0], rules, fields)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(sv)
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Created saved view %d: %s (%d rule(s))\n", sv.ID, sv.Name, len(sv.FilterRules))
+	}
+
+	return nil
+}
+
+func runViewsEdit(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	sv, err := findSavedView(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	updates := make(map[string]interface{})
+	if viewName != "" {
+		updates["name"] = viewName
+	}
+	if cmd.Flags().Changed("dashboard") {
+		updates["show_on_dashboard"] = viewDashboard
+	}
+	if cmd.Flags().Changed("sidebar") {
+		updates["show_in_sidebar"] = viewSidebar
+	}
+	if viewEditSort != "" {
+		maps.Copy(updates, viewSortFields(viewEditSort))
+	}
+	if viewFilters.isSet() {
+		rules, err := viewFilters.resolve(client)
+		if err != nil {
+			return err
+		}
+		updates["filter_rules"] = rules
+	}
+
+	if len(updates) == 0 {
+		return fmt.Errorf("no changes specified")
+	}
+
+	sv, err = client.UpdateSavedView(sv.ID, updates)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(sv)
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Updated saved view %d: %s\n", sv.ID, sv.Name)
+	}
+
+	return nil
+}
+
+func runViewsDelete(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	sv, err := findSavedView(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	if !viewForce {
+		if !confirmAction(fmt.Sprintf("Delete saved view %d (%s)?", sv.ID, sv.Name)) {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	if err := client.DeleteSavedView(sv.ID); err != nil {
+		return err
+	}
+
+	if !isQuiet() {
+		fmt.Printf("Deleted saved view %d\n", sv.ID)
+	}
+
+	return nil
+}
+
+func runViewsRun(cmd *cobra.Command, args []string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	sv, err := findSavedView(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	filter, err := api.FilterRuleParams(sv.FilterRules)
+	if err != nil {
+		return fmt.Errorf("saved view %s: %w", sv.Name, err)
+	}
+
+	columns, err := selectDocumentColumns(newMetadataNames(client), defaultDocumentColumns)
+	if err != nil {
+		return err
+	}
+
+	ordering := "-created"
+	if sv.SortField != "" {
+		ordering = sv.SortField
+		if sv.SortReverse {
+			ordering = "-" + ordering
+		}
+	}
+
+	result, err := fetchDocuments(client, api.DocumentListParams{
+		Filter:   filter,
+		Limit:    viewRunLimit,
+		Page:     viewRunPage,
+		Ordering: ordering,
+	})
+	if err != nil {
+		return err
+	}
+
+	return printDocumentList(result, columns)
+}
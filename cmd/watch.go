@@ -0,0 +1,384 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/julianfbeck/paperless-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch <directory>",
+	Short: "Upload new files from a directory",
+	Long: `Watch a directory and its subdirectories and upload every new or changed
+file once its size and modification time have been unchanged for --settle,
+so partially written scans aren't picked up. Hidden files and directories
+are ignored. Files already present when watching starts are skipped unless
+--existing is given. --on-success and --on-failure delete files or move
+them to another directory once they've been uploaded, so the directory
+only holds what's still to do. Move targets inside the watched directory
+aren't watched.
+
+On Linux the directory is watched with inotify. Changes made by other
+machines on SMB/CIFS or NFS shares don't cause events, so such directories,
+and all directories on other platforms, are rescanned periodically instead.
+--poll forces periodic rescans at the given interval.
+
+The flags set metadata for all uploads. The watch_rules in the config file
+add metadata for files matching a pattern, relative to the watched
+directory. The first matching rule is used; its tags are added to the
+flag's tags and its other fields replace them. A pattern ending in "/"
+matches everything below that directory, other patterns are globs where
+"*" doesn't cross "/" and "**" does. Globs without "/" match file names.
+
+  watch_rules:
+    - match: inbox/taxes/
+      tags: [taxes]
+      storage_path: Taxes/{year}
+    - match: "*invoice*.pdf"
+      document_type: Invoice
+
+Example:
+  paperless watch ~/scans
+  paperless watch ~/scans --tag inbox --existing
+  paperless watch /mnt/scanner --poll 30s --settle 10s
+  paperless watch ~/scans --on-success delete --on-failure move:/srv/scans-failed`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWatch,
+}
+
+var (
+	watchPoll          time.Duration
+	watchSettle        time.Duration
+	watchExisting      bool
+	watchTags          []string
+	watchCorrespondent string
+	watchDocType       string
+	watchStoragePath   string
+	watchOnSuccess     string
+	watchOnFailure     string
+)
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().DurationVar(&watchPoll, "poll", 0, "rescan the directory at this interval instead of using file system events")
+	watchCmd.Flags().DurationVar(&watchSettle, "settle", 5*time.Second, "how long a file must stay unchanged before it's uploaded")
+	watchCmd.Flags().BoolVar(&watchExisting, "existing", false, "also upload files present when watching starts")
+	watchCmd.Flags().StringArrayVar(&watchTags, "tag", nil, "tag to add to every upload (repeatable)")
+	watchCmd.Flags().StringVar(&watchCorrespondent, "correspondent", "", "correspondent for every upload")
+	watchCmd.Flags().StringVar(&watchDocType, "type", "", "document type for every upload")
+	watchCmd.Flags().StringVar(&watchStoragePath, "storage-path", "", "storage path for every upload")
+	watchCmd.Flags().StringVar(&watchOnSuccess, "on-success", "keep", "what to do with uploaded files: keep, delete or move:DIR")
+	watchCmd.Flags().StringVar(&watchOnFailure, "on-failure", "keep", "what to do with files that failed to upload: keep or move:DIR")
+}
+
+// watchRule is a configured rule with its metadata resolved to IDs
+type watchRule struct {
+	match  string
+	re     *regexp.Regexp
+	params api.UploadParams
+}
+
+// matches reports whether rel, a slash separated path relative to the
+// watched directory, is covered by the rule
+func (r watchRule) matches(rel string) bool {
+	if strings.HasSuffix(r.match, "/") {
+		return strings.HasPrefix(rel, r.match)
+	}
+	if !strings.Contains(r.match, "/") {
+		rel = filepath.Base(rel)
+	}
+	return r.re.MatchString(rel)
+}
+
+// globRegexp compiles a glob where "*" matches within one path element,
+// "**" across elements and "?" a single character
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// loadWatchRules resolves the configured watch rules
+func loadWatchRules(client *api.Client) ([]watchRule, error) {
+	rules, err := config.GetWatchRules()
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]watchRule, 0, len(rules))
+	for _, rule := range rules {
+		match := filepath.ToSlash(strings.TrimPrefix(rule.Match, "./"))
+		re, err := globRegexp(match)
+		if err != nil {
+			return nil, fmt.Errorf("watch rule %q: %w", rule.Match, err)
+		}
+		params, err := resolveUploadParams(client, rule.Correspondent, rule.DocumentType, rule.StoragePath, rule.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("watch rule %q: %w", rule.Match, err)
+		}
+		resolved = append(resolved, watchRule{match: match, re: re, params: params})
+	}
+	return resolved, nil
+}
+
+// paramsFor returns the upload metadata for rel, the first matching rule
+// merged over the defaults
+func paramsFor(rules []watchRule, defaults api.UploadParams, rel string) (api.UploadParams, string) {
+	for _, rule := range rules {
+		if !rule.matches(rel) {
+			continue
+		}
+		params := defaults
+		if rule.params.Correspondent != nil {
+			params.Correspondent = rule.params.Correspondent
+		}
+		if rule.params.DocumentType != nil {
+			params.DocumentType = rule.params.DocumentType
+		}
+		if rule.params.StoragePath != nil {
+			params.StoragePath = rule.params.StoragePath
+		}
+		params.Tags = append(append([]int(nil), defaults.Tags...), rule.params.Tags...)
+		return params, rule.match
+	}
+	return defaults, ""
+}
+
+// defaultPollInterval is used when file system events aren't available
+const defaultPollInterval = 10 * time.Second
+
+// watchedFile is what a scan last saw of a file
+type watchedFile struct {
+	size    int64
+	modTime time.Time
+	// since is when the file was first seen with this size and time
+	since    time.Time
+	uploaded bool
+}
+
+// scanDir lists the regular files and directories below dir, skipping
+// hidden entries and the directories in skip, given as absolute paths
+func scanDir(dir string, skip []string) (map[string]fs.FileInfo, []string, error) {
+	files := make(map[string]fs.FileInfo)
+	var dirs []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Files may vanish between listing and stat
+			if path != dir && os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if abs, err := filepath.Abs(path); err == nil && path != dir && slices.Contains(skip, abs) {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, path)
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files[path] = info
+		return nil
+	})
+	return files, dirs, err
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	dir := args[0]
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("not a directory: %s", dir)
+	}
+	if cmd.Flags().Changed("poll") && watchPoll <= 0 {
+		return fmt.Errorf("--poll must be positive")
+	}
+	if watchSettle < 0 {
+		return fmt.Errorf("--settle can't be negative")
+	}
+	if err := setSourcePolicies(watchOnSuccess, watchOnFailure); err != nil {
+		return err
+	}
+	var skip []string
+	for _, policy := range []sourcePolicy{uploadSourcePolicies.success, uploadSourcePolicies.failure} {
+		if policy.action != "move" {
+			continue
+		}
+		if abs, err := filepath.Abs(policy.dir); err == nil {
+			skip = append(skip, abs)
+		}
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	defaults, err := resolveUploadParams(client, watchCorrespondent, watchDocType, watchStoragePath, watchTags)
+	if err != nil {
+		return err
+	}
+	rules, err := loadWatchRules(client)
+	if err != nil {
+		return err
+	}
+	uploadImplications, _, err = loadTagImplications(client)
+	if err != nil {
+		return err
+	}
+
+	// Use file system events unless polling is asked for or needed
+	var events *dirEvents
+	poll := watchPoll
+	mode := fmt.Sprintf("polling every %s", poll)
+	if poll == 0 {
+		if isNetworkFS(dir) {
+			poll = defaultPollInterval
+			mode = fmt.Sprintf("network share, polling every %s", poll)
+		} else if events, err = newDirEvents(); err != nil {
+			poll = defaultPollInterval
+			mode = fmt.Sprintf("%v, polling every %s", err, poll)
+		} else {
+			defer events.close()
+			mode = "file system events"
+		}
+	}
+
+	seen := make(map[string]*watchedFile)
+	scan := func() (pending bool, err error) {
+		files, dirs, err := scanDir(dir, skip)
+		if err != nil {
+			return false, err
+		}
+		if events != nil {
+			for _, d := range dirs {
+				if err := events.add(d); err != nil {
+					return false, fmt.Errorf("watching %s: %w", d, err)
+				}
+			}
+		}
+
+		for path := range seen {
+			if _, ok := files[path]; !ok {
+				delete(seen, path)
+			}
+		}
+
+		now := time.Now()
+		for path, info := range files {
+			state, ok := seen[path]
+			if !ok || state.size != info.Size() || !state.modTime.Equal(info.ModTime()) {
+				// New or still being written
+				seen[path] = &watchedFile{size: info.Size(), modTime: info.ModTime(), since: now}
+				pending = true
+				continue
+			}
+			if state.uploaded {
+				continue
+			}
+			if now.Sub(state.since) < watchSettle {
+				pending = true
+				continue
+			}
+			// Whatever the outcome, don't retry until the file changes
+			state.uploaded = true
+
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				rel = path
+			}
+			params, rule := paramsFor(rules, defaults, filepath.ToSlash(rel))
+			if rule != "" && !isQuiet() {
+				fmt.Fprintf(os.Stderr, "%s matches rule %s\n", rel, rule)
+			}
+			if _, err := uploadFile(client, path, params); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+		}
+		return pending, nil
+	}
+
+	pending, err := scan()
+	if err != nil {
+		return err
+	}
+	if !watchExisting {
+		for _, state := range seen {
+			state.uploaded = true
+		}
+		pending = false
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "Watching %s (%s, %d rule(s)), press Ctrl-C to stop\n", dir, mode, len(rules))
+	}
+
+	for {
+		var wake <-chan time.Time
+		var changed chan struct{}
+		if events != nil {
+			changed = events.C
+			if pending {
+				// Check again once files have had time to settle
+				wake = time.After(max(watchSettle, 100*time.Millisecond))
+			}
+		} else {
+			wake = time.After(poll)
+		}
+
+		select {
+		case <-ctx.Done():
+			if !isQuiet() {
+				fmt.Fprintln(os.Stderr, "Stopped watching")
+			}
+			return nil
+		case <-changed:
+		case <-wake:
+		}
+
+		if pending, err = scan(); err != nil {
+			return err
+		}
+	}
+}
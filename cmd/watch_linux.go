@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"path/filepath"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// dirEvents reports changes in a set of directories using inotify
+type dirEvents struct {
+	fd int
+	// C receives a value when something changed; bursts are coalesced
+	C chan struct{}
+
+	mu      sync.Mutex
+	watches map[string]int
+	paths   map[int]string
+}
+
+func newDirEvents() (*dirEvents, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &dirEvents{fd: fd, C: make(chan struct{}, 1), watches: make(map[string]int), paths: make(map[int]string)}
+	go e.read()
+	return e, nil
+}
+
+// add starts watching dir unless it's watched already
+func (e *dirEvents) add(dir string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.watches[dir]; ok {
+		return nil
+	}
+	const mask = syscall.IN_CREATE | syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY | syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM | syscall.IN_DELETE
+	wd, err := syscall.InotifyAddWatch(e.fd, dir, mask)
+	if err != nil {
+		return err
+	}
+	e.watches[dir] = wd
+	e.paths[wd] = dir
+	return nil
+}
+
+func (e *dirEvents) close() {
+	syscall.Close(e.fd)
+}
+
+func (e *dirEvents) read() {
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := syscall.Read(e.fd, buf)
+		if err != nil || n <= 0 {
+			if err == syscall.EINTR {
+				continue
+			}
+			return
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			if event.Mask&syscall.IN_IGNORED != 0 {
+				// The directory is gone; watch it again if it comes back
+				e.mu.Lock()
+				delete(e.watches, e.paths[int(event.Wd)])
+				delete(e.paths, int(event.Wd))
+				e.mu.Unlock()
+			}
+			offset += syscall.SizeofInotifyEvent + int(event.Len)
+		}
+
+		select {
+		case e.C <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// File system magic numbers from statfs(2)
+const (
+	nfsSuperMagic  = 0x6969
+	smbSuperMagic  = 0x517b
+	cifsSuperMagic = 0xff534d42
+	smb2SuperMagic = 0xfe534d42
+)
+
+// isNetworkFS reports whether dir is on an NFS or SMB share, where changes
+// made by other machines don't cause inotify events
+func isNetworkFS(dir string) bool {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(filepath.Clean(dir), &st); err != nil {
+		return false
+	}
+	switch uint32(st.Type) {
+	case nfsSuperMagic, smbSuperMagic, cifsSuperMagic, smb2SuperMagic:
+		return true
+	}
+	return false
+}
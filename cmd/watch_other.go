@@ -0,0 +1,20 @@
+//go:build !linux
+
+package cmd
+
+import "errors"
+
+// dirEvents is only implemented on Linux; elsewhere watch polls
+type dirEvents struct {
+	C chan struct{}
+}
+
+func newDirEvents() (*dirEvents, error) {
+	return nil, errors.New("file system events are not supported on this platform")
+}
+
+func (e *dirEvents) add(dir string) error { return nil }
+
+func (e *dirEvents) close() {}
+
+func isNetworkFS(dir string) bool { return false }
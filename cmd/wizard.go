@@ -0,0 +1,426 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/julianfbeck/paperless-cli/internal/api"
+	"github.com/ledongthuc/pdf"
+)
+
+// previewLines is the number of text lines shown for each file
+const previewLines = 12
+
+// errWizardQuit stops the upload wizard without an error
+var errWizardQuit = errors.New("quit")
+
+// namedObject is a correspondent, document type or tag offered for selection
+type namedObject struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// uploadWizard prompts for the metadata of each file before it's uploaded
+type uploadWizard struct {
+	client *api.Client
+	in     *bufio.Reader
+
+	correspondents []namedObject
+	types          []namedObject
+	tags           []namedObject
+}
+
+// runUploadWizard previews each file and asks for its metadata, using
+// defaults for the initial answers. A title template suggests each file's
+// title.
+func runUploadWizard(client *api.Client, files []string, defaults api.UploadParams, titleTemplate string) error {
+	if !isTerminal(os.Stdin) {
+		return fmt.Errorf("--interactive needs a terminal")
+	}
+
+	w := &uploadWizard{client: client, in: bufio.NewReader(os.Stdin)}
+	if err := w.loadChoices(); err != nil {
+		return err
+	}
+
+	uploaded := 0
+	for i, filePath := range files {
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return fmt.Errorf("file not found: %s", filePath)
+		}
+
+		fmt.Fprintf(os.Stderr, "\n[%d/%d] %s (%s)\n", i+1, len(files), filePath, formatBytes(info.Size()))
+		w.preview(filePath)
+
+		fileDefaults := defaults
+		if titleTemplate != "" {
+			fileDefaults.Title = expandTitle(titleTemplate, filePath, i+1)
+		}
+		params, upload, err := w.ask(filePath, fileDefaults)
+		if errors.Is(err, errWizardQuit) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if !upload {
+			continue
+		}
+
+		taskID, err := uploadFile(client, filePath, params)
+		if err != nil && taskID == "" {
+			return err
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		uploaded++
+	}
+
+	if !isQuiet() && !isJSON() {
+		fmt.Fprintf(os.Stderr, "\nUploaded %d of %d file(s)\n", uploaded, len(files))
+	}
+
+	return nil
+}
+
+// loadChoices fetches the correspondents, types and tags to match against
+func (w *uploadWizard) loadChoices() error {
+	corrs, err := w.client.ListCorrespondents(api.ListParams{})
+	if err != nil {
+		return err
+	}
+	for _, c := range corrs.Results {
+		w.correspondents = append(w.correspondents, namedObject{c.ID, c.Name})
+	}
+
+	types, err := w.client.ListDocumentTypes(api.ListParams{})
+	if err != nil {
+		return err
+	}
+	for _, t := range types.Results {
+		w.types = append(w.types, namedObject{t.ID, t.Name})
+	}
+
+	tags, err := w.client.ListTags(api.ListParams{})
+	if err != nil {
+		return err
+	}
+	for _, t := range tags.Results {
+		w.tags = append(w.tags, namedObject{t.ID, t.Name})
+	}
+
+	return nil
+}
+
+// ask prompts for the metadata of one file. It reports false if the file
+// should be skipped.
+func (w *uploadWizard) ask(filePath string, params api.UploadParams) (api.UploadParams, bool, error) {
+	if params.Title == "" {
+		params.Title = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	}
+
+	title, err := w.prompt("Title", params.Title)
+	if err != nil {
+		return params, false, err
+	}
+	params.Title = title
+
+	if params.Correspondent, err = w.askObject("Correspondent", "correspondent", w.correspondents, params.Correspondent); err != nil {
+		return params, false, err
+	}
+	if params.DocumentType, err = w.askObject("Type", "document type", w.types, params.DocumentType); err != nil {
+		return params, false, err
+	}
+	if params.Tags, err = w.askTags(params.Tags); err != nil {
+		return params, false, err
+	}
+
+	for {
+		answer, err := w.prompt("Upload? (y)es, (n)o/skip, (q)uit", "y")
+		if err != nil {
+			return params, false, err
+		}
+		switch strings.ToLower(answer) {
+		case "y", "yes":
+			return params, true, nil
+		case "n", "no", "s", "skip":
+			return params, false, nil
+		case "q", "quit":
+			return params, false, errWizardQuit
+		}
+	}
+}
+
+// askObject prompts for a single correspondent or type. "-" clears the
+// answer and "?" lists all choices.
+func (w *uploadWizard) askObject(label, kind string, objs []namedObject, current *int) (*int, error) {
+	for {
+		answer, err := w.prompt(label, objectName(objs, current))
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" || answer == "-" {
+			return nil, nil
+		}
+
+		obj, err := w.choose(kind, objs, answer)
+		if err != nil {
+			return nil, err
+		}
+		if obj != nil {
+			return &obj.ID, nil
+		}
+	}
+}
+
+// askTags prompts for a comma separated list of tags
+func (w *uploadWizard) askTags(current []int) ([]int, error) {
+	names := make([]string, len(current))
+	for i, id := range current {
+		names[i] = objectName(w.tags, &id)
+	}
+
+prompt:
+	for {
+		answer, err := w.prompt("Tags (comma separated)", strings.Join(names, ", "))
+		if err != nil {
+			return nil, err
+		}
+		if answer == "-" {
+			return nil, nil
+		}
+
+		var ids []int
+		for _, part := range strings.Split(answer, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			tag, err := w.choose("tag", w.tags, part)
+			if err != nil {
+				return nil, err
+			}
+			if tag == nil {
+				continue prompt
+			}
+			ids = append(ids, tag.ID)
+		}
+		return ids, nil
+	}
+}
+
+// choose resolves an answer to one object, asking to pick one if several
+// match. It returns nil if the answer should be asked for again.
+func (w *uploadWizard) choose(kind string, objs []namedObject, answer string) (*namedObject, error) {
+	if answer == "?" {
+		for _, o := range objs {
+			fmt.Fprintf(os.Stderr, "  %s\n", o.Name)
+		}
+		return nil, nil
+	}
+
+	matches := fuzzyFind(objs, answer)
+	switch len(matches) {
+	case 0:
+		fmt.Fprintf(os.Stderr, "  No %s matches %q (? lists all, - for none)\n", kind, answer)
+		return nil, nil
+	case 1:
+		if !strings.EqualFold(matches[0].Name, answer) {
+			fmt.Fprintf(os.Stderr, "  -> %s\n", matches[0].Name)
+		}
+		return &matches[0], nil
+	}
+
+	if len(matches) > 9 {
+		matches = matches[:9]
+	}
+	for i, m := range matches {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, m.Name)
+	}
+	pick, err := w.prompt(fmt.Sprintf("Which %s? (1-%d)", kind, len(matches)), "")
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(pick)
+	if err != nil || n < 1 || n > len(matches) {
+		return nil, nil
+	}
+	return &matches[n-1], nil
+}
+
+// prompt asks a question on stderr and returns the trimmed answer, or def
+// if the answer is empty
+func (w *uploadWizard) prompt(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(os.Stderr, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s: ", question)
+	}
+
+	line, err := w.in.ReadString('\n')
+	if err == io.EOF && line == "" {
+		fmt.Fprintln(os.Stderr)
+		return "", errWizardQuit
+	}
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// preview prints the first lines of a file's text. Scanned PDFs without a
+// text layer get their first page rendered to an image if pdftoppm is
+// installed.
+func (w *uploadWizard) preview(filePath string) {
+	var text string
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".pdf":
+		text = pdfFirstPageText(filePath)
+		if strings.TrimSpace(text) == "" {
+			if thumb := renderFirstPage(filePath); thumb != "" {
+				fmt.Fprintf(os.Stderr, "  (no text layer, first page rendered to %s)\n", thumb)
+			} else {
+				fmt.Fprintln(os.Stderr, "  (no text layer)")
+			}
+			return
+		}
+	case ".txt", ".md", ".csv", ".eml":
+		f, err := os.Open(filePath)
+		if err != nil {
+			return
+		}
+		data, _ := io.ReadAll(io.LimitReader(f, 8192))
+		f.Close()
+		text = string(data)
+	default:
+		fmt.Fprintln(os.Stderr, "  (no preview)")
+		return
+	}
+
+	shown := 0
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "  | %s\n", truncate(line, 76))
+		if shown++; shown == previewLines {
+			break
+		}
+	}
+}
+
+// pdfFirstPageText returns the text of a PDF's first page, or "" if it
+// can't be read
+func pdfFirstPageText(filePath string) string {
+	f, r, err := pdf.Open(filePath)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	if r.NumPage() == 0 {
+		return ""
+	}
+	page := r.Page(1)
+	if page.V.IsNull() {
+		return ""
+	}
+	text, err := page.GetPlainText(nil)
+	if err != nil {
+		return ""
+	}
+	return text
+}
+
+// renderFirstPage renders a PDF's first page to a PNG in the temp
+// directory using pdftoppm, returning its path or "" if that isn't possible
+func renderFirstPage(filePath string) string {
+	if _, err := exec.LookPath("pdftoppm"); err != nil {
+		return ""
+	}
+
+	base := filepath.Join(os.TempDir(), "paperless-preview-"+strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath)))
+	cmd := exec.Command("pdftoppm", "-png", "-singlefile", "-f", "1", "-l", "1", "-scale-to", "800", filePath, base)
+	if err := cmd.Run(); err != nil {
+		return ""
+	}
+	return base + ".png"
+}
+
+// objectName returns the name of the object with the given ID, or "" if id
+// is nil
+func objectName(objs []namedObject, id *int) string {
+	if id == nil {
+		return ""
+	}
+	for _, o := range objs {
+		if o.ID == *id {
+			return o.Name
+		}
+	}
+	return strconv.Itoa(*id)
+}
+
+// fuzzyFind returns the objects best matching input: the one with that ID
+// or exact name, else those whose name starts with it, else those containing
+// it, else those containing its characters in order
+func fuzzyFind(objs []namedObject, input string) []namedObject {
+	if id, err := strconv.Atoi(input); err == nil {
+		for _, o := range objs {
+			if o.ID == id {
+				return []namedObject{o}
+			}
+		}
+	}
+
+	in := strings.ToLower(input)
+	var prefix, contains, scattered []namedObject
+	for _, o := range objs {
+		name := strings.ToLower(o.Name)
+		switch {
+		case name == in:
+			return []namedObject{o}
+		case strings.HasPrefix(name, in):
+			prefix = append(prefix, o)
+		case strings.Contains(name, in):
+			contains = append(contains, o)
+		case isSubsequence(in, name):
+			scattered = append(scattered, o)
+		}
+	}
+
+	for _, matches := range [][]namedObject{prefix, contains, scattered} {
+		if len(matches) > 0 {
+			return matches
+		}
+	}
+	return nil
+}
+
+// isSubsequence reports whether the runes of sub appear in s in order
+func isSubsequence(sub, s string) bool {
+	rest := []rune(sub)
+	for _, r := range s {
+		if len(rest) == 0 {
+			break
+		}
+		if r == rest[0] {
+			rest = rest[1:]
+		}
+	}
+	return len(rest) == 0
+}
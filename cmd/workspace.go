@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julianfbeck/paperless-cli/internal/config"
+)
+
+var (
+	workspace       *config.Workspace
+	workspaceErr    error
+	workspaceLoaded bool
+)
+
+// currentWorkspace returns the workspace settings of the working
+// directory, or nil outside a workspace. The file is read once.
+func currentWorkspace() (*config.Workspace, error) {
+	if !workspaceLoaded {
+		workspaceLoaded = true
+		workspace, workspaceErr = config.FindWorkspace(".")
+	}
+	return workspace, workspaceErr
+}
+
+// expandTitle fills in a title template for the nth uploaded file: {name}
+// is the file name without extension, {n} the position and {date} today's
+// date
+func expandTitle(template, filePath string, n int) string {
+	name := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	return strings.NewReplacer(
+		"{name}", name,
+		"{n}", strconv.Itoa(n),
+		"{date}", time.Now().Format("2006-01-02"),
+	).Replace(template)
+}
@@ -3,8 +3,12 @@
 package api
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"os"
 	"testing"
+	"time"
 )
 
 // These tests require PAPERLESS_URL and PAPERLESS_TOKEN environment variables
@@ -55,6 +59,26 @@ func TestSearchDocuments(t *testing.T) {
 	t.Logf("Search for 'test' returned %d results", result.Count)
 }
 
+func TestListDocumentsFields(t *testing.T) {
+	client := getTestClient(t)
+
+	result, err := client.ListDocuments(DocumentListParams{
+		Limit:           5,
+		TruncateContent: true,
+		Fields:          []string{"id", "title"},
+	})
+	if err != nil {
+		t.Fatalf("ListDocuments failed: %v", err)
+	}
+
+	for _, doc := range result.Results {
+		if doc.ID == 0 {
+			t.Errorf("expected document IDs, got %+v", doc)
+		}
+	}
+	t.Logf("Listed %d documents with id and title only", len(result.Results))
+}
+
 func TestGetDocument(t *testing.T) {
 	client := getTestClient(t)
 
@@ -80,6 +104,85 @@ func TestGetDocument(t *testing.T) {
 	t.Logf("  Content length: %d chars", len(doc.Content))
 }
 
+func TestGetDocumentMetadata(t *testing.T) {
+	client := getTestClient(t)
+
+	result, err := client.ListDocuments(DocumentListParams{Limit: 1})
+	if err != nil {
+		t.Fatalf("ListDocuments failed: %v", err)
+	}
+
+	if len(result.Results) == 0 {
+		t.Skip("No documents available for testing")
+	}
+
+	docID := result.Results[0].ID
+	meta, err := client.GetDocumentMetadata(docID)
+	if err != nil {
+		t.Fatalf("GetDocumentMetadata failed: %v", err)
+	}
+
+	if meta.OriginalChecksum == "" {
+		t.Error("Expected an original checksum")
+	}
+	if meta.OriginalMimeType == "" {
+		t.Error("Expected an original MIME type")
+	}
+
+	t.Logf("Document %d: %s, %d bytes, checksum %s", docID, meta.OriginalMimeType, meta.OriginalSize, meta.OriginalChecksum)
+	t.Logf("  Archive version: %v, %d embedded metadata entries", meta.HasArchiveVersion, len(meta.ArchiveMetadata))
+}
+
+func TestGetDocumentSuggestions(t *testing.T) {
+	client := getTestClient(t)
+
+	result, err := client.ListDocuments(DocumentListParams{Limit: 1})
+	if err != nil {
+		t.Fatalf("ListDocuments failed: %v", err)
+	}
+
+	if len(result.Results) == 0 {
+		t.Skip("No documents available for testing")
+	}
+
+	docID := result.Results[0].ID
+	suggestions, err := client.GetDocumentSuggestions(docID)
+	if err != nil {
+		t.Fatalf("GetDocumentSuggestions failed: %v", err)
+	}
+
+	t.Logf("Suggestions for %d: correspondents %v, types %v, tags %v, dates %v",
+		docID, suggestions.Correspondents, suggestions.DocumentTypes, suggestions.Tags, suggestions.Dates)
+}
+
+func TestGetNextASN(t *testing.T) {
+	client := getTestClient(t)
+
+	asn, err := client.GetNextASN()
+	if err != nil {
+		t.Fatalf("GetNextASN failed: %v", err)
+	}
+
+	if asn < 1 {
+		t.Errorf("expected a positive ASN, got %d", asn)
+	}
+	t.Logf("Next ASN: %d", asn)
+}
+
+func TestSearchAutocomplete(t *testing.T) {
+	client := getTestClient(t)
+
+	words, err := client.SearchAutocomplete("in", 5)
+	if err != nil {
+		t.Fatalf("SearchAutocomplete failed: %v", err)
+	}
+
+	if len(words) > 5 {
+		t.Errorf("expected at most 5 words, got %d", len(words))
+	}
+	t.Logf("Autocomplete for 'in': %v", words)
+}
+
 func TestGetSimilarDocuments(t *testing.T) {
 	client := getTestClient(t)
 
@@ -127,6 +230,38 @@ func TestDownloadDocument(t *testing.T) {
 	t.Logf("Downloaded document %d: %s (%d bytes)", docID, filename, len(data))
 }
 
+func TestDownloadDocumentTo(t *testing.T) {
+	client := getTestClient(t)
+
+	result, err := client.ListDocuments(DocumentListParams{Limit: 1})
+	if err != nil {
+		t.Fatalf("ListDocuments failed: %v", err)
+	}
+
+	if len(result.Results) == 0 {
+		t.Skip("No documents available for testing")
+	}
+
+	docID := result.Results[0].ID
+	var buf bytes.Buffer
+	var progressCalls int
+	info, err := client.DownloadDocumentTo(context.Background(), docID, &buf, DownloadOptions{
+		Progress: func(done, total int64) { progressCalls++ },
+	})
+	if err != nil {
+		t.Fatalf("DownloadDocumentTo failed: %v", err)
+	}
+
+	if info.Written != int64(buf.Len()) {
+		t.Errorf("Written = %d, buffer has %d bytes", info.Written, buf.Len())
+	}
+	if progressCalls == 0 {
+		t.Error("progress callback was never called")
+	}
+
+	t.Logf("Streamed document %d: %s (%d of %d bytes)", docID, info.Filename, info.Written, info.Size)
+}
+
 func TestGetDocumentThumb(t *testing.T) {
 	client := getTestClient(t)
 
@@ -159,10 +294,14 @@ func TestUploadDocument(t *testing.T) {
 		t.Skip("Test PDF not found at testdata/test_upload.pdf")
 	}
 
-	taskID, err := client.UploadDocument(testFile, "API Test Upload", nil, nil, nil)
+	result, err := client.UploadDocument(testFile, "API Test Upload", nil, nil, nil)
 	if err != nil {
 		t.Fatalf("UploadDocument failed: %v", err)
 	}
+	taskID := result.TaskID
+	for _, warning := range result.Warnings {
+		t.Logf("Upload warning: %s", warning)
+	}
 
 	t.Logf("Upload task ID: %s", taskID)
 
@@ -175,12 +314,40 @@ func TestUploadDocument(t *testing.T) {
 	}
 }
 
+func TestParseUploadResult(t *testing.T) {
+	const id = "b6a2f9c0-1111-2222-3333-444455556666"
+	tests := []struct {
+		body     string
+		warnings int
+	}{
+		{id, 0},
+		{`"` + id + `"` + "\n", 0},
+		{`{"task_id": "` + id + `"}`, 0},
+		{`{"task_id": "` + id + `", "warnings": ["Possible duplicate of document 12"]}`, 1},
+		{`{"id": "` + id + `", "warning": "Not consuming: duplicate"}`, 1},
+	}
+	for _, tt := range tests {
+		result, err := parseUploadResult([]byte(tt.body))
+		if err != nil {
+			t.Errorf("parseUploadResult(%s) failed: %v", tt.body, err)
+			continue
+		}
+		if result.TaskID != id || len(result.Warnings) != tt.warnings {
+			t.Errorf("parseUploadResult(%s) = %+v", tt.body, result)
+		}
+	}
+
+	if _, err := parseUploadResult([]byte(`{"detail": "ok"}`)); err == nil {
+		t.Error("expected an error for a response without a task ID")
+	}
+}
+
 // ==================== Tag Tests ====================
 
 func TestListTags(t *testing.T) {
 	client := getTestClient(t)
 
-	result, err := client.ListTags()
+	result, err := client.ListTags(ListParams{})
 	if err != nil {
 		t.Fatalf("ListTags failed: %v", err)
 	}
@@ -195,7 +362,7 @@ func TestCreateAndDeleteTag(t *testing.T) {
 	client := getTestClient(t)
 
 	// Create a test tag
-	tag, err := client.CreateTag("test-cli-tag", "#ff0000")
+	tag, err := client.CreateTag("test-cli-tag", "#ff0000", nil)
 	if err != nil {
 		t.Fatalf("CreateTag failed: %v", err)
 	}
@@ -220,12 +387,36 @@ func TestCreateAndDeleteTag(t *testing.T) {
 	t.Logf("Deleted tag %d", tag.ID)
 }
 
+func TestCreateInboxTag(t *testing.T) {
+	client := getTestClient(t)
+
+	tag, err := client.CreateTag("test-cli-inbox", "#000000", map[string]interface{}{
+		"text_color":         "#ffffff",
+		"is_inbox_tag":       true,
+		"match":              "^scan",
+		"matching_algorithm": MatchRegex,
+	})
+	if err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+	defer client.DeleteTag(tag.ID)
+
+	if !tag.IsInboxTag || tag.TextColor != "#ffffff" {
+		t.Errorf("Tag options not set: inbox %t, text color %q", tag.IsInboxTag, tag.TextColor)
+	}
+	if tag.Match != "^scan" || tag.MatchingAlgo != MatchRegex {
+		t.Errorf("Matching not set: match %q, algorithm %d", tag.Match, tag.MatchingAlgo)
+	}
+
+	t.Logf("Created inbox tag: [%d] %s", tag.ID, tag.Name)
+}
+
 // ==================== Correspondent Tests ====================
 
 func TestListCorrespondents(t *testing.T) {
 	client := getTestClient(t)
 
-	result, err := client.ListCorrespondents()
+	result, err := client.ListCorrespondents(ListParams{})
 	if err != nil {
 		t.Fatalf("ListCorrespondents failed: %v", err)
 	}
@@ -240,10 +431,17 @@ func TestCreateAndDeleteCorrespondent(t *testing.T) {
 	client := getTestClient(t)
 
 	// Create a test correspondent
-	corr, err := client.CreateCorrespondent("Test CLI Correspondent")
+	corr, err := client.CreateCorrespondent("Test CLI Correspondent", map[string]interface{}{
+		"match":              "ACME",
+		"matching_algorithm": MatchLiteral,
+		"is_insensitive":     false,
+	})
 	if err != nil {
 		t.Fatalf("CreateCorrespondent failed: %v", err)
 	}
+	if corr.Match != "ACME" || corr.MatchingAlgo != MatchLiteral || corr.IsInsensitive {
+		t.Errorf("Matching not set: match %q, algorithm %d, insensitive %t", corr.Match, corr.MatchingAlgo, corr.IsInsensitive)
+	}
 
 	t.Logf("Created correspondent: [%d] %s", corr.ID, corr.Name)
 
@@ -270,7 +468,7 @@ func TestCreateAndDeleteCorrespondent(t *testing.T) {
 func TestListDocumentTypes(t *testing.T) {
 	client := getTestClient(t)
 
-	result, err := client.ListDocumentTypes()
+	result, err := client.ListDocumentTypes(ListParams{})
 	if err != nil {
 		t.Fatalf("ListDocumentTypes failed: %v", err)
 	}
@@ -285,7 +483,7 @@ func TestCreateAndDeleteDocumentType(t *testing.T) {
 	client := getTestClient(t)
 
 	// Create a test document type
-	dt, err := client.CreateDocumentType("Test CLI DocType")
+	dt, err := client.CreateDocumentType("Test CLI DocType", nil)
 	if err != nil {
 		t.Fatalf("CreateDocumentType failed: %v", err)
 	}
@@ -310,12 +508,33 @@ func TestCreateAndDeleteDocumentType(t *testing.T) {
 	t.Logf("Deleted document type %d", dt.ID)
 }
 
+func TestValidationErrors(t *testing.T) {
+	client := getTestClient(t)
+
+	// A blank name is rejected with a validation error for the field
+	_, err := client.CreateTag("", "", nil)
+	if err == nil {
+		t.Fatal("expected CreateTag with a blank name to fail")
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError, got %T: %v", err, err)
+	}
+	v := apiErr.Validation()
+	if len(v["name"]) == 0 {
+		t.Fatalf("expected a validation message for name, got %v", v)
+	}
+
+	t.Logf("Validation error:\n%v", err)
+}
+
 // ==================== Storage Path Tests ====================
 
 func TestListStoragePaths(t *testing.T) {
 	client := getTestClient(t)
 
-	result, err := client.ListStoragePaths()
+	result, err := client.ListStoragePaths(ListParams{})
 	if err != nil {
 		t.Fatalf("ListStoragePaths failed: %v", err)
 	}
@@ -330,7 +549,7 @@ func TestCreateAndDeleteStoragePath(t *testing.T) {
 	client := getTestClient(t)
 
 	// Create a test storage path
-	sp, err := client.CreateStoragePath("Test CLI Path", "test/{{ created_year }}")
+	sp, err := client.CreateStoragePath("Test CLI Path", "test/{{ created_year }}", nil)
 	if err != nil {
 		t.Fatalf("CreateStoragePath failed: %v", err)
 	}
@@ -355,12 +574,45 @@ func TestCreateAndDeleteStoragePath(t *testing.T) {
 	t.Logf("Deleted storage path %d", sp.ID)
 }
 
+func TestUpdateStoragePath(t *testing.T) {
+	client := getTestClient(t)
+
+	sp, err := client.CreateStoragePath("Test CLI Match Path", "test/{{ title }}", map[string]interface{}{
+		"match":              "invoice",
+		"matching_algorithm": MatchAny,
+	})
+	if err != nil {
+		t.Fatalf("CreateStoragePath failed: %v", err)
+	}
+	defer client.DeleteStoragePath(sp.ID)
+
+	if sp.Match != "invoice" || sp.MatchingAlgo != MatchAny {
+		t.Errorf("Matching not set on create: match %q, algorithm %d", sp.Match, sp.MatchingAlgo)
+	}
+
+	updated, err := client.UpdateStoragePath(sp.ID, map[string]interface{}{
+		"path":               "test/{{ created_year }}/{{ title }}",
+		"matching_algorithm": MatchLiteral,
+	})
+	if err != nil {
+		t.Fatalf("UpdateStoragePath failed: %v", err)
+	}
+	if updated.Path != "test/{{ created_year }}/{{ title }}" || updated.MatchingAlgo != MatchLiteral {
+		t.Errorf("Storage path not updated: path %q, algorithm %d", updated.Path, updated.MatchingAlgo)
+	}
+	if updated.Match != "invoice" {
+		t.Errorf("Update changed match: got %q", updated.Match)
+	}
+
+	t.Logf("Updated storage path %d: %s (%s)", updated.ID, updated.Path, MatchingAlgorithmName(updated.MatchingAlgo))
+}
+
 // ==================== Saved View Tests ====================
 
 func TestListSavedViews(t *testing.T) {
 	client := getTestClient(t)
 
-	result, err := client.ListSavedViews()
+	result, err := client.ListSavedViews(ListParams{})
 	if err != nil {
 		t.Fatalf("ListSavedViews failed: %v", err)
 	}
@@ -371,6 +623,104 @@ func TestListSavedViews(t *testing.T) {
 	}
 }
 
+func TestCreateAndDeleteSavedView(t *testing.T) {
+	client := getTestClient(t)
+
+	rules := []FilterRule{NewFilterRule(FilterTitleContains, "invoice")}
+	sv, err := client.CreateSavedView("Test CLI View", rules, map[string]interface{}{"sort_field": "created"})
+	if err != nil {
+		t.Fatalf("CreateSavedView failed: %v", err)
+	}
+
+	t.Logf("Created saved view: [%d] %s", sv.ID, sv.Name)
+
+	updated, err := client.UpdateSavedView(sv.ID, map[string]interface{}{"show_in_sidebar": true})
+	if err != nil {
+		t.Fatalf("UpdateSavedView failed: %v", err)
+	}
+	if !updated.ShowInSidebar {
+		t.Error("expected the view to be shown in the sidebar")
+	}
+	if len(updated.FilterRules) != 1 || updated.FilterRules[0].RuleType != FilterTitleContains {
+		t.Errorf("filter rules mismatch: got %+v", updated.FilterRules)
+	}
+
+	if err := client.DeleteSavedView(sv.ID); err != nil {
+		t.Fatalf("DeleteSavedView failed: %v", err)
+	}
+
+	t.Logf("Deleted saved view %d", sv.ID)
+}
+
+func TestRunSavedView(t *testing.T) {
+	client := getTestClient(t)
+
+	result, err := client.ListSavedViews(ListParams{})
+	if err != nil {
+		t.Fatalf("ListSavedViews failed: %v", err)
+	}
+	if len(result.Results) == 0 {
+		t.Skip("No saved views available for testing")
+	}
+
+	sv := result.Results[0]
+	filter, err := FilterRuleParams(sv.FilterRules)
+	if err != nil {
+		t.Fatalf("FilterRuleParams failed: %v", err)
+	}
+	docs, err := client.ListDocuments(DocumentListParams{Filter: filter, Limit: 5})
+	if err != nil {
+		t.Fatalf("ListDocuments with %v failed: %v", filter, err)
+	}
+
+	t.Logf("Saved view %q (%s) shows %d documents", sv.Name, filter.Encode(), docs.Count)
+}
+
+// ==================== Share Link Tests ====================
+
+func TestListShareLinks(t *testing.T) {
+	client := getTestClient(t)
+	if !client.Supports(FeatureShareLinks) {
+		t.Skip("server does not support share links")
+	}
+
+	result, err := client.ListShareLinks(ListParams{})
+	if err != nil {
+		t.Fatalf("ListShareLinks failed: %v", err)
+	}
+
+	t.Logf("Found %d share links", len(result.Results))
+	for _, link := range result.Results {
+		t.Logf("  - [%d] document %d (%s): %s", link.ID, link.Document, link.FileVersion, client.ShareURL(link))
+	}
+}
+
+// ==================== User Tests ====================
+
+func TestListUsers(t *testing.T) {
+	client := getTestClient(t)
+
+	result, err := client.ListUsers(ListParams{})
+	if err != nil {
+		t.Fatalf("ListUsers failed: %v", err)
+	}
+
+	t.Logf("Found %d users", len(result.Results))
+	for _, u := range result.Results {
+		t.Logf("  - [%d] %s (active: %t, superuser: %t)", u.ID, u.Username, u.IsActive, u.IsSuperuser)
+	}
+
+	if len(result.Results) > 0 {
+		found, err := client.FindUserByName(result.Results[0].Username)
+		if err != nil {
+			t.Fatalf("FindUserByName failed: %v", err)
+		}
+		if found.ID != result.Results[0].ID {
+			t.Errorf("FindUserByName returned user %d, want %d", found.ID, result.Results[0].ID)
+		}
+	}
+}
+
 // ==================== Statistics Test ====================
 
 func TestGetStatistics(t *testing.T) {
@@ -395,6 +745,47 @@ func TestGetTask(t *testing.T) {
 	t.Skip("Task test requires a valid task ID from a recent upload")
 }
 
+func TestListTasks(t *testing.T) {
+	client := getTestClient(t)
+
+	tasks, err := client.ListTasks(TaskListParams{Status: "FAILURE"})
+	if err != nil {
+		t.Fatalf("ListTasks failed: %v", err)
+	}
+
+	t.Logf("Found %d failed tasks", len(tasks))
+	for _, task := range tasks {
+		if task.Status != "FAILURE" {
+			t.Errorf("task %s has status %s, want FAILURE", task.TaskID, task.Status)
+		}
+		t.Logf("  - [%s] %s: %s", task.TaskID, task.TaskFileName, task.Result)
+	}
+}
+
+func TestStatusStream(t *testing.T) {
+	client := getTestClient(t)
+
+	// Updates only come while files are consumed
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.OpenStatusStream(ctx)
+	if err != nil {
+		t.Skipf("Status websocket unavailable: %v", err)
+	}
+	defer stream.Close()
+
+	status, err := stream.Next()
+	if err != nil {
+		t.Skipf("No consumption update within 5s: %v", err)
+	}
+	if status.TaskID == "" {
+		t.Errorf("Update without task ID: %+v", status)
+	}
+
+	t.Logf("Consumption update: %s %s %s (%d/%d)", status.Filename, status.Status, status.Message, status.CurrentProgress, status.MaxProgress)
+}
+
 // ==================== Find By Name Tests ====================
 
 func TestFindByName(t *testing.T) {
@@ -426,3 +817,72 @@ func TestFindByName(t *testing.T) {
 
 	t.Log("FindByName tests passed - all returned expected errors for non-existent items")
 }
+
+// ==================== Server Version Tests ====================
+
+func TestServerVersion(t *testing.T) {
+	client := getTestClient(t)
+
+	version, err := client.ServerVersion()
+	if err != nil {
+		t.Fatalf("ServerVersion failed: %v", err)
+	}
+
+	t.Logf("Server version: %s (API v%d, server supports up to v%d)", version, client.APIVersion(), client.ServerAPIVersion())
+	if max := client.ServerAPIVersion(); max > 0 && client.APIVersion() > max {
+		t.Errorf("requested API version %d exceeds server maximum %d", client.APIVersion(), max)
+	}
+	for _, f := range Features() {
+		t.Logf("  - %s (>= %s): %t", f, RequiredVersion(f), client.Supports(f))
+	}
+}
+
+func TestGetRemoteVersion(t *testing.T) {
+	client := getTestClient(t)
+
+	remote, err := client.GetRemoteVersion()
+	if err != nil {
+		t.Fatalf("GetRemoteVersion failed: %v", err)
+	}
+
+	if !remote.Checked() {
+		t.Skip("server has update checking disabled")
+	}
+	t.Logf("Latest release: %s (update available: %t)", remote.Version, remote.UpdateAvailable)
+}
+
+func TestGetAppConfig(t *testing.T) {
+	client := getTestClient(t)
+
+	cfg, err := client.GetAppConfig()
+	if err != nil {
+		t.Fatalf("GetAppConfig failed: %v", err)
+	}
+
+	if cfg.ID() == 0 {
+		t.Error("Expected configuration to have an ID")
+	}
+	t.Logf("Application configuration has %d settings", len(cfg))
+}
+
+func TestObtainToken(t *testing.T) {
+	url := os.Getenv("PAPERLESS_URL")
+	username := os.Getenv("PAPERLESS_USERNAME")
+	password := os.Getenv("PAPERLESS_PASSWORD")
+	if url == "" || username == "" || password == "" {
+		t.Skip("PAPERLESS_URL, PAPERLESS_USERNAME and PAPERLESS_PASSWORD must be set")
+	}
+
+	token, err := NewClient(url, "").ObtainToken(username, password)
+	if err != nil {
+		t.Fatalf("ObtainToken failed: %v", err)
+	}
+
+	if _, err := NewClient(url, token).GetStatistics(); err != nil {
+		t.Errorf("Token from login doesn't work: %v", err)
+	}
+
+	if _, err := NewClient(url, "").ObtainToken(username, password+"-wrong"); err == nil {
+		t.Error("Expected login with a wrong password to fail")
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// AppConfig is the instance-wide application configuration, e.g. OCR
+// language and mode or the app title. Keys vary between server versions, so
+// it is kept as the raw object; "id" identifies it for updates.
+type AppConfig map[string]any
+
+// ID returns the ID of the configuration object
+func (a AppConfig) ID() int {
+	id, _ := a["id"].(float64)
+	return int(id)
+}
+
+// UISettings are the web UI settings of the token's user
+type UISettings struct {
+	User        map[string]any `json:"user"`
+	Settings    map[string]any `json:"settings"`
+	Permissions []string       `json:"permissions,omitempty"`
+}
+
+// GetAppConfig gets the application configuration. Only superusers may read
+// it.
+func (c *Client) GetAppConfig() (AppConfig, error) {
+	if err := c.RequireFeature(FeatureAppConfig); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.get("/api/config/")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("", resp)
+	}
+
+	// The endpoint lists configurations, but there is only ever one
+	var configs []AppConfig
+	if err := json.NewDecoder(resp.Body).Decode(&configs); err != nil {
+		return nil, err
+	}
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("server returned no application configuration")
+	}
+
+	return configs[0], nil
+}
+
+// UpdateAppConfig changes fields of the application configuration
+func (c *Client) UpdateAppConfig(id int, updates map[string]any) (AppConfig, error) {
+	resp, err := c.patch(fmt.Sprintf("/api/config/%d/", id), updates)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("update", resp)
+	}
+
+	var cfg AppConfig
+	if err := json.NewDecoder(resp.Body).Decode(&cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// GetUISettings gets the web UI settings of the token's user
+func (c *Client) GetUISettings() (*UISettings, error) {
+	resp, err := c.get("/api/ui_settings/")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("", resp)
+	}
+
+	var settings UISettings
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// SaveUISettings stores the web UI settings of the token's user. The server
+// replaces all settings, so settings must be complete, not just the changes.
+func (c *Client) SaveUISettings(settings map[string]any) error {
+	resp, err := c.post("/api/ui_settings/", map[string]any{"settings": settings})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return newAPIError("save", resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+)
+
+// Timing breaks down where the time of a single request went
+type Timing struct {
+	DNS       time.Duration `json:"dns"`
+	Connect   time.Duration `json:"connect"`
+	TLS       time.Duration `json:"tls"`
+	FirstByte time.Duration `json:"first_byte"`
+	Total     time.Duration `json:"total"`
+	Bytes     int64         `json:"bytes"`
+	Reused    bool          `json:"reused"`
+}
+
+// TimedGet performs a GET on path, discards the body and reports connection
+// setup, server and transfer timings
+func (c *Client) TimedGet(path string) (*Timing, error) {
+	req, err := c.newRequest("GET", path, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var t Timing
+	var dnsStart, connectStart, tlsStart time.Time
+	trace := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone:           func(httptrace.DNSDoneInfo) { t.DNS = time.Since(dnsStart) },
+		ConnectStart:      func(string, string) { connectStart = time.Now() },
+		ConnectDone:       func(string, string, error) { t.Connect = time.Since(connectStart) },
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { t.TLS = time.Since(tlsStart) },
+		GotConn:           func(info httptrace.GotConnInfo) { t.Reused = info.Reused },
+	}
+
+	start := time.Now()
+	trace.GotFirstResponseByte = func() { t.FirstByte = time.Since(start) }
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("", resp)
+	}
+
+	t.Bytes, err = io.Copy(io.Discard, resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	t.Total = time.Since(start)
+
+	return &t, nil
+}
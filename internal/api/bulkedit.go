@@ -0,0 +1,162 @@
+package api
+
+import "net/http"
+
+// BulkOperation is a bulk edit method with its parameters. Use the Bulk*
+// constructors to build one.
+type BulkOperation struct {
+	Method     string
+	Parameters map[string]any
+}
+
+// PermissionSet lists the users and groups granted a permission
+type PermissionSet struct {
+	Users  []int `json:"users"`
+	Groups []int `json:"groups"`
+}
+
+// ObjectPermissions are the view and change permissions of an object
+type ObjectPermissions struct {
+	View   PermissionSet `json:"view"`
+	Change PermissionSet `json:"change"`
+}
+
+// BulkSetCorrespondent sets the correspondent, or clears it if id is nil
+func BulkSetCorrespondent(id *int) BulkOperation {
+	return BulkOperation{"set_correspondent", map[string]any{"correspondent": id}}
+}
+
+// BulkSetDocumentType sets the document type, or clears it if id is nil
+func BulkSetDocumentType(id *int) BulkOperation {
+	return BulkOperation{"set_document_type", map[string]any{"document_type": id}}
+}
+
+// BulkSetStoragePath sets the storage path, or clears it if id is nil
+func BulkSetStoragePath(id *int) BulkOperation {
+	return BulkOperation{"set_storage_path", map[string]any{"storage_path": id}}
+}
+
+// BulkAddTag adds a tag
+func BulkAddTag(id int) BulkOperation {
+	return BulkOperation{"add_tag", map[string]any{"tag": id}}
+}
+
+// BulkRemoveTag removes a tag
+func BulkRemoveTag(id int) BulkOperation {
+	return BulkOperation{"remove_tag", map[string]any{"tag": id}}
+}
+
+// BulkModifyTags adds and removes several tags at once
+func BulkModifyTags(add, remove []int) BulkOperation {
+	return BulkOperation{"modify_tags", map[string]any{
+		"add_tags":    nonNilInts(add),
+		"remove_tags": nonNilInts(remove),
+	}}
+}
+
+// BulkModifyCustomFields sets custom field values, keyed by field ID, and
+// removes fields
+func BulkModifyCustomFields(add map[int]any, remove []int) BulkOperation {
+	if add == nil {
+		add = map[int]any{}
+	}
+	return BulkOperation{"modify_custom_fields", map[string]any{
+		"add_custom_fields":    add,
+		"remove_custom_fields": nonNilInts(remove),
+	}}
+}
+
+// BulkSetPermissions sets the owner (unless nil) and permissions (unless
+// nil). With merge, existing permissions are extended rather than replaced.
+func BulkSetPermissions(owner *int, permissions *ObjectPermissions, merge bool) BulkOperation {
+	params := map[string]any{"merge": merge}
+	if owner != nil {
+		params["owner"] = *owner
+	}
+	if permissions != nil {
+		params["set_permissions"] = permissions
+	}
+	return BulkOperation{"set_permissions", params}
+}
+
+// BulkDelete deletes the documents
+func BulkDelete() BulkOperation {
+	return BulkOperation{"delete", map[string]any{}}
+}
+
+// BulkReprocess runs the documents through the consumer again, which
+// regenerates the archived version and replaces the content with fresh OCR
+func BulkReprocess() BulkOperation {
+	return BulkOperation{"reprocess", map[string]any{}}
+}
+
+// BulkMerge combines the documents, in the order given, into a new document.
+// Its metadata is copied from metadataDocument unless that is nil; with
+// deleteOriginals the merged documents are deleted afterwards.
+func BulkMerge(metadataDocument *int, deleteOriginals bool) BulkOperation {
+	params := map[string]any{"delete_originals": deleteOriginals}
+	if metadataDocument != nil {
+		params["metadata_document_id"] = *metadataDocument
+	}
+	return BulkOperation{"merge", params}
+}
+
+// BulkSplit splits a document into new documents, one per page range in
+// pages, like "1-3,4,5-7". With deleteOriginals the original is deleted
+// afterwards.
+func BulkSplit(pages string, deleteOriginals bool) BulkOperation {
+	return BulkOperation{"split", map[string]any{
+		"pages":            pages,
+		"delete_originals": deleteOriginals,
+	}}
+}
+
+// BulkRotate rotates all pages of the documents clockwise by degrees, a
+// multiple of 90
+func BulkRotate(degrees int) BulkOperation {
+	return BulkOperation{"rotate", map[string]any{"degrees": degrees}}
+}
+
+// BulkDeletePages removes the given pages, counted from 1, from a document
+func BulkDeletePages(pages []int) BulkOperation {
+	return BulkOperation{"delete_pages", map[string]any{"pages": nonNilInts(pages)}}
+}
+
+// BulkEditDocuments applies op to all given documents in one request
+func (c *Client) BulkEditDocuments(documents []int, op BulkOperation) error {
+	if len(documents) == 0 {
+		return nil
+	}
+	return c.BulkEdit(documents, op.Method, op.Parameters)
+}
+
+// BulkEdit applies a bulk operation such as "add_tag" or
+// "modify_custom_fields" to the given documents
+func (c *Client) BulkEdit(documents []int, method string, parameters map[string]any) error {
+	if parameters == nil {
+		parameters = map[string]any{}
+	}
+	resp, err := c.post("/api/documents/bulk_edit/", map[string]any{
+		"documents":  documents,
+		"method":     method,
+		"parameters": parameters,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError("bulk edit", resp)
+	}
+
+	return nil
+}
+
+// nonNilInts returns ids, or an empty slice so it's sent as [] not null
+func nonNilInts(ids []int) []int {
+	if ids == nil {
+		return []int{}
+	}
+	return ids
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Cache persists response bodies between runs so that list requests can be
+// served locally or revalidated with If-None-Match / If-Modified-Since
+// instead of refetched
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Put(key string, data []byte) error
+	Delete(key string) error
+}
+
+// WithCache caches the tag, correspondent, document type, storage path and
+// custom field lists. Responses younger than ttl are used without asking
+// the server; older ones are revalidated with a conditional request.
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+// cachedResources are the collections whose unfiltered lists are cached
+var cachedResources = []string{
+	"/api/tags/",
+	"/api/correspondents/",
+	"/api/document_types/",
+	"/api/storage_paths/",
+	"/api/custom_fields/",
+}
+
+// cachedResponse is what gets stored for a cacheable GET
+type cachedResponse struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Stored       time.Time `json:"stored"`
+	Body         []byte    `json:"body"`
+}
+
+// cacheKey identifies a response per server, token and path. The token is
+// part of the key because permissions change what a list contains.
+func (c *Client) cacheKey(path string) string {
+	sum := sha256.Sum256([]byte(c.baseURL + "\x00" + c.token + "\x00" + path))
+	return hex.EncodeToString(sum[:])
+}
+
+// getCached makes a GET request that is answered from the cache while the
+// entry is fresh and revalidated afterwards. On a 304 the cached body is
+// returned as if the server had sent it.
+func (c *Client) getCached(path string) (*http.Response, error) {
+	if c.cache == nil {
+		return c.get(path)
+	}
+
+	key := c.cacheKey(path)
+	var cached *cachedResponse
+	if data, ok := c.cache.Get(key); ok {
+		var entry cachedResponse
+		if json.Unmarshal(data, &entry) == nil {
+			cached = &entry
+		}
+	}
+
+	if cached != nil && time.Since(cached.Stored) < c.cacheTTL {
+		return &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader(cached.Body)),
+		}, nil
+	}
+
+	req, err := c.newRequest("GET", path, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		resp.Body.Close()
+		resp.StatusCode = http.StatusOK
+		resp.Status = "200 OK"
+		resp.Body = io.NopCloser(bytes.NewReader(cached.Body))
+		c.storeCached(key, *cached)
+
+	case resp.StatusCode == http.StatusOK:
+		entry := cachedResponse{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		}
+		// Without a validator the entry is only useful while it is fresh
+		if entry.ETag == "" && entry.LastModified == "" && c.cacheTTL <= 0 {
+			break
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		entry.Body = body
+		c.storeCached(key, entry)
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	return resp, nil
+}
+
+func (c *Client) storeCached(key string, entry cachedResponse) {
+	entry.Stored = time.Now()
+	if data, err := json.Marshal(entry); err == nil {
+		// A cache that can't be written only costs speed
+		c.cache.Put(key, data)
+	}
+}
+
+// invalidateFor drops cached lists that a modifying request to path makes
+// stale, so a tag created by one command is visible to the next one
+func (c *Client) invalidateFor(path string) {
+	if c.cache == nil {
+		return
+	}
+	for _, prefix := range cachedResources {
+		if strings.HasPrefix(path, prefix) || strings.HasPrefix(path, "/api/bulk_edit_objects/") {
+			c.invalidateList(prefix)
+		}
+	}
+}
+
+// invalidateList drops all cached pages of a collection. It reports whether
+// there was a cache to drop from, i.e. whether refetching could help.
+func (c *Client) invalidateList(path string) bool {
+	if c.cache == nil {
+		return false
+	}
+	for page := 1; ; page++ {
+		key := c.cacheKey(listPagePath(path, page))
+		if _, ok := c.cache.Get(key); !ok {
+			break
+		}
+		c.cache.Delete(key)
+	}
+	return true
+}
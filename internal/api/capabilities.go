@@ -0,0 +1,273 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Version is a Paperless-ngx release version
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion parses versions like "2.14.7" or "v2.3.0-beta.rc1"
+func ParseVersion(s string) (Version, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(s, "-+ "); i != -1 {
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) == 0 || parts[0] == "" {
+		return Version{}, fmt.Errorf("invalid version: %q", s)
+	}
+
+	var nums [3]int
+	for i := 0; i < len(parts) && i < 3; i++ {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version: %q", s)
+		}
+		nums[i] = n
+	}
+
+	return Version{Major: nums[0], Minor: nums[1], Patch: nums[2]}, nil
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// AtLeast reports whether v is the same as or newer than other
+func (v Version) AtLeast(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor > other.Minor
+	}
+	return v.Patch >= other.Patch
+}
+
+// Feature is server functionality that only exists in newer Paperless-ngx releases
+type Feature string
+
+const (
+	FeatureCustomFields Feature = "custom fields"
+	FeatureShareLinks   Feature = "share links"
+	FeatureWorkflows    Feature = "workflows"
+	FeatureGlobalSearch Feature = "global search"
+	FeaturePDFEditing   Feature = "merge, split and rotate"
+	FeatureAppConfig    Feature = "application configuration"
+	FeatureDeletePages  Feature = "deleting pages"
+	FeatureTrash        Feature = "trash"
+
+	FeatureCustomFieldQuery      Feature = "filtering by custom field values"
+	FeatureSelectOptionIDs       Feature = "select options with IDs"
+	FeatureBulkCustomFieldValues Feature = "setting custom field values in bulk"
+)
+
+// featureVersions maps each feature to the first server release supporting it
+var featureVersions = map[Feature]Version{
+	FeatureCustomFields: {2, 0, 0},
+	FeatureShareLinks:   {2, 0, 0},
+	FeatureWorkflows:    {2, 0, 0},
+	FeatureGlobalSearch: {2, 3, 0},
+	FeaturePDFEditing:   {2, 3, 0},
+	FeatureAppConfig:    {2, 3, 0},
+	FeatureDeletePages:  {2, 5, 0},
+	FeatureTrash:        {2, 10, 0},
+
+	FeatureCustomFieldQuery:      {2, 11, 0},
+	FeatureSelectOptionIDs:       {2, 14, 0},
+	FeatureBulkCustomFieldValues: {2, 15, 0},
+}
+
+// Features returns all known version-gated features
+func Features() []Feature {
+	return []Feature{
+		FeatureCustomFields,
+		FeatureShareLinks,
+		FeatureWorkflows,
+		FeatureGlobalSearch,
+		FeaturePDFEditing,
+		FeatureAppConfig,
+		FeatureDeletePages,
+		FeatureTrash,
+		FeatureCustomFieldQuery,
+		FeatureSelectOptionIDs,
+		FeatureBulkCustomFieldValues,
+	}
+}
+
+// RequiredVersion returns the minimum server version for a feature
+func RequiredVersion(f Feature) Version {
+	return featureVersions[f]
+}
+
+// UnsupportedFeatureError is returned when the server is too old for a feature
+type UnsupportedFeatureError struct {
+	Feature  Feature
+	Required Version
+	Server   Version
+}
+
+func (e *UnsupportedFeatureError) Error() string {
+	return fmt.Sprintf("%s requires Paperless-ngx >= %s (server is %s)", e.Feature, e.Required, e.Server)
+}
+
+// preferredAPIVersion is the REST API version the response types in this
+// package are written against. Servers supporting only older versions are
+// spoken to in their newest version instead.
+const preferredAPIVersion = 5
+
+// recordServerVersion remembers the versions advertised in response headers
+func (c *Client) recordServerVersion(resp *http.Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if v, err := ParseVersion(resp.Header.Get("X-Version")); err == nil {
+		c.serverVersion = &v
+	}
+	if n, err := strconv.Atoi(resp.Header.Get("X-Api-Version")); err == nil && n > 0 {
+		c.serverAPIVersion = n
+	}
+}
+
+// APIVersion returns the REST API version requested in the Accept header.
+// Until a response has told which versions the server supports, this is
+// the preferred version.
+func (c *Client) APIVersion() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.serverAPIVersion > 0 && c.serverAPIVersion < preferredAPIVersion {
+		return c.serverAPIVersion
+	}
+	return preferredAPIVersion
+}
+
+// ServerAPIVersion returns the newest REST API version the server supports,
+// or 0 if it is not known yet
+func (c *Client) ServerAPIVersion() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.serverAPIVersion
+}
+
+// ServerVersion returns the Paperless-ngx version of the server. The version
+// is taken from the X-Version header, which the server only sends to
+// authenticated requests. If a proxy strips the header, the system status
+// endpoint (admin only) is asked instead.
+func (c *Client) ServerVersion() (Version, error) {
+	if v := c.knownServerVersion(); v != nil {
+		return *v, nil
+	}
+
+	resp, err := c.get("/api/")
+	if err != nil {
+		return Version{}, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Version{}, newAPIError("", resp)
+	}
+	if v := c.knownServerVersion(); v != nil {
+		return *v, nil
+	}
+
+	if v, err := c.statusVersion(); err == nil {
+		c.mu.Lock()
+		c.serverVersion = &v
+		c.mu.Unlock()
+		return v, nil
+	}
+
+	return Version{}, fmt.Errorf("server did not report its version")
+}
+
+func (c *Client) knownServerVersion() *Version {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.serverVersion
+}
+
+// statusVersion reads the version from /api/status/
+func (c *Client) statusVersion() (Version, error) {
+	resp, err := c.get("/api/status/")
+	if err != nil {
+		return Version{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Version{}, newAPIError("", resp)
+	}
+
+	var status struct {
+		Version string `json:"pngx_version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return Version{}, err
+	}
+	return ParseVersion(status.Version)
+}
+
+// RemoteVersion is the newest Paperless-ngx release as seen by the server
+type RemoteVersion struct {
+	Version         string `json:"version"`
+	UpdateAvailable bool   `json:"update_available"`
+}
+
+// Checked reports whether the server looked the release up. Servers with
+// update checking disabled report version 0.0.0.
+func (r RemoteVersion) Checked() bool {
+	return r.Version != "" && strings.TrimPrefix(r.Version, "v") != "0.0.0"
+}
+
+// GetRemoteVersion asks the server for the latest release on GitHub
+func (c *Client) GetRemoteVersion() (*RemoteVersion, error) {
+	resp, err := c.get("/api/remote_version/")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("", resp)
+	}
+
+	var remote RemoteVersion
+	if err := json.NewDecoder(resp.Body).Decode(&remote); err != nil {
+		return nil, err
+	}
+	return &remote, nil
+}
+
+// Supports reports whether the server is new enough for a feature. Servers
+// that don't report a version are assumed to support everything.
+func (c *Client) Supports(f Feature) bool {
+	return c.RequireFeature(f) == nil
+}
+
+// RequireFeature returns an UnsupportedFeatureError if the server is too old
+// for the given feature
+func (c *Client) RequireFeature(f Feature) error {
+	server, err := c.ServerVersion()
+	if err != nil {
+		return nil
+	}
+
+	required := featureVersions[f]
+	if !server.AtLeast(required) {
+		return &UnsupportedFeatureError{Feature: f, Required: required, Server: server}
+	}
+
+	return nil
+}
@@ -2,6 +2,8 @@ package api
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,44 +14,140 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // Client is the Paperless API client
 type Client struct {
-	baseURL    string
-	token      string
-	httpClient *http.Client
+	baseURL        string
+	token          string
+	httpClient     *http.Client
+	transferClient *http.Client
+
+	debugOut   io.Writer
+	debugTrace bool
+	tlsConfig  *tls.Config
+	cache      Cache
+	cacheTTL   time.Duration
+
+	mu               sync.Mutex
+	serverVersion    *Version
+	serverAPIVersion int
+
+	scope      DocumentScope
+	scopeOnce  sync.Once
+	scopeQuery url.Values
+	scopeErr   error
+}
+
+// maxIdleConnsPerHost keeps enough connections alive for parallel requests
+const maxIdleConnsPerHost = 32
+
+// Option configures optional Client behaviour
+type Option func(*Client)
+
+// WithDebug logs every HTTP request to w. With trace enabled, redacted
+// headers and text bodies are logged as well.
+func WithDebug(w io.Writer, trace bool) Option {
+	return func(c *Client) {
+		c.debugOut = w
+		c.debugTrace = trace
+	}
 }
 
 // NewClient creates a new API client
-func NewClient(baseURL, token string) *Client {
+func NewClient(baseURL, token string, opts ...Option) *Client {
 	// Ensure baseURL doesn't have trailing slash
 	baseURL = strings.TrimSuffix(baseURL, "/")
-	return &Client{
+	c := &Client{
 		baseURL: baseURL,
 		token:   token,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.httpClient.Transport = c.transport()
+	c.transferClient = &http.Client{Transport: c.httpClient.Transport}
+	return c
 }
 
-// request makes an authenticated request to the API
-func (c *Client) request(method, path string, body io.Reader, contentType string) (*http.Response, error) {
+// transport builds the RoundTripper chain for the configured options
+func (c *Client) transport() http.RoundTripper {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	// The default of 2 idle connections per host forces new TCP/TLS
+	// handshakes as soon as bulk operations run more than two requests at once
+	t.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	t.ResponseHeaderTimeout = 5 * time.Minute
+	if c.tlsConfig != nil {
+		t.TLSClientConfig = c.tlsConfig
+	}
+
+	var rt http.RoundTripper = t
+	if c.debugOut != nil {
+		rt = &debugTransport{next: rt, out: c.debugOut, trace: c.debugTrace}
+	}
+	return rt
+}
+
+// BaseURL returns the server URL the client talks to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// newRequest builds an authenticated request to the API
+func (c *Client) newRequest(method, path string, body io.Reader, contentType string) (*http.Request, error) {
 	url := c.baseURL + path
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Token "+c.token)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Token "+c.token)
+	}
 	if contentType != "" {
 		req.Header.Set("Content-Type", contentType)
 	}
-	req.Header.Set("Accept", "application/json; version=5")
+	req.Header.Set("Accept", fmt.Sprintf("application/json; version=%d", c.APIVersion()))
 
-	return c.httpClient.Do(req)
+	return req, nil
+}
+
+// do sends a request built by newRequest
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	return c.send(c.httpClient, req)
+}
+
+// doTransfer sends a request whose body or response may be large. Unlike
+// do it has no overall timeout, only a limit on waiting for the response.
+func (c *Client) doTransfer(req *http.Request) (*http.Response, error) {
+	return c.send(c.transferClient, req)
+}
+
+func (c *Client) send(hc *http.Client, req *http.Request) (*http.Response, error) {
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	c.recordServerVersion(resp)
+
+	return resp, nil
+}
+
+// request makes an authenticated request to the API
+func (c *Client) request(method, path string, body io.Reader, contentType string) (*http.Response, error) {
+	req, err := c.newRequest(method, path, body, contentType)
+	if err != nil {
+		return nil, err
+	}
+	if method != "GET" {
+		c.invalidateFor(path)
+	}
+	return c.do(req)
 }
 
 // get makes a GET request
@@ -105,6 +203,26 @@ type Document struct {
 	ArchiveSerialNumber *int      `json:"archive_serial_number"`
 	OriginalFileName    string    `json:"original_file_name"`
 	ArchivedFileName    string    `json:"archived_file_name"`
+	PageCount           *int      `json:"page_count,omitempty"`
+
+	CustomFields []CustomFieldInstance `json:"custom_fields,omitempty"`
+
+	// Owner is nil for documents without an owner. Permissions are only
+	// filled by single-object gets and lists with FullPerms.
+	Owner       *int               `json:"owner"`
+	Permissions *ObjectPermissions `json:"permissions,omitempty"`
+
+	// SearchHit is only set in full-text search results
+	SearchHit *SearchHit `json:"__search_hit__,omitempty"`
+}
+
+// SearchHit is how well a document matched a full-text query. The
+// highlights are HTML, with matches in <span class="match"> tags.
+type SearchHit struct {
+	Score          float64 `json:"score"`
+	Rank           int     `json:"rank"`
+	Highlights     string  `json:"highlights"`
+	NoteHighlights string  `json:"note_highlights"`
 }
 
 // Tag represents a Paperless tag
@@ -119,6 +237,9 @@ type Tag struct {
 	IsInsensitive  bool   `json:"is_insensitive"`
 	IsInboxTag     bool   `json:"is_inbox_tag"`
 	DocumentCount  int    `json:"document_count"`
+
+	Owner       *int               `json:"owner"`
+	Permissions *ObjectPermissions `json:"permissions,omitempty"`
 }
 
 // Correspondent represents a Paperless correspondent
@@ -131,6 +252,9 @@ type Correspondent struct {
 	IsInsensitive   bool   `json:"is_insensitive"`
 	DocumentCount   int    `json:"document_count"`
 	LastCorrespond  string `json:"last_correspondence"`
+
+	Owner       *int               `json:"owner"`
+	Permissions *ObjectPermissions `json:"permissions,omitempty"`
 }
 
 // DocumentType represents a Paperless document type
@@ -142,6 +266,9 @@ type DocumentType struct {
 	MatchingAlgo  int    `json:"matching_algorithm"`
 	IsInsensitive bool   `json:"is_insensitive"`
 	DocumentCount int    `json:"document_count"`
+
+	Owner       *int               `json:"owner"`
+	Permissions *ObjectPermissions `json:"permissions,omitempty"`
 }
 
 // Task represents a Paperless task
@@ -152,12 +279,56 @@ type Task struct {
 	DateCreated  string `json:"date_created"`
 	DateDone     string `json:"date_done"`
 	Type         string `json:"type"`
+	TaskName     string `json:"task_name,omitempty"`
 	Status       string `json:"status"`
 	Result       string `json:"result"`
 	Acknowledged bool   `json:"acknowledged"`
 	RelatedDoc   string `json:"related_document"`
 }
 
+// TaskListParams filters ListTasks
+type TaskListParams struct {
+	// Status is PENDING, STARTED, SUCCESS, FAILURE or REVOKED
+	Status string
+	Type   string
+	// Acknowledged filters by whether the task was dismissed in the web UI
+	Acknowledged *bool
+}
+
+// DocumentMetadata holds file level information about a document
+type DocumentMetadata struct {
+	OriginalChecksum     string          `json:"original_checksum"`
+	OriginalSize         int64           `json:"original_size"`
+	OriginalMimeType     string          `json:"original_mime_type"`
+	OriginalFileName     string          `json:"original_filename"`
+	MediaFileName        string          `json:"media_filename"`
+	HasArchiveVersion    bool            `json:"has_archive_version"`
+	OriginalMetadata     []MetadataEntry `json:"original_metadata"`
+	ArchiveChecksum      string          `json:"archive_checksum"`
+	ArchiveSize          int64           `json:"archive_size"`
+	ArchiveMediaFileName string          `json:"archive_media_filename"`
+	ArchiveMetadata      []MetadataEntry `json:"archive_metadata"`
+	Lang                 string          `json:"lang"`
+}
+
+// MetadataEntry is a single embedded file metadata value (e.g. XMP)
+type MetadataEntry struct {
+	Namespace string `json:"namespace"`
+	Prefix    string `json:"prefix"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+}
+
+// DocumentSuggestions holds the metadata Paperless' classifier and date
+// parser suggest for a document
+type DocumentSuggestions struct {
+	Correspondents []int    `json:"correspondents"`
+	Tags           []int    `json:"tags"`
+	DocumentTypes  []int    `json:"document_types"`
+	StoragePaths   []int    `json:"storage_paths"`
+	Dates          []string `json:"dates"`
+}
+
 // DocumentListParams contains parameters for listing documents
 type DocumentListParams struct {
 	Query         string
@@ -166,14 +337,56 @@ type DocumentListParams struct {
 	DocumentType  string
 	CreatedAfter  string
 	CreatedBefore string
-	Limit         int
-	Page          int
-	Ordering      string
+	StoragePath   string
+	// ASN matches one archive serial number exactly; ASNFrom and ASNTo
+	// limit it inclusively
+	ASN     *int
+	ASNFrom *int
+	ASNTo   *int
+	// AddedAfter and AddedBefore filter by the date of adding (YYYY-MM-DD)
+	AddedAfter  string
+	AddedBefore string
+	// OwnerID matches documents of this user, NoOwner those without owner
+	OwnerID *int
+	NoOwner bool
+	// Untagged, NoCorrespondent and NoDocumentType match documents lacking
+	// that metadata
+	Untagged        bool
+	NoCorrespondent bool
+	NoDocumentType  bool
+	Limit           int
+	Page            int
+	Ordering        string
+	// TagIDsAny matches documents with at least one of these tags
+	TagIDsAny []int
+	// TagIDsNone matches documents with none of these tags
+	TagIDsNone []int
+	// IDAfter matches documents with a higher ID, for resuming runs
+	// ordered by ID
+	IDAfter int
+	// FullPerms fills in the permissions of each document
+	FullPerms bool
+	// Filter holds further query parameters, such as those of a saved view
+	Filter url.Values
+	// TruncateContent cuts the content of each document short, which makes
+	// listings of long documents much faster
+	TruncateContent bool
+	// Fields limits the documents to these fields, e.g. "id" and "title"
+	Fields []string
 }
 
 // ListDocuments lists documents with optional filters
 func (c *Client) ListDocuments(params DocumentListParams) (*PaginatedResponse[Document], error) {
+	return c.ListDocumentsContext(context.Background(), params)
+}
+
+// ListDocumentsContext is ListDocuments with a context that can cancel the
+// request
+func (c *Client) ListDocumentsContext(ctx context.Context, params DocumentListParams) (*PaginatedResponse[Document], error) {
 	query := url.Values{}
+	for key, values := range params.Filter {
+		query[key] = append([]string(nil), values...)
+	}
 
 	if params.Query != "" {
 		query.Set("query", params.Query)
@@ -181,6 +394,12 @@ func (c *Client) ListDocuments(params DocumentListParams) (*PaginatedResponse[Do
 	for _, tag := range params.Tags {
 		query.Add("tags__name__iexact", tag)
 	}
+	if len(params.TagIDsAny) > 0 {
+		query.Set("tags__id__in", joinIDs(params.TagIDsAny))
+	}
+	if len(params.TagIDsNone) > 0 {
+		query.Set("tags__id__none", joinIDs(params.TagIDsNone))
+	}
 	if params.Correspondent != "" {
 		query.Set("correspondent__name__iexact", params.Correspondent)
 	}
@@ -193,6 +412,39 @@ func (c *Client) ListDocuments(params DocumentListParams) (*PaginatedResponse[Do
 	if params.CreatedBefore != "" {
 		query.Set("created__date__lt", params.CreatedBefore)
 	}
+	if params.StoragePath != "" {
+		query.Set("storage_path__name__iexact", params.StoragePath)
+	}
+	if params.ASN != nil {
+		query.Set("archive_serial_number", strconv.Itoa(*params.ASN))
+	}
+	if params.ASNFrom != nil {
+		query.Set("archive_serial_number__gte", strconv.Itoa(*params.ASNFrom))
+	}
+	if params.ASNTo != nil {
+		query.Set("archive_serial_number__lte", strconv.Itoa(*params.ASNTo))
+	}
+	if params.AddedAfter != "" {
+		query.Set("added__date__gt", params.AddedAfter)
+	}
+	if params.AddedBefore != "" {
+		query.Set("added__date__lt", params.AddedBefore)
+	}
+	if params.OwnerID != nil {
+		query.Set("owner__id", strconv.Itoa(*params.OwnerID))
+	}
+	if params.NoOwner {
+		query.Set("owner__isnull", "true")
+	}
+	if params.Untagged {
+		query.Set("is_tagged", "false")
+	}
+	if params.NoCorrespondent {
+		query.Set("correspondent__isnull", "true")
+	}
+	if params.NoDocumentType {
+		query.Set("document_type__isnull", "true")
+	}
 	if params.Limit > 0 {
 		query.Set("page_size", strconv.Itoa(params.Limit))
 	}
@@ -202,21 +454,39 @@ func (c *Client) ListDocuments(params DocumentListParams) (*PaginatedResponse[Do
 	if params.Ordering != "" {
 		query.Set("ordering", params.Ordering)
 	}
+	if params.IDAfter > 0 {
+		query.Set("id__gt", strconv.Itoa(params.IDAfter))
+	}
+	if params.FullPerms {
+		query.Set("full_perms", "true")
+	}
+	if params.TruncateContent {
+		query.Set("truncate_content", "true")
+	}
+	if len(params.Fields) > 0 {
+		query.Set("fields", strings.Join(params.Fields, ","))
+	}
+	if err := c.applyScope(query); err != nil {
+		return nil, err
+	}
 
 	path := "/api/documents/"
 	if len(query) > 0 {
 		path += "?" + query.Encode()
 	}
 
-	resp, err := c.get(path)
+	req, err := c.newRequest("GET", path, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError("", resp)
 	}
 
 	var result PaginatedResponse[Document]
@@ -229,18 +499,17 @@ func (c *Client) ListDocuments(params DocumentListParams) (*PaginatedResponse[Do
 
 // GetDocument gets a single document by ID
 func (c *Client) GetDocument(id int) (*Document, error) {
-	resp, err := c.get(fmt.Sprintf("/api/documents/%d/", id))
+	resp, err := c.get(fmt.Sprintf("/api/documents/%d/?full_perms=true", id))
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("document %d not found", id)
+		return nil, &DocumentNotFoundError{ID: id}
 	}
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError("", resp)
 	}
 
 	var doc Document
@@ -251,454 +520,608 @@ func (c *Client) GetDocument(id int) (*Document, error) {
 	return &doc, nil
 }
 
-// UploadDocument uploads a document file
-func (c *Client) UploadDocument(filePath string, title string, correspondent *int, docType *int, tags []int) (string, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return "", err
-	}
-	defer file.Close()
-
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+// DocumentURL returns the web UI page of a document
+func (c *Client) DocumentURL(id int) string {
+	return fmt.Sprintf("%s/documents/%d/details", c.baseURL, id)
+}
 
-	// Add the file
-	part, err := writer.CreateFormFile("document", filepath.Base(filePath))
+// GetDocumentSuggestions gets the suggested correspondents, tags, types,
+// storage paths and dates of a document
+func (c *Client) GetDocumentSuggestions(id int) (*DocumentSuggestions, error) {
+	resp, err := c.get(fmt.Sprintf("/api/documents/%d/suggestions/", id))
 	if err != nil {
-		return "", err
-	}
-	if _, err := io.Copy(part, file); err != nil {
-		return "", err
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	// Add optional fields
-	if title != "" {
-		writer.WriteField("title", title)
-	}
-	if correspondent != nil {
-		writer.WriteField("correspondent", strconv.Itoa(*correspondent))
-	}
-	if docType != nil {
-		writer.WriteField("document_type", strconv.Itoa(*docType))
-	}
-	for _, tag := range tags {
-		writer.WriteField("tags", strconv.Itoa(tag))
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &DocumentNotFoundError{ID: id}
 	}
-
-	writer.Close()
-
-	resp, err := c.request("POST", "/api/documents/post_document/", body, writer.FormDataContentType())
-	if err != nil {
-		return "", err
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("", resp)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
-		respBody, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("upload failed %d: %s", resp.StatusCode, string(respBody))
+	var suggestions DocumentSuggestions
+	if err := json.NewDecoder(resp.Body).Decode(&suggestions); err != nil {
+		return nil, err
 	}
 
-	// The response contains a task ID
-	var result string
-	respBody, _ := io.ReadAll(resp.Body)
-	// Response is just a task UUID string
-	result = strings.Trim(string(respBody), "\" \n")
-	return result, nil
+	return &suggestions, nil
 }
 
-// DownloadDocument downloads a document file
-func (c *Client) DownloadDocument(id int, original bool) ([]byte, string, error) {
-	path := fmt.Sprintf("/api/documents/%d/download/", id)
-	if original {
-		path += "?original=true"
-	}
-
-	resp, err := c.get(path)
+// GetDocumentMetadata gets checksums, sizes and embedded metadata of a document's files
+func (c *Client) GetDocumentMetadata(id int) (*DocumentMetadata, error) {
+	resp, err := c.get(fmt.Sprintf("/api/documents/%d/metadata/", id))
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &DocumentNotFoundError{ID: id}
+	}
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, "", fmt.Errorf("download failed %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError("", resp)
 	}
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, "", err
+	var meta DocumentMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+		return nil, err
 	}
 
-	// Extract filename from Content-Disposition header
-	filename := ""
-	if cd := resp.Header.Get("Content-Disposition"); cd != "" {
-		if idx := strings.Index(cd, "filename="); idx != -1 {
-			filename = strings.Trim(cd[idx+9:], "\"")
-		}
-	}
+	return &meta, nil
+}
 
-	return data, filename, nil
+// UploadParams holds the optional metadata sent along with an upload
+type UploadParams struct {
+	Title         string
+	Correspondent *int
+	DocumentType  *int
+	StoragePath   *int
+	Tags          []int
+	// Progress, if set, is called as the file is sent
+	Progress ProgressFunc
 }
 
-// UpdateDocument updates a document's metadata
-func (c *Client) UpdateDocument(id int, updates map[string]interface{}) (*Document, error) {
-	resp, err := c.patch(fmt.Sprintf("/api/documents/%d/", id), updates)
+// UploadDocument uploads a document file
+func (c *Client) UploadDocument(filePath string, title string, correspondent *int, docType *int, tags []int) (*UploadResult, error) {
+	return c.Upload(filePath, UploadParams{
+		Title:         title,
+		Correspondent: correspondent,
+		DocumentType:  docType,
+		Tags:          tags,
+	})
+}
+
+// Upload uploads a document file. The file is streamed from disk rather
+// than buffered, so large scans don't need to fit into memory.
+func (c *Client) Upload(filePath string, params UploadParams) (*UploadResult, error) {
+	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	defer file.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("update failed %d: %s", resp.StatusCode, string(body))
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
 	}
 
-	var doc Document
-	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+	fileName := filepath.Base(filePath)
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+
+	// Measure the multipart envelope so the request carries a Content-Length
+	// instead of being sent chunked
+	envelope := &countingWriter{}
+	if err := writeUploadBody(envelope, boundary, fileName, params, strings.NewReader("")); err != nil {
 		return nil, err
 	}
 
-	return &doc, nil
-}
+	pr, pw := io.Pipe()
+	go func() {
+		content := &progressReader{r: file, total: info.Size(), fn: params.Progress}
+		pw.CloseWithError(writeUploadBody(pw, boundary, fileName, params, content))
+	}()
 
-// DeleteDocument deletes a document
-func (c *Client) DeleteDocument(id int) error {
-	resp, err := c.delete(fmt.Sprintf("/api/documents/%d/", id))
+	req, err := c.newRequest("POST", "/api/documents/post_document/", pr, "multipart/form-data; boundary="+boundary)
 	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("delete failed %d: %s", resp.StatusCode, string(body))
+		pr.Close()
+		return nil, err
 	}
+	req.ContentLength = envelope.n + info.Size()
 
-	return nil
-}
-
-// ListTags lists all tags
-func (c *Client) ListTags() (*PaginatedResponse[Tag], error) {
-	resp, err := c.get("/api/tags/?page_size=1000")
+	resp, err := c.doTransfer(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
+		return nil, newAPIError("upload", resp)
 	}
 
-	var result PaginatedResponse[Tag]
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
 		return nil, err
 	}
+	return parseUploadResult(respBody)
+}
 
-	return &result, nil
+// UploadResult is the server's answer to an upload
+type UploadResult struct {
+	TaskID string `json:"task_id"`
+	// Warnings are notes such as a likely duplicate, which some versions
+	// return along with the task
+	Warnings []string `json:"warnings,omitempty"`
 }
 
-// GetTag gets a single tag by ID
-func (c *Client) GetTag(id int) (*Tag, error) {
-	resp, err := c.get(fmt.Sprintf("/api/tags/%d/", id))
-	if err != nil {
-		return nil, err
+// parseUploadResult reads the task of an upload from a bare UUID, a JSON
+// string or a JSON object such as {"task_id": ..., "warnings": [...]}
+func parseUploadResult(body []byte) (*UploadResult, error) {
+	body = bytes.TrimSpace(body)
+
+	var result UploadResult
+	var fields map[string]json.RawMessage
+	switch {
+	case json.Unmarshal(body, &result.TaskID) == nil:
+	case json.Unmarshal(body, &fields) == nil:
+		for _, key := range []string{"task_id", "task", "id"} {
+			if json.Unmarshal(fields[key], &result.TaskID) == nil && result.TaskID != "" {
+				break
+			}
+		}
+		for _, key := range []string{"warnings", "warning", "duplicates", "message"} {
+			result.Warnings = append(result.Warnings, jsonStrings(fields[key])...)
+		}
+	default:
+		result.TaskID = string(body)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("tag %d not found", id)
-	}
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	if result.TaskID == "" {
+		return nil, fmt.Errorf("upload response has no task ID: %s", body)
 	}
+	return &result, nil
+}
 
-	var tag Tag
-	if err := json.NewDecoder(resp.Body).Decode(&tag); err != nil {
-		return nil, err
+// jsonStrings reads a JSON string or list of strings
+func jsonStrings(raw json.RawMessage) []string {
+	var one string
+	if json.Unmarshal(raw, &one) == nil && one != "" {
+		return []string{one}
 	}
-
-	return &tag, nil
+	var many []string
+	json.Unmarshal(raw, &many)
+	return many
 }
 
-// CreateTag creates a new tag
-func (c *Client) CreateTag(name, color string) (*Tag, error) {
-	data := map[string]interface{}{
-		"name": name,
-	}
-	if color != "" {
-		data["color"] = color
+// writeUploadBody writes the multipart form for post_document to w
+func writeUploadBody(w io.Writer, boundary, fileName string, params UploadParams, content io.Reader) error {
+	writer := multipart.NewWriter(w)
+	if err := writer.SetBoundary(boundary); err != nil {
+		return err
 	}
 
-	resp, err := c.post("/api/tags/", data)
+	// Add the file
+	part, err := writer.CreateFormFile("document", fileName)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("create failed %d: %s", resp.StatusCode, string(body))
+	if _, err := io.Copy(part, content); err != nil {
+		return err
 	}
 
-	var tag Tag
-	if err := json.NewDecoder(resp.Body).Decode(&tag); err != nil {
-		return nil, err
+	// Add optional fields
+	if params.Title != "" {
+		writer.WriteField("title", params.Title)
+	}
+	if params.Correspondent != nil {
+		writer.WriteField("correspondent", strconv.Itoa(*params.Correspondent))
+	}
+	if params.DocumentType != nil {
+		writer.WriteField("document_type", strconv.Itoa(*params.DocumentType))
+	}
+	if params.StoragePath != nil {
+		writer.WriteField("storage_path", strconv.Itoa(*params.StoragePath))
+	}
+	for _, tag := range params.Tags {
+		writer.WriteField("tags", strconv.Itoa(tag))
 	}
 
-	return &tag, nil
+	return writer.Close()
 }
 
-// UpdateTag updates a tag
-func (c *Client) UpdateTag(id int, updates map[string]interface{}) (*Tag, error) {
-	resp, err := c.patch(fmt.Sprintf("/api/tags/%d/", id), updates)
+// DownloadDocument downloads a document file
+func (c *Client) DownloadDocument(id int, original bool) ([]byte, string, error) {
+	var buf bytes.Buffer
+	info, err := c.DownloadDocumentTo(context.Background(), id, &buf, DownloadOptions{Original: original})
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	defer resp.Body.Close()
+	return buf.Bytes(), info.Filename, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("update failed %d: %s", resp.StatusCode, string(body))
+// DownloadOptions controls DownloadDocumentTo
+type DownloadOptions struct {
+	// Original requests the original file instead of the archived PDF
+	Original bool
+	// Progress, if set, is called as the file is received
+	Progress ProgressFunc
+}
+
+// DownloadInfo describes a finished download
+type DownloadInfo struct {
+	Filename    string
+	ContentType string
+	// Size is the Content-Length announced by the server, -1 if unknown
+	Size    int64
+	Written int64
+}
+
+// DownloadDocumentTo streams a document file into w without buffering it in
+// memory. The request is cancelled when ctx is done.
+func (c *Client) DownloadDocumentTo(ctx context.Context, id int, w io.Writer, opts DownloadOptions) (*DownloadInfo, error) {
+	path := fmt.Sprintf("/api/documents/%d/download/", id)
+	if opts.Original {
+		path += "?original=true"
 	}
 
-	var tag Tag
-	if err := json.NewDecoder(resp.Body).Decode(&tag); err != nil {
+	req, err := c.newRequest("GET", path, nil, "")
+	if err != nil {
 		return nil, err
 	}
 
-	return &tag, nil
+	return c.transferTo(req.WithContext(ctx), w, "download", opts.Progress)
 }
 
-// DeleteTag deletes a tag
-func (c *Client) DeleteTag(id int) error {
-	resp, err := c.delete(fmt.Sprintf("/api/tags/%d/", id))
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("delete failed %d: %s", resp.StatusCode, string(body))
-	}
+// Content choices for BulkDownloadTo
+const (
+	BulkDownloadArchive   = "archive"
+	BulkDownloadOriginals = "originals"
+	BulkDownloadBoth      = "both"
+)
 
-	return nil
+// BulkDownloadOptions controls BulkDownloadTo
+type BulkDownloadOptions struct {
+	// Content is BulkDownloadArchive (the default), BulkDownloadOriginals or
+	// BulkDownloadBoth
+	Content string
+	// FollowFormatting names the files in the zip after the server's storage
+	// path formatting instead of their IDs
+	FollowFormatting bool
+	// Progress, if set, is called as the archive is received
+	Progress ProgressFunc
 }
 
-// ListCorrespondents lists all correspondents
-func (c *Client) ListCorrespondents() (*PaginatedResponse[Correspondent], error) {
-	resp, err := c.get("/api/correspondents/?page_size=1000")
+// BulkDownloadTo streams a zip archive of the given documents into w. The
+// request is cancelled when ctx is done.
+func (c *Client) BulkDownloadTo(ctx context.Context, ids []int, w io.Writer, opts BulkDownloadOptions) (*DownloadInfo, error) {
+	if opts.Content == "" {
+		opts.Content = BulkDownloadArchive
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"documents":         ids,
+		"content":           opts.Content,
+		"follow_formatting": opts.FollowFormatting,
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
-	}
-
-	var result PaginatedResponse[Correspondent]
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	req, err := c.newRequest("POST", "/api/documents/bulk_download/", bytes.NewReader(body), "application/json")
+	if err != nil {
 		return nil, err
 	}
 
-	return &result, nil
+	return c.transferTo(req.WithContext(ctx), w, "bulk download", opts.Progress)
 }
 
-// GetCorrespondent gets a single correspondent by ID
-func (c *Client) GetCorrespondent(id int) (*Correspondent, error) {
-	resp, err := c.get(fmt.Sprintf("/api/correspondents/%d/", id))
+// transferTo sends req and streams the response body into w
+func (c *Client) transferTo(req *http.Request, w io.Writer, op string, progress ProgressFunc) (*DownloadInfo, error) {
+	resp, err := c.doTransfer(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("correspondent %d not found", id)
-	}
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
-	}
-
-	var corr Correspondent
-	if err := json.NewDecoder(resp.Body).Decode(&corr); err != nil {
-		return nil, err
+		return nil, newAPIError(op, resp)
 	}
 
-	return &corr, nil
-}
-
-// CreateCorrespondent creates a new correspondent
-func (c *Client) CreateCorrespondent(name string) (*Correspondent, error) {
-	data := map[string]interface{}{
-		"name": name,
+	info := &DownloadInfo{
+		Filename:    contentDispositionFilename(resp.Header.Get("Content-Disposition")),
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        resp.ContentLength,
 	}
 
-	resp, err := c.post("/api/correspondents/", data)
+	body := &progressReader{r: resp.Body, total: resp.ContentLength, fn: progress}
+	info.Written, err = io.Copy(w, body)
 	if err != nil {
-		return nil, err
+		return info, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("create failed %d: %s", resp.StatusCode, string(body))
+	if info.Size >= 0 && info.Written != info.Size {
+		return info, fmt.Errorf("download incomplete: got %d of %d bytes", info.Written, info.Size)
 	}
 
-	var corr Correspondent
-	if err := json.NewDecoder(resp.Body).Decode(&corr); err != nil {
-		return nil, err
-	}
+	return info, nil
+}
 
-	return &corr, nil
+// contentDispositionFilename extracts the filename from a Content-Disposition header
+func contentDispositionFilename(cd string) string {
+	if idx := strings.Index(cd, "filename="); idx != -1 {
+		return strings.Trim(cd[idx+9:], "\"")
+	}
+	return ""
 }
 
-// UpdateCorrespondent updates a correspondent
-func (c *Client) UpdateCorrespondent(id int, updates map[string]interface{}) (*Correspondent, error) {
-	resp, err := c.patch(fmt.Sprintf("/api/correspondents/%d/", id), updates)
+// UpdateDocument updates a document's metadata
+func (c *Client) UpdateDocument(id int, updates map[string]interface{}) (*Document, error) {
+	resp, err := c.patch(fmt.Sprintf("/api/documents/%d/", id), updates)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("update failed %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError("update", resp)
 	}
 
-	var corr Correspondent
-	if err := json.NewDecoder(resp.Body).Decode(&corr); err != nil {
+	var doc Document
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
 		return nil, err
 	}
 
-	return &corr, nil
+	return &doc, nil
 }
 
-// DeleteCorrespondent deletes a correspondent
-func (c *Client) DeleteCorrespondent(id int) error {
-	resp, err := c.delete(fmt.Sprintf("/api/correspondents/%d/", id))
+// AddNote adds a note to a document
+func (c *Client) AddNote(id int, note string) error {
+	resp, err := c.post(fmt.Sprintf("/api/documents/%d/notes/", id), map[string]interface{}{
+		"note": note,
+	})
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("delete failed %d: %s", resp.StatusCode, string(body))
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return newAPIError("add note", resp)
 	}
 
 	return nil
 }
 
-// ListDocumentTypes lists all document types
-func (c *Client) ListDocumentTypes() (*PaginatedResponse[DocumentType], error) {
-	resp, err := c.get("/api/document_types/?page_size=1000")
+// DeleteDocument deletes a document
+func (c *Client) DeleteDocument(id int) error {
+	resp, err := c.delete(fmt.Sprintf("/api/documents/%d/", id))
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return newAPIError("delete", resp)
 	}
 
-	var result PaginatedResponse[DocumentType]
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
+	return nil
+}
 
-	return &result, nil
+func (c *Client) tags() resource[Tag] {
+	return resource[Tag]{client: c, path: "/api/tags/", name: "tag", cached: true,
+		nameOf: func(t Tag) string { return t.Name }}
 }
 
-// GetDocumentType gets a single document type by ID
-func (c *Client) GetDocumentType(id int) (*DocumentType, error) {
-	resp, err := c.get(fmt.Sprintf("/api/document_types/%d/", id))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+// ListTags lists tags, following all pages unless params.Page is set
+func (c *Client) ListTags(params ListParams) (*PaginatedResponse[Tag], error) {
+	return c.tags().list(params)
+}
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("document type %d not found", id)
+// GetTag gets a single tag by ID
+func (c *Client) GetTag(id int) (*Tag, error) {
+	return c.tags().get(id)
+}
+
+// CreateTag creates a new tag. Fields holds further properties such as
+// "match" or "is_inbox_tag".
+func (c *Client) CreateTag(name, color string, fields map[string]interface{}) (*Tag, error) {
+	data := map[string]interface{}{
+		"name": name,
 	}
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	for key, value := range fields {
+		data[key] = value
 	}
-
-	var dt DocumentType
-	if err := json.NewDecoder(resp.Body).Decode(&dt); err != nil {
-		return nil, err
+	if color != "" {
+		data["color"] = color
 	}
+	return c.tags().create(data)
+}
 
-	return &dt, nil
+// UpdateTag updates a tag
+func (c *Client) UpdateTag(id int, updates map[string]interface{}) (*Tag, error) {
+	return c.tags().update(id, updates)
 }
 
-// CreateDocumentType creates a new document type
-func (c *Client) CreateDocumentType(name string) (*DocumentType, error) {
-	data := map[string]interface{}{
-		"name": name,
-	}
+// DeleteTag deletes a tag
+func (c *Client) DeleteTag(id int) error {
+	return c.tags().delete(id)
+}
 
-	resp, err := c.post("/api/document_types/", data)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+// FindTagByName finds a tag by name
+func (c *Client) FindTagByName(name string) (*Tag, error) {
+	return c.tags().findByName(name)
+}
 
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("create failed %d: %s", resp.StatusCode, string(body))
-	}
+func (c *Client) correspondents() resource[Correspondent] {
+	return resource[Correspondent]{client: c, path: "/api/correspondents/", name: "correspondent", cached: true,
+		nameOf: func(corr Correspondent) string { return corr.Name }}
+}
 
-	var dt DocumentType
-	if err := json.NewDecoder(resp.Body).Decode(&dt); err != nil {
-		return nil, err
-	}
+// ListCorrespondents lists correspondents, following all pages unless
+// params.Page is set
+func (c *Client) ListCorrespondents(params ListParams) (*PaginatedResponse[Correspondent], error) {
+	return c.correspondents().list(params)
+}
 
-	return &dt, nil
+// GetCorrespondent gets a single correspondent by ID
+func (c *Client) GetCorrespondent(id int) (*Correspondent, error) {
+	return c.correspondents().get(id)
 }
 
-// UpdateDocumentType updates a document type
-func (c *Client) UpdateDocumentType(id int, updates map[string]interface{}) (*DocumentType, error) {
-	resp, err := c.patch(fmt.Sprintf("/api/document_types/%d/", id), updates)
-	if err != nil {
-		return nil, err
+// CreateCorrespondent creates a new correspondent. Fields holds further
+// properties such as "match" or "matching_algorithm".
+func (c *Client) CreateCorrespondent(name string, fields map[string]interface{}) (*Correspondent, error) {
+	data := map[string]interface{}{"name": name}
+	for key, value := range fields {
+		data[key] = value
 	}
-	defer resp.Body.Close()
+	return c.correspondents().create(data)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("update failed %d: %s", resp.StatusCode, string(body))
-	}
+// UpdateCorrespondent updates a correspondent
+func (c *Client) UpdateCorrespondent(id int, updates map[string]interface{}) (*Correspondent, error) {
+	return c.correspondents().update(id, updates)
+}
 
-	var dt DocumentType
-	if err := json.NewDecoder(resp.Body).Decode(&dt); err != nil {
-		return nil, err
+// DeleteCorrespondent deletes a correspondent
+func (c *Client) DeleteCorrespondent(id int) error {
+	return c.correspondents().delete(id)
+}
+
+// FindCorrespondentByName finds a correspondent by name
+func (c *Client) FindCorrespondentByName(name string) (*Correspondent, error) {
+	return c.correspondents().findByName(name)
+}
+
+func (c *Client) documentTypes() resource[DocumentType] {
+	return resource[DocumentType]{client: c, path: "/api/document_types/", name: "document type", cached: true,
+		nameOf: func(dt DocumentType) string { return dt.Name }}
+}
+
+// ListDocumentTypes lists document types, following all pages unless
+// params.Page is set
+func (c *Client) ListDocumentTypes(params ListParams) (*PaginatedResponse[DocumentType], error) {
+	return c.documentTypes().list(params)
+}
+
+// GetDocumentType gets a single document type by ID
+func (c *Client) GetDocumentType(id int) (*DocumentType, error) {
+	return c.documentTypes().get(id)
+}
+
+// CreateDocumentType creates a new document type. Fields holds further
+// properties such as "match" or "matching_algorithm".
+func (c *Client) CreateDocumentType(name string, fields map[string]interface{}) (*DocumentType, error) {
+	data := map[string]interface{}{"name": name}
+	for key, value := range fields {
+		data[key] = value
 	}
+	return c.documentTypes().create(data)
+}
 
-	return &dt, nil
+// UpdateDocumentType updates a document type
+func (c *Client) UpdateDocumentType(id int, updates map[string]interface{}) (*DocumentType, error) {
+	return c.documentTypes().update(id, updates)
 }
 
 // DeleteDocumentType deletes a document type
 func (c *Client) DeleteDocumentType(id int) error {
-	resp, err := c.delete(fmt.Sprintf("/api/document_types/%d/", id))
-	if err != nil {
-		return err
+	return c.documentTypes().delete(id)
+}
+
+// FindDocumentTypeByName finds a document type by name
+func (c *Client) FindDocumentTypeByName(name string) (*DocumentType, error) {
+	return c.documentTypes().findByName(name)
+}
+
+func (c *Client) storagePaths() resource[StoragePath] {
+	return resource[StoragePath]{client: c, path: "/api/storage_paths/", name: "storage path", cached: true,
+		nameOf: func(sp StoragePath) string { return sp.Name }}
+}
+
+// ListStoragePaths lists storage paths, following all pages unless
+// params.Page is set
+func (c *Client) ListStoragePaths(params ListParams) (*PaginatedResponse[StoragePath], error) {
+	return c.storagePaths().list(params)
+}
+
+// GetStoragePath gets a single storage path by ID
+func (c *Client) GetStoragePath(id int) (*StoragePath, error) {
+	return c.storagePaths().get(id)
+}
+
+// CreateStoragePath creates a new storage path. Fields holds further
+// properties such as "match" or "matching_algorithm".
+func (c *Client) CreateStoragePath(name, path string, fields map[string]interface{}) (*StoragePath, error) {
+	data := map[string]interface{}{"name": name, "path": path}
+	for key, value := range fields {
+		data[key] = value
 	}
-	defer resp.Body.Close()
+	return c.storagePaths().create(data)
+}
 
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("delete failed %d: %s", resp.StatusCode, string(body))
+// UpdateStoragePath updates a storage path
+func (c *Client) UpdateStoragePath(id int, updates map[string]interface{}) (*StoragePath, error) {
+	return c.storagePaths().update(id, updates)
+}
+
+// DeleteStoragePath deletes a storage path
+func (c *Client) DeleteStoragePath(id int) error {
+	return c.storagePaths().delete(id)
+}
+
+// FindStoragePathByName finds a storage path by name
+func (c *Client) FindStoragePathByName(name string) (*StoragePath, error) {
+	return c.storagePaths().findByName(name)
+}
+
+func (c *Client) savedViews() resource[SavedView] {
+	return resource[SavedView]{client: c, path: "/api/saved_views/", name: "saved view",
+		nameOf: func(sv SavedView) string { return sv.Name }}
+}
+
+// ListSavedViews lists saved views, following all pages unless params.Page
+// is set
+func (c *Client) ListSavedViews(params ListParams) (*PaginatedResponse[SavedView], error) {
+	return c.savedViews().list(params)
+}
+
+// GetSavedView gets a single saved view by ID
+func (c *Client) GetSavedView(id int) (*SavedView, error) {
+	return c.savedViews().get(id)
+}
+
+// CreateSavedView creates a saved view showing the documents that match
+// all rules. Fields holds further properties such as "sort_field" or
+// "show_on_dashboard".
+func (c *Client) CreateSavedView(name string, rules []FilterRule, fields map[string]interface{}) (*SavedView, error) {
+	if rules == nil {
+		rules = []FilterRule{}
 	}
+	data := map[string]interface{}{
+		"name":              name,
+		"filter_rules":      rules,
+		"show_on_dashboard": false,
+		"show_in_sidebar":   false,
+	}
+	for key, value := range fields {
+		data[key] = value
+	}
+	return c.savedViews().create(data)
+}
 
-	return nil
+// UpdateSavedView updates a saved view. Updating "filter_rules" replaces
+// all rules.
+func (c *Client) UpdateSavedView(id int, updates map[string]interface{}) (*SavedView, error) {
+	return c.savedViews().update(id, updates)
+}
+
+// DeleteSavedView deletes a saved view
+func (c *Client) DeleteSavedView(id int) error {
+	return c.savedViews().delete(id)
+}
+
+// FindSavedViewByName finds a saved view by name
+func (c *Client) FindSavedViewByName(name string) (*SavedView, error) {
+	return c.savedViews().findByName(name)
 }
 
 // GetTask gets a task by ID
@@ -710,8 +1133,7 @@ func (c *Client) GetTask(taskID string) (*Task, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError("", resp)
 	}
 
 	var tasks []Task
@@ -726,46 +1148,40 @@ func (c *Client) GetTask(taskID string) (*Task, error) {
 	return &tasks[0], nil
 }
 
-// FindTagByName finds a tag by name
-func (c *Client) FindTagByName(name string) (*Tag, error) {
-	tags, err := c.ListTags()
-	if err != nil {
-		return nil, err
+// ListTasks lists background tasks, newest first
+func (c *Client) ListTasks(params TaskListParams) ([]Task, error) {
+	query := url.Values{}
+	if params.Status != "" {
+		query.Set("status", params.Status)
 	}
-	for _, tag := range tags.Results {
-		if strings.EqualFold(tag.Name, name) {
-			return &tag, nil
-		}
+	if params.Type != "" {
+		query.Set("type", params.Type)
+	}
+	if params.Acknowledged != nil {
+		query.Set("acknowledged", strconv.FormatBool(*params.Acknowledged))
 	}
-	return nil, fmt.Errorf("tag not found: %s", name)
-}
 
-// FindCorrespondentByName finds a correspondent by name
-func (c *Client) FindCorrespondentByName(name string) (*Correspondent, error) {
-	corrs, err := c.ListCorrespondents()
+	path := "/api/tasks/"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := c.get(path)
 	if err != nil {
 		return nil, err
 	}
-	for _, corr := range corrs.Results {
-		if strings.EqualFold(corr.Name, name) {
-			return &corr, nil
-		}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("", resp)
 	}
-	return nil, fmt.Errorf("correspondent not found: %s", name)
-}
 
-// FindDocumentTypeByName finds a document type by name
-func (c *Client) FindDocumentTypeByName(name string) (*DocumentType, error) {
-	types, err := c.ListDocumentTypes()
-	if err != nil {
+	var tasks []Task
+	if err := json.NewDecoder(resp.Body).Decode(&tasks); err != nil {
 		return nil, err
 	}
-	for _, dt := range types.Results {
-		if strings.EqualFold(dt.Name, name) {
-			return &dt, nil
-		}
-	}
-	return nil, fmt.Errorf("document type not found: %s", name)
+
+	return tasks, nil
 }
 
 // StoragePath represents a Paperless storage path
@@ -778,6 +1194,9 @@ type StoragePath struct {
 	MatchingAlgo  int    `json:"matching_algorithm"`
 	IsInsensitive bool   `json:"is_insensitive"`
 	DocumentCount int    `json:"document_count"`
+
+	Owner       *int               `json:"owner"`
+	Permissions *ObjectPermissions `json:"permissions,omitempty"`
 }
 
 // SavedView represents a Paperless saved view
@@ -788,7 +1207,7 @@ type SavedView struct {
 	ShowInSidebar      bool   `json:"show_in_sidebar"`
 	SortField          string `json:"sort_field"`
 	SortReverse        bool   `json:"sort_reverse"`
-	FilterRules        []any  `json:"filter_rules"`
+	FilterRules        []FilterRule `json:"filter_rules"`
 }
 
 // GlobalSearchResult represents results from global search
@@ -799,109 +1218,26 @@ type GlobalSearchResult struct {
 	DocumentTypes  []DocumentType  `json:"document_types"`
 	StoragePaths   []StoragePath   `json:"storage_paths"`
 	Tags           []Tag           `json:"tags"`
+	CustomFields   []CustomField   `json:"custom_fields"`
 }
 
-// ListStoragePaths lists all storage paths
-func (c *Client) ListStoragePaths() (*PaginatedResponse[StoragePath], error) {
-	resp, err := c.get("/api/storage_paths/?page_size=1000")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
-	}
-
-	var result PaginatedResponse[StoragePath]
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
-
-	return &result, nil
-}
-
-// GetStoragePath gets a single storage path by ID
-func (c *Client) GetStoragePath(id int) (*StoragePath, error) {
-	resp, err := c.get(fmt.Sprintf("/api/storage_paths/%d/", id))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("storage path %d not found", id)
-	}
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
-	}
-
-	var sp StoragePath
-	if err := json.NewDecoder(resp.Body).Decode(&sp); err != nil {
-		return nil, err
-	}
-
-	return &sp, nil
-}
-
-// CreateStoragePath creates a new storage path
-func (c *Client) CreateStoragePath(name, path string) (*StoragePath, error) {
-	data := map[string]interface{}{
-		"name": name,
-		"path": path,
-	}
-
-	resp, err := c.post("/api/storage_paths/", data)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("create failed %d: %s", resp.StatusCode, string(body))
-	}
-
-	var sp StoragePath
-	if err := json.NewDecoder(resp.Body).Decode(&sp); err != nil {
+// GlobalSearch performs a global search across all objects
+func (c *Client) GlobalSearch(query string) (*GlobalSearchResult, error) {
+	if err := c.RequireFeature(FeatureGlobalSearch); err != nil {
 		return nil, err
 	}
 
-	return &sp, nil
-}
-
-// DeleteStoragePath deletes a storage path
-func (c *Client) DeleteStoragePath(id int) error {
-	resp, err := c.delete(fmt.Sprintf("/api/storage_paths/%d/", id))
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("delete failed %d: %s", resp.StatusCode, string(body))
-	}
-
-	return nil
-}
-
-// ListSavedViews lists all saved views
-func (c *Client) ListSavedViews() (*PaginatedResponse[SavedView], error) {
-	resp, err := c.get("/api/saved_views/?page_size=1000")
+	resp, err := c.get(fmt.Sprintf("/api/search/?query=%s", url.QueryEscape(query)))
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError("", resp)
 	}
 
-	var result PaginatedResponse[SavedView]
+	var result GlobalSearchResult
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
@@ -909,67 +1245,71 @@ func (c *Client) ListSavedViews() (*PaginatedResponse[SavedView], error) {
 	return &result, nil
 }
 
-// GetSavedView gets a single saved view by ID
-func (c *Client) GetSavedView(id int) (*SavedView, error) {
-	resp, err := c.get(fmt.Sprintf("/api/saved_views/%d/", id))
+// GetNextASN returns the next free archive serial number, one above the
+// highest in use
+func (c *Client) GetNextASN() (int, error) {
+	resp, err := c.get("/api/documents/next_asn/")
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("saved view %d not found", id)
-	}
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return 0, newAPIError("", resp)
 	}
 
-	var sv SavedView
-	if err := json.NewDecoder(resp.Body).Decode(&sv); err != nil {
-		return nil, err
+	var asn int
+	if err := json.NewDecoder(resp.Body).Decode(&asn); err != nil {
+		return 0, err
 	}
 
-	return &sv, nil
+	return asn, nil
 }
 
-// GlobalSearch performs a global search across all objects
-func (c *Client) GlobalSearch(query string) (*GlobalSearchResult, error) {
-	resp, err := c.get(fmt.Sprintf("/api/search/?query=%s", url.QueryEscape(query)))
+// SearchAutocomplete returns words of the search index starting with prefix,
+// most common first, as the web UI's search field suggests them
+func (c *Client) SearchAutocomplete(prefix string, limit int) ([]string, error) {
+	query := url.Values{"term": {prefix}}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	resp, err := c.get("/api/search/autocomplete/?" + query.Encode())
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError("", resp)
 	}
 
-	var result GlobalSearchResult
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	var words []string
+	if err := json.NewDecoder(resp.Body).Decode(&words); err != nil {
 		return nil, err
 	}
 
-	return &result, nil
+	return words, nil
 }
 
 // GetSimilarDocuments finds documents similar to the given one
 func (c *Client) GetSimilarDocuments(docID int, limit int) (*PaginatedResponse[Document], error) {
-	path := fmt.Sprintf("/api/documents/?more_like_id=%d", docID)
+	query := url.Values{"more_like_id": {strconv.Itoa(docID)}}
 	if limit > 0 {
-		path += fmt.Sprintf("&page_size=%d", limit)
+		query.Set("page_size", strconv.Itoa(limit))
+	}
+	if err := c.applyScope(query); err != nil {
+		return nil, err
 	}
 
-	resp, err := c.get(path)
+	resp, err := c.get("/api/documents/?" + query.Encode())
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError("", resp)
 	}
 
 	var result PaginatedResponse[Document]
@@ -989,8 +1329,7 @@ func (c *Client) GetDocumentPreview(id int) ([]byte, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("preview failed %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError("preview", resp)
 	}
 
 	return io.ReadAll(resp.Body)
@@ -1005,8 +1344,7 @@ func (c *Client) GetDocumentThumb(id int) ([]byte, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("thumbnail failed %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError("thumbnail", resp)
 	}
 
 	return io.ReadAll(resp.Body)
@@ -1021,8 +1359,7 @@ func (c *Client) GetStatistics() (map[string]any, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, newAPIError("", resp)
 	}
 
 	var result map[string]any
@@ -1033,16 +1370,18 @@ func (c *Client) GetStatistics() (map[string]any, error) {
 	return result, nil
 }
 
-// FindStoragePathByName finds a storage path by name
-func (c *Client) FindStoragePathByName(name string) (*StoragePath, error) {
-	paths, err := c.ListStoragePaths()
+// CheckAccess performs a GET on the given API path and reports whether the
+// token is allowed to read it
+func (c *Client) CheckAccess(path string) error {
+	resp, err := c.get(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	for _, sp := range paths.Results {
-		if strings.EqualFold(sp.Name, name) {
-			return &sp, nil
-		}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError("", resp)
 	}
-	return nil, fmt.Errorf("storage path not found: %s", name)
+
+	return nil
 }
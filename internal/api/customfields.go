@@ -0,0 +1,372 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Custom field data types as reported by the API
+const (
+	FieldString   = "string"
+	FieldURL      = "url"
+	FieldDate     = "date"
+	FieldBoolean  = "boolean"
+	FieldInteger  = "integer"
+	FieldFloat    = "float"
+	FieldMonetary = "monetary"
+	FieldDocLink  = "documentlink"
+	FieldSelect   = "select"
+	FieldLongText = "longtext"
+)
+
+// FieldTypes lists the data types a custom field can be created with
+var FieldTypes = []string{
+	FieldString, FieldURL, FieldDate, FieldBoolean, FieldInteger,
+	FieldFloat, FieldMonetary, FieldDocLink, FieldSelect, FieldLongText,
+}
+
+// CustomField represents a custom field definition
+type CustomField struct {
+	ID        int            `json:"id"`
+	Name      string         `json:"name"`
+	DataType  string         `json:"data_type"`
+	ExtraData map[string]any `json:"extra_data,omitempty"`
+	// DocumentCount is only reported by newer servers
+	DocumentCount int `json:"document_count,omitempty"`
+}
+
+// CustomFieldInstance is the value of a custom field on a document
+type CustomFieldInstance struct {
+	Field int `json:"field"`
+	Value any `json:"value"`
+}
+
+func (c *Client) customFields() resource[CustomField] {
+	return resource[CustomField]{client: c, path: "/api/custom_fields/", name: "custom field", cached: true,
+		nameOf: func(f CustomField) string { return f.Name }}
+}
+
+// ListCustomFields lists custom field definitions, following all pages
+// unless params.Page is set
+func (c *Client) ListCustomFields(params ListParams) (*PaginatedResponse[CustomField], error) {
+	if err := c.RequireFeature(FeatureCustomFields); err != nil {
+		return nil, err
+	}
+	return c.customFields().list(params)
+}
+
+// GetCustomField gets a custom field definition by ID
+func (c *Client) GetCustomField(id int) (*CustomField, error) {
+	if err := c.RequireFeature(FeatureCustomFields); err != nil {
+		return nil, err
+	}
+	return c.customFields().get(id)
+}
+
+// CreateCustomField creates a custom field. extraData holds type specific
+// settings such as select_options or default_currency and may be nil.
+func (c *Client) CreateCustomField(name, dataType string, extraData map[string]any) (*CustomField, error) {
+	if err := c.RequireFeature(FeatureCustomFields); err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{
+		"name":      name,
+		"data_type": dataType,
+	}
+	if extraData != nil {
+		data["extra_data"] = extraData
+	}
+	return c.customFields().create(data)
+}
+
+// UpdateCustomField updates a custom field definition
+func (c *Client) UpdateCustomField(id int, updates map[string]interface{}) (*CustomField, error) {
+	return c.customFields().update(id, updates)
+}
+
+// DeleteCustomField deletes a custom field and its values on all documents
+func (c *Client) DeleteCustomField(id int) error {
+	return c.customFields().delete(id)
+}
+
+// FindCustomFieldByName finds a custom field by name
+func (c *Client) FindCustomFieldByName(name string) (*CustomField, error) {
+	if err := c.RequireFeature(FeatureCustomFields); err != nil {
+		return nil, err
+	}
+	return c.customFields().findByName(name)
+}
+
+// SetCustomFields sets custom field values on a document, keyed by field
+// ID. Fields already on the document that are not in values are kept.
+func (c *Client) SetCustomFields(id int, values map[int]any) (*Document, error) {
+	doc, err := c.GetDocument(id)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := MergeCustomFields(doc.CustomFields, values)
+	return c.UpdateDocument(id, map[string]interface{}{"custom_fields": fields})
+}
+
+// MergeCustomFields returns existing with the fields in values replaced or
+// added, in field ID order for new fields
+func MergeCustomFields(existing []CustomFieldInstance, values map[int]any) []CustomFieldInstance {
+	fields := make([]CustomFieldInstance, 0, len(existing)+len(values))
+	for _, f := range existing {
+		if _, ok := values[f.Field]; !ok {
+			fields = append(fields, f)
+		}
+	}
+
+	ids := make([]int, 0, len(values))
+	for id := range values {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		fields = append(fields, CustomFieldInstance{Field: id, Value: values[id]})
+	}
+
+	return fields
+}
+
+// maxStringFieldLength is the server side limit for string fields
+const maxStringFieldLength = 128
+
+// monetaryRe is compiled on first use to keep it out of startup
+var monetaryRe = sync.OnceValue(func() *regexp.Regexp {
+	return regexp.MustCompile(`^([A-Za-z]{3})?\s*(-?\d+(?:[.,]\d{1,2})?)\s*([A-Za-z]{3})?$`)
+})
+
+// ParseValue converts a command line value to what the API expects for the
+// field's data type. An empty string clears the field.
+func (f *CustomField) ParseValue(s string) (any, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	invalid := func(expected string) error {
+		return fmt.Errorf("invalid value %q for %s field %q: expected %s", s, f.DataType, f.Name, expected)
+	}
+
+	switch f.DataType {
+	case FieldString:
+		if len(s) > maxStringFieldLength {
+			return nil, invalid(fmt.Sprintf("at most %d characters", maxStringFieldLength))
+		}
+		return s, nil
+
+	case FieldLongText:
+		return s, nil
+
+	case FieldURL:
+		u, err := url.Parse(s)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, invalid("an absolute URL like https://example.com")
+		}
+		return s, nil
+
+	case FieldDate:
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return nil, invalid("a date as YYYY-MM-DD")
+		}
+		return t.Format("2006-01-02"), nil
+
+	case FieldBoolean:
+		switch strings.ToLower(s) {
+		case "true", "yes", "y", "1", "on":
+			return true, nil
+		case "false", "no", "n", "0", "off":
+			return false, nil
+		}
+		return nil, invalid("true or false")
+
+	case FieldInteger:
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, invalid("a whole number")
+		}
+		return v, nil
+
+	case FieldFloat:
+		v, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+		if err != nil {
+			return nil, invalid("a number")
+		}
+		return v, nil
+
+	case FieldMonetary:
+		m := monetaryRe().FindStringSubmatch(s)
+		if m == nil || (m[1] != "" && m[3] != "") {
+			return nil, invalid("an amount with optional currency, e.g. EUR12.50 or 12.50")
+		}
+		amount, _ := strconv.ParseFloat(strings.Replace(m[2], ",", ".", 1), 64)
+		return strings.ToUpper(m[1]+m[3]) + strconv.FormatFloat(amount, 'f', 2, 64), nil
+
+	case FieldDocLink:
+		var ids []int
+		for _, part := range strings.Split(s, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return nil, invalid("comma separated document IDs")
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+
+	case FieldSelect:
+		return f.parseSelect(s)
+	}
+
+	// Unknown types from newer servers are passed through unchanged
+	return s, nil
+}
+
+// parseSelect matches s against the field's options by label or ID
+func (f *CustomField) parseSelect(s string) (any, error) {
+	options := f.SelectOptions()
+
+	labels := make([]string, len(options))
+	for i, opt := range options {
+		if strings.EqualFold(opt.Label, s) || opt.ID == s {
+			return f.SelectOptionValue(i), nil
+		}
+		labels[i] = opt.Label
+	}
+
+	return nil, fmt.Errorf("invalid value %q for select field %q: expected one of %s", s, f.Name, strings.Join(labels, ", "))
+}
+
+// SelectOption is one choice of a select field. Servers before 2.14 store
+// options as plain labels; their ID is the option's position.
+type SelectOption struct {
+	ID    string `json:"id,omitempty"`
+	Label string `json:"label"`
+}
+
+// SelectOptions returns the choices of a select field
+func (f *CustomField) SelectOptions() []SelectOption {
+	raw, _ := f.ExtraData["select_options"].([]any)
+
+	options := make([]SelectOption, 0, len(raw))
+	for i, opt := range raw {
+		switch o := opt.(type) {
+		case string:
+			options = append(options, SelectOption{ID: strconv.Itoa(i), Label: o})
+		case map[string]any:
+			id, _ := o["id"].(string)
+			label, _ := o["label"].(string)
+			options = append(options, SelectOption{ID: id, Label: label})
+		}
+	}
+	return options
+}
+
+// LegacySelectOptions reports whether the field stores its options as plain
+// labels, with documents referring to them by position
+func (f *CustomField) LegacySelectOptions() bool {
+	raw, _ := f.ExtraData["select_options"].([]any)
+	for _, opt := range raw {
+		if _, ok := opt.(string); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatValue renders a stored value for display, showing select options
+// by label
+func (f *CustomField) FormatValue(value any) string {
+	if value == nil {
+		return ""
+	}
+	if f.DataType == FieldSelect {
+		for i, opt := range f.SelectOptions() {
+			if fmt.Sprint(f.SelectOptionValue(i)) == fmt.Sprint(value) {
+				return opt.Label
+			}
+		}
+	}
+	if ids, ok := value.([]any); ok {
+		parts := make([]string, len(ids))
+		for i, id := range ids {
+			parts[i] = fmt.Sprint(id)
+		}
+		return strings.Join(parts, ", ")
+	}
+	return fmt.Sprint(value)
+}
+
+// SelectOptionValue returns the value documents store for the i-th option:
+// its ID, or its position for legacy options
+func (f *CustomField) SelectOptionValue(i int) any {
+	if f.LegacySelectOptions() {
+		return i
+	}
+	return f.SelectOptions()[i].ID
+}
+
+// SetSelectOptions replaces the choices of a select field. Options without
+// an ID are assigned one by the server.
+func (c *Client) SetSelectOptions(field *CustomField, options []SelectOption) (*CustomField, error) {
+	legacy := field.LegacySelectOptions() ||
+		(len(field.SelectOptions()) == 0 && !c.Supports(FeatureSelectOptionIDs))
+
+	extra := make(map[string]any, len(field.ExtraData)+1)
+	for k, v := range field.ExtraData {
+		extra[k] = v
+	}
+	if legacy {
+		labels := make([]string, len(options))
+		for i, opt := range options {
+			labels[i] = opt.Label
+		}
+		extra["select_options"] = labels
+	} else {
+		extra["select_options"] = options
+	}
+
+	return c.customFields().update(field.ID, map[string]interface{}{"extra_data": extra})
+}
+
+// DocumentsWithFieldValue returns the IDs of all documents whose custom
+// field equals value
+func (c *Client) DocumentsWithFieldValue(fieldID int, value any) ([]int, error) {
+	if err := c.RequireFeature(FeatureCustomFieldQuery); err != nil {
+		return nil, err
+	}
+
+	query, err := json.Marshal([]any{fieldID, "exact", value})
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{"custom_field_query": {string(query)}, "page_size": {"1"}}
+
+	resp, err := c.get("/api/documents/?" + params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("", resp)
+	}
+
+	var result PaginatedResponse[Document]
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result.All, nil
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxDebugBody caps how much of a request or response body is logged
+const maxDebugBody = 4096
+
+// redactedHeaders are never written to the debug log in clear text
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+	"X-Csrftoken":   true,
+}
+
+// sensitiveJSON matches the string values of JSON keys holding credentials,
+// like "password" or "token", also when the body is cut off inside the value
+var sensitiveJSON = regexp.MustCompile(`("[^"]*(?i:password|token|secret)[^"]*"\s*:\s*)"(?:[^"\\]|\\.)*"?`)
+
+// debugTransport is a RoundTripper that logs every request to out. With trace
+// enabled, headers and the start of text bodies are logged too, with
+// credentials redacted.
+type debugTransport struct {
+	next  http.RoundTripper
+	out   io.Writer
+	trace bool
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.trace {
+		fmt.Fprintf(t.out, "> %s %s\n", req.Method, req.URL.Redacted())
+		t.writeHeaders(">", req.Header)
+		if req.GetBody != nil && isTextContent(req.Header.Get("Content-Type")) {
+			if body, err := req.GetBody(); err == nil {
+				data, _ := io.ReadAll(io.LimitReader(body, maxDebugBody+1))
+				body.Close()
+				t.writeBody(">", data)
+			}
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	if err != nil {
+		fmt.Fprintf(t.out, "[debug] %s %s -> error after %s: %v\n", req.Method, req.URL.Redacted(), elapsed, err)
+		return nil, err
+	}
+
+	fmt.Fprintf(t.out, "[debug] %s %s -> %d (%s)\n", req.Method, req.URL.Redacted(), resp.StatusCode, elapsed)
+
+	if t.trace {
+		t.writeHeaders("<", resp.Header)
+		if isTextContent(resp.Header.Get("Content-Type")) {
+			data, _ := io.ReadAll(io.LimitReader(resp.Body, maxDebugBody+1))
+			t.writeBody("<", data)
+			// Hand the consumed prefix back to the caller
+			resp.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+		}
+	}
+
+	return resp, nil
+}
+
+func (t *debugTransport) writeHeaders(prefix string, h http.Header) {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		value := strings.Join(h[k], ", ")
+		if redactedHeaders[http.CanonicalHeaderKey(k)] {
+			value = "[REDACTED]"
+		}
+		fmt.Fprintf(t.out, "%s %s: %s\n", prefix, k, value)
+	}
+}
+
+func (t *debugTransport) writeBody(prefix string, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	data = sensitiveJSON.ReplaceAll(data, []byte(`${1}"[REDACTED]"`))
+	suffix := ""
+	if len(data) > maxDebugBody {
+		data = data[:maxDebugBody]
+		suffix = " [truncated]"
+	}
+	fmt.Fprintf(t.out, "%s %s%s\n", prefix, data, suffix)
+}
+
+// isTextContent reports whether a body of this content type is worth logging
+func isTextContent(contentType string) bool {
+	return strings.HasPrefix(contentType, "application/json") || strings.HasPrefix(contentType, "text/")
+}
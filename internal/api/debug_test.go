@@ -0,0 +1,90 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// roundTripFunc answers requests without a server
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTraceRedactsCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  string
+		response string
+		hidden   []string
+		shown    []string
+	}{
+		{
+			name:     "login",
+			request:  `{"username":"alice","password":"hunter2"}`,
+			response: `{"token":"0123456789abcdef"}`,
+			hidden:   []string{"hunter2", "0123456789abcdef"},
+			shown:    []string{`"username":"alice"`, `"password":"[REDACTED]"`, `"token":"[REDACTED]"`},
+		},
+		{
+			name:     "escaped quotes and spacing",
+			request:  `{"new_password" : "a\"b c", "email": "a@example.com"}`,
+			response: `{"id": 3}`,
+			hidden:   []string{`a\"b c`},
+			shown:    []string{`"new_password" : "[REDACTED]"`, `"email": "a@example.com"`},
+		},
+		{
+			name:     "other keys",
+			request:  `{"client_secret":"s3cret","API_TOKEN":"t0ken","title":"secret plans"}`,
+			response: `{}`,
+			hidden:   []string{"s3cret", "t0ken"},
+			shown:    []string{`"title":"secret plans"`},
+		},
+		{
+			name:     "value cut off",
+			request:  `{"name":"x"}`,
+			response: `{"token":"` + strings.Repeat("z", maxDebugBody),
+			hidden:   []string{"zzz"},
+			shown:    []string{`"token":"[REDACTED]"`},
+		},
+	}
+
+	for _, tt := range tests {
+		var log bytes.Buffer
+		transport := &debugTransport{out: &log, trace: true, next: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(tt.response)),
+			}, nil
+		})}
+
+		req, _ := http.NewRequest(http.MethodPost, "http://paperless.test/api/token/", strings.NewReader(tt.request))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		// The caller still gets the body unredacted
+		body, _ := io.ReadAll(resp.Body)
+		if string(body) != tt.response {
+			t.Errorf("%s: body = %q, want %q", tt.name, body, tt.response)
+		}
+
+		for _, s := range tt.hidden {
+			if strings.Contains(log.String(), s) {
+				t.Errorf("%s: log contains %q:\n%s", tt.name, s, log.String())
+			}
+		}
+		for _, s := range tt.shown {
+			if !strings.Contains(log.String(), s) {
+				t.Errorf("%s: log lacks %q:\n%s", tt.name, s, log.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// APIError is returned when the server responds with an unexpected status code
+type APIError struct {
+	Op         string
+	StatusCode int
+	Body       string
+
+	// FieldName names a field in validation messages, e.g. by the flag
+	// that set it. Fields keep their API names if it is nil.
+	FieldName func(field string) string
+}
+
+func (e *APIError) Error() string {
+	body := " " + e.Body
+	if v := e.Validation(); v != nil {
+		body = "\n" + v.Format(e.FieldName)
+	}
+	if e.Op == "" {
+		return fmt.Sprintf("API error %d:%s", e.StatusCode, body)
+	}
+	return fmt.Sprintf("%s failed %d:%s", e.Op, e.StatusCode, body)
+}
+
+// nonFieldErrors is the key of validation messages not about one field
+const nonFieldErrors = "non_field_errors"
+
+// ValidationErrors are the messages of a rejected request by field
+type ValidationErrors map[string][]string
+
+// Validation parses the body of a 400 response like
+// {"name": ["This field is required."]}. It returns nil for other errors.
+func (e *APIError) Validation() ValidationErrors {
+	if e.StatusCode != http.StatusBadRequest {
+		return nil
+	}
+	var body map[string]any
+	if err := json.Unmarshal([]byte(e.Body), &body); err != nil || len(body) == 0 {
+		return nil
+	}
+
+	v := make(ValidationErrors)
+	for field, value := range body {
+		if field == "detail" {
+			field = nonFieldErrors
+		}
+		if !v.collect(field, "", value) {
+			return nil
+		}
+	}
+	return v
+}
+
+// collect adds the messages in value, which may be nested in lists and
+// objects like those of custom fields, to the field's messages. It reports
+// false if value holds anything but messages.
+func (v ValidationErrors) collect(field, prefix string, value any) bool {
+	switch value := value.(type) {
+	case string:
+		v[field] = append(v[field], prefix+value)
+	case []any:
+		for _, item := range value {
+			if !v.collect(field, prefix, item) {
+				return false
+			}
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			inner := prefix
+			if key != nonFieldErrors {
+				inner += key + ": "
+			}
+			if !v.collect(field, inner, value[key]) {
+				return false
+			}
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// Format lists the messages, one per line, with the field named by name
+// (or its API name if name is nil)
+func (v ValidationErrors) Format(name func(field string) string) string {
+	fields := make([]string, 0, len(v))
+	for field := range v {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var b strings.Builder
+	for _, field := range fields {
+		label := field
+		if name != nil {
+			label = name(field)
+		}
+		for _, msg := range v[field] {
+			if b.Len() > 0 {
+				b.WriteByte('\n')
+			}
+			if field == nonFieldErrors {
+				fmt.Fprintf(&b, "  - %s", msg)
+			} else {
+				fmt.Fprintf(&b, "  - %s: %s", label, msg)
+			}
+		}
+	}
+	return b.String()
+}
+
+// newAPIError builds an APIError from a response, consuming its body
+func newAPIError(op string, resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	return &APIError{Op: op, StatusCode: resp.StatusCode, Body: string(body)}
+}
+
+// NotFoundError is returned when no object has the name looked up
+type NotFoundError struct {
+	Kind string
+	Name string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found: %s", e.Kind, e.Name)
+}
+
+// IsNotFound reports whether err says a name lookup found nothing, as
+// opposed to the lookup failing
+func IsNotFound(err error) bool {
+	var notFound *NotFoundError
+	return errors.As(err, &notFound)
+}
+
+// DocumentNotFoundError is returned when no document has the ID asked for
+type DocumentNotFoundError struct {
+	ID int
+}
+
+func (e *DocumentNotFoundError) Error() string {
+	return fmt.Sprintf("document %d not found", e.ID)
+}
+
+// IsDocumentNotFound reports whether err says a document doesn't exist,
+// including the 404 of a document download or deletion
+func IsDocumentNotFound(err error) bool {
+	var notFound *DocumentNotFoundError
+	if errors.As(err, &notFound) {
+		return true
+	}
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsForbidden reports whether err is an API error caused by missing permissions
+func IsForbidden(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusForbidden || apiErr.StatusCode == http.StatusUnauthorized
+	}
+	return false
+}
+
+// IsThrottled reports whether err is an API error asking the client to back off
+func IsThrottled(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode == http.StatusServiceUnavailable
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// FilterRule is a condition of a saved view, like "has tag 5". Value is a
+// string whatever the rule type, and nil for rules without one.
+type FilterRule struct {
+	RuleType int     `json:"rule_type"`
+	Value    *string `json:"value"`
+}
+
+// NewFilterRule returns a rule with a value
+func NewFilterRule(ruleType int, value string) FilterRule {
+	return FilterRule{RuleType: ruleType, Value: &value}
+}
+
+// Filter rule types used by the CLI. FilterRuleTypeName names all others.
+const (
+	FilterTitleContains   = 0
+	FilterCorrespondentIs = 3
+	FilterDocumentTypeIs  = 4
+	FilterHasTag          = 6
+	FilterCreatedBefore   = 8
+	FilterCreatedAfter    = 9
+	FilterFulltextQuery   = 20
+)
+
+// filterRuleTypes are the rule types the server knows, in order
+var filterRuleTypes = []string{
+	"title contains",
+	"content contains",
+	"ASN is",
+	"correspondent is",
+	"document type is",
+	"is in inbox",
+	"has tag",
+	"has any tag",
+	"created before",
+	"created after",
+	"created year is",
+	"created month is",
+	"created day is",
+	"added before",
+	"added after",
+	"modified before",
+	"modified after",
+	"does not have tag",
+	"does not have ASN",
+	"title or content contains",
+	"fulltext query",
+	"more like this",
+	"has tags in",
+	"ASN greater than",
+	"ASN less than",
+	"storage path is",
+	"has correspondent in",
+	"does not have correspondent in",
+	"has document type in",
+	"does not have document type in",
+	"has storage path in",
+	"does not have storage path in",
+	"owner is",
+	"has owner in",
+	"does not have owner",
+	"does not have owner in",
+	"has custom field value",
+	"is shared by me",
+	"has custom fields",
+	"has custom field in",
+	"does not have custom field in",
+	"does not have custom field",
+	"custom fields query",
+	"created to",
+	"created from",
+	"added to",
+	"added from",
+	"mime type is",
+}
+
+// FilterRuleTypeName names a rule type like "has_tag", or returns its
+// number if it is unknown
+func FilterRuleTypeName(ruleType int) string {
+	if ruleType < 0 || ruleType >= len(filterRuleTypes) {
+		return strconv.Itoa(ruleType)
+	}
+	return strings.ReplaceAll(strings.ToLower(filterRuleTypes[ruleType]), " ", "_")
+}
+
+// ParseFilterRuleType parses a rule type given by name, like "has_tag" or
+// "has tag", or by number
+func ParseFilterRuleType(s string) (int, bool) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, n >= 0
+	}
+	name := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), " ", "_")
+	for i := range filterRuleTypes {
+		if FilterRuleTypeName(i) == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// ruleParam is the document list parameter a rule type filters by
+type ruleParam struct {
+	name string
+	// multi rules of the same type are combined into one comma-separated
+	// list of IDs
+	multi bool
+	// boolean rules are true if they have no value
+	boolean bool
+}
+
+// filterRuleParams maps rule types to document list parameters, as the web
+// UI does when it opens a saved view
+var filterRuleParams = map[int]ruleParam{
+	0:  {name: "title__icontains"},
+	1:  {name: "content__icontains"},
+	2:  {name: "archive_serial_number"},
+	3:  {name: "correspondent__id"},
+	4:  {name: "document_type__id"},
+	5:  {name: "is_in_inbox", boolean: true},
+	6:  {name: "tags__id__all", multi: true},
+	7:  {name: "is_tagged", boolean: true},
+	8:  {name: "created__date__lt"},
+	9:  {name: "created__date__gt"},
+	10: {name: "created__year"},
+	11: {name: "created__month"},
+	12: {name: "created__day"},
+	13: {name: "added__date__lt"},
+	14: {name: "added__date__gt"},
+	15: {name: "modified__date__lt"},
+	16: {name: "modified__date__gt"},
+	17: {name: "tags__id__none", multi: true},
+	18: {name: "archive_serial_number__isnull", boolean: true},
+	19: {name: "title_content"},
+	20: {name: "query"},
+	21: {name: "more_like_id"},
+	22: {name: "tags__id__in", multi: true},
+	23: {name: "archive_serial_number__gt"},
+	24: {name: "archive_serial_number__lt"},
+	25: {name: "storage_path__id"},
+	26: {name: "correspondent__id__in", multi: true},
+	27: {name: "correspondent__id__none", multi: true},
+	28: {name: "document_type__id__in", multi: true},
+	29: {name: "document_type__id__none", multi: true},
+	30: {name: "storage_path__id__in", multi: true},
+	31: {name: "storage_path__id__none", multi: true},
+	32: {name: "owner__id"},
+	33: {name: "owner__id__in", multi: true},
+	34: {name: "owner__isnull", boolean: true},
+	35: {name: "owner__id__none", multi: true},
+	36: {name: "custom_fields__icontains"},
+	37: {name: "shared_by__id"},
+	38: {name: "custom_fields__id__all", multi: true},
+	39: {name: "custom_fields__id__in", multi: true},
+	40: {name: "custom_fields__id__none", multi: true},
+	41: {name: "has_custom_fields", boolean: true},
+	42: {name: "custom_field_query"},
+	43: {name: "created__date__lte"},
+	44: {name: "created__date__gte"},
+	45: {name: "added__date__lte"},
+	46: {name: "added__date__gte"},
+	47: {name: "mime_type"},
+}
+
+// FilterRuleParams translates saved view rules to document list parameters
+func FilterRuleParams(rules []FilterRule) (url.Values, error) {
+	params := url.Values{}
+	for _, rule := range rules {
+		p, ok := filterRuleParams[rule.RuleType]
+		if !ok {
+			return nil, fmt.Errorf("unsupported filter rule type %d", rule.RuleType)
+		}
+
+		var value string
+		switch {
+		case rule.Value != nil:
+			value = *rule.Value
+		case p.boolean:
+			value = "true"
+		default:
+			// The web UI leaves out rules that are still empty
+			continue
+		}
+
+		if p.multi && params.Has(p.name) {
+			params.Set(p.name, params.Get(p.name)+","+value)
+		} else {
+			params.Set(p.name, value)
+		}
+	}
+	return params, nil
+}
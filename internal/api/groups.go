@@ -0,0 +1,83 @@
+package api
+
+import (
+	"net/url"
+	"slices"
+	"strconv"
+)
+
+// Group is a Paperless user group
+type Group struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Permissions are codenames like "view_document"
+	Permissions []string `json:"permissions"`
+}
+
+func (c *Client) groups() resource[Group] {
+	return resource[Group]{client: c, path: "/api/groups/", name: "group",
+		nameOf: func(g Group) string { return g.Name }}
+}
+
+// ListGroups lists user groups
+func (c *Client) ListGroups(params ListParams) (*PaginatedResponse[Group], error) {
+	return c.groups().list(params)
+}
+
+// GetGroup gets a single group by ID
+func (c *Client) GetGroup(id int) (*Group, error) {
+	return c.groups().get(id)
+}
+
+// CreateGroup creates a group with the given permissions
+func (c *Client) CreateGroup(name string, permissions []string) (*Group, error) {
+	if permissions == nil {
+		permissions = []string{}
+	}
+	return c.groups().create(map[string]interface{}{"name": name, "permissions": permissions})
+}
+
+// UpdateGroup updates a group
+func (c *Client) UpdateGroup(id int, updates map[string]interface{}) (*Group, error) {
+	return c.groups().update(id, updates)
+}
+
+// DeleteGroup deletes a group. Its members keep their accounts.
+func (c *Client) DeleteGroup(id int) error {
+	return c.groups().delete(id)
+}
+
+// FindGroupByName finds a group by name
+func (c *Client) FindGroupByName(name string) (*Group, error) {
+	return c.groups().findByName(name)
+}
+
+// GroupMembers lists the users in a group
+func (c *Client) GroupMembers(groupID int) ([]User, error) {
+	result, err := c.users().list(ListParams{Extra: url.Values{"groups__id": {strconv.Itoa(groupID)}}})
+	if err != nil {
+		return nil, err
+	}
+
+	// Older servers ignore the filter
+	members := result.Results[:0]
+	for _, u := range result.Results {
+		if slices.Contains(u.Groups, groupID) {
+			members = append(members, u)
+		}
+	}
+	return members, nil
+}
+
+// SetGroupMembership adds a user to a group or removes them from it.
+// Membership is stored on the user, so this updates the user.
+func (c *Client) SetGroupMembership(user *User, groupID int, member bool) (*User, error) {
+	groups := slices.DeleteFunc(slices.Clone(user.Groups), func(id int) bool { return id == groupID })
+	if member {
+		groups = append(groups, groupID)
+	}
+	if groups == nil {
+		groups = []int{}
+	}
+	return c.UpdateUser(user.ID, map[string]interface{}{"groups": groups})
+}
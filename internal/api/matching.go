@@ -0,0 +1,48 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Matching algorithms that assign tags, correspondents, document types and
+// storage paths to new documents
+const (
+	MatchNone    = 0
+	MatchAny     = 1
+	MatchAll     = 2
+	MatchLiteral = 3
+	MatchRegex   = 4
+	MatchFuzzy   = 5
+	MatchAuto    = 6
+)
+
+// matchingAlgorithms names the matching algorithms, in order
+var matchingAlgorithms = []string{"none", "any", "all", "literal", "regex", "fuzzy", "auto"}
+
+// MatchingAlgorithmName names a matching algorithm like "literal", or returns
+// its number if it is unknown
+func MatchingAlgorithmName(algorithm int) string {
+	if algorithm < 0 || algorithm >= len(matchingAlgorithms) {
+		return strconv.Itoa(algorithm)
+	}
+	return matchingAlgorithms[algorithm]
+}
+
+// ParseMatchingAlgorithm parses a matching algorithm given by name or by
+// number. "exact" is accepted for "literal", as the web UI calls it.
+func ParseMatchingAlgorithm(s string) (int, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, n >= 0 && n < len(matchingAlgorithms)
+	}
+	if s == "exact" {
+		return MatchLiteral, true
+	}
+	for i, name := range matchingAlgorithms {
+		if name == s {
+			return i, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,273 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// defaultPageSize is used when following all pages of a list
+const defaultPageSize = 1000
+
+// ListParams are the query options shared by the tag, correspondent,
+// document type, storage path, custom field and saved view endpoints
+type ListParams struct {
+	// NameContains filters by case-insensitive substring of the name
+	NameContains string
+	// IDs restricts the result to these object IDs
+	IDs []int
+	// Ordering is a field name, prefixed with "-" for descending order
+	Ordering string
+	// Page requests a single page. Zero fetches all pages.
+	Page     int
+	PageSize int
+	// Extra holds any further query parameters
+	Extra url.Values
+}
+
+// isPlain reports whether the params ask for the unfiltered list, which is
+// the only one worth caching
+func (p ListParams) isPlain() bool {
+	return p.NameContains == "" && p.IDs == nil && p.Ordering == "" && len(p.Extra) == 0 &&
+		(p.PageSize == 0 || p.PageSize == defaultPageSize)
+}
+
+func (p ListParams) values() url.Values {
+	v := url.Values{}
+	for key, values := range p.Extra {
+		v[key] = append([]string(nil), values...)
+	}
+	if p.NameContains != "" {
+		v.Set("name__icontains", p.NameContains)
+	}
+	if len(p.IDs) > 0 {
+		v.Set("id__in", joinIDs(p.IDs))
+	}
+	if p.Ordering != "" {
+		v.Set("ordering", p.Ordering)
+	}
+	if p.Page > 0 {
+		v.Set("page", strconv.Itoa(p.Page))
+	}
+	if p.PageSize > 0 {
+		v.Set("page_size", strconv.Itoa(p.PageSize))
+	}
+	return v
+}
+
+// joinIDs formats IDs for __in style filters
+func joinIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ",")
+}
+
+// listPagePath returns the path of one page of an unfiltered list
+func listPagePath(path string, page int) string {
+	return path + "?" + ListParams{Page: page, PageSize: defaultPageSize}.values().Encode()
+}
+
+// resource is a typed client for one REST collection such as /api/tags/
+type resource[T any] struct {
+	client *Client
+	path   string
+	// name is used in error messages, e.g. "tag 5 not found"
+	name string
+	// cached lists are served through the response cache
+	cached bool
+	// nameOf returns an object's name for lookups by name
+	nameOf func(T) string
+	// nameField is the field filtered on for lookups by name, "name" if empty
+	nameField string
+}
+
+// list returns one page if params.Page is set, otherwise all pages combined
+func (r resource[T]) list(params ListParams) (*PaginatedResponse[T], error) {
+	if params.Page > 0 {
+		return r.page(params)
+	}
+
+	if params.PageSize == 0 {
+		params.PageSize = defaultPageSize
+	}
+	params.Page = 1
+	result, err := r.page(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Follow pages by number rather than the "next" URL, which may point to
+	// the wrong scheme or host behind a reverse proxy
+	for next := result.Next; next != ""; {
+		params.Page++
+		page, err := r.page(params)
+		if err != nil {
+			return nil, err
+		}
+		result.Results = append(result.Results, page.Results...)
+		next = page.Next
+	}
+	result.Next = ""
+
+	return result, nil
+}
+
+func (r resource[T]) page(params ListParams) (*PaginatedResponse[T], error) {
+	path := r.path + "?" + params.values().Encode()
+
+	get := r.client.get
+	if r.cached && params.isPlain() {
+		get = r.client.getCached
+	}
+	resp, err := get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("", resp)
+	}
+
+	var result PaginatedResponse[T]
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r resource[T]) get(id int) (*T, error) {
+	// full_perms includes the view and change permissions of owned objects
+	resp, err := r.client.get(fmt.Sprintf("%s%d/?full_perms=true", r.path, id))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s %d not found", r.name, id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("", resp)
+	}
+
+	var obj T
+	if err := json.NewDecoder(resp.Body).Decode(&obj); err != nil {
+		return nil, err
+	}
+
+	return &obj, nil
+}
+
+func (r resource[T]) create(data map[string]interface{}) (*T, error) {
+	resp, err := r.client.post(r.path, data)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("create", resp)
+	}
+
+	var obj T
+	if err := json.NewDecoder(resp.Body).Decode(&obj); err != nil {
+		return nil, err
+	}
+
+	return &obj, nil
+}
+
+func (r resource[T]) update(id int, updates map[string]interface{}) (*T, error) {
+	resp, err := r.client.patch(fmt.Sprintf("%s%d/", r.path, id), updates)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("update", resp)
+	}
+
+	var obj T
+	if err := json.NewDecoder(resp.Body).Decode(&obj); err != nil {
+		return nil, err
+	}
+
+	return &obj, nil
+}
+
+func (r resource[T]) delete(id int) error {
+	resp, err := r.client.delete(fmt.Sprintf("%s%d/", r.path, id))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return newAPIError("delete", resp)
+	}
+
+	return nil
+}
+
+// findByName returns the object whose name matches case-insensitively
+func (r resource[T]) findByName(name string) (*T, error) {
+	field := r.nameField
+	if field == "" {
+		field = "name"
+	}
+
+	// A name__iexact lookup is one small request however many objects exist
+	result, err := r.page(ListParams{Page: 1, Extra: url.Values{field + "__iexact": {name}}})
+	var apiErr *APIError
+	if err != nil && !errors.As(err, &apiErr) {
+		return nil, err
+	}
+	if err == nil {
+		if obj := r.matchName(result.Results, name); obj != nil {
+			return obj, nil
+		}
+		if result.Count == 0 {
+			return nil, &NotFoundError{Kind: r.name, Name: name}
+		}
+	}
+
+	// The filter was rejected or ignored, so scan the full list instead
+	return r.scanByName(name)
+}
+
+// scanByName looks for the name in the full list. On a miss a cached list is
+// fetched again, as the object may have been created (for example in the
+// web UI) after the list was cached.
+func (r resource[T]) scanByName(name string) (*T, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		result, err := r.list(ListParams{})
+		if err != nil {
+			return nil, err
+		}
+		if obj := r.matchName(result.Results, name); obj != nil {
+			return obj, nil
+		}
+		if !r.cached || !r.client.invalidateList(r.path) {
+			break
+		}
+	}
+	return nil, &NotFoundError{Kind: r.name, Name: name}
+}
+
+func (r resource[T]) matchName(objs []T, name string) *T {
+	for i := range objs {
+		if strings.EqualFold(r.nameOf(objs[i]), name) {
+			return &objs[i]
+		}
+	}
+	return nil
+}
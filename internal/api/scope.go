@@ -0,0 +1,173 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DocumentScope limits every document listing of a client, for example to
+// the documents of one owner or tag. Objects are given by name or ID.
+type DocumentScope struct {
+	// Owner is "me" for the token's user or a user ID
+	Owner         string
+	Tags          []string
+	Correspondent string
+	DocumentType  string
+	StoragePath   string
+}
+
+// IsZero reports whether the scope doesn't restrict anything
+func (s DocumentScope) IsZero() bool {
+	return s.Owner == "" && len(s.Tags) == 0 && s.Correspondent == "" && s.DocumentType == "" && s.StoragePath == ""
+}
+
+// WithDocumentScope restricts document lists and searches to the scope.
+// Names are resolved on first use.
+func WithDocumentScope(scope DocumentScope) Option {
+	return func(c *Client) {
+		c.scope = scope
+	}
+}
+
+// applyScope adds the document scope's filters to query. A filter of the
+// query on the same field must agree with the scope, as the scope can only
+// narrow a listing.
+func (c *Client) applyScope(query url.Values) error {
+	if c.scope.IsZero() {
+		return nil
+	}
+
+	c.scopeOnce.Do(func() {
+		c.scopeQuery, c.scopeErr = c.resolveScope()
+	})
+	if c.scopeErr != nil {
+		return fmt.Errorf("resolving profile filter: %w", c.scopeErr)
+	}
+	for key, values := range c.scopeQuery {
+		// Documents need all tags of both, e.g. of a saved view and the scope
+		if strings.HasSuffix(key, "__all") && query.Has(key) {
+			query.Set(key, query.Get(key)+","+values[0])
+			continue
+		}
+		if query.Has(key) && query.Get(key) != values[0] {
+			return fmt.Errorf("the profile filter limits %s to %s, which conflicts with the requested %s", key, values[0], query.Get(key))
+		}
+		query[key] = values
+	}
+	return nil
+}
+
+// resolveScope turns the scope's names into ID filters, which the server
+// ANDs with the name filters of a listing
+func (c *Client) resolveScope() (url.Values, error) {
+	query := url.Values{}
+
+	if c.scope.Owner != "" {
+		owner := c.scope.Owner
+		if owner == "me" {
+			id, err := c.CurrentUserID()
+			if err != nil {
+				return nil, err
+			}
+			owner = strconv.Itoa(id)
+		} else if _, err := strconv.Atoi(owner); err != nil {
+			return nil, fmt.Errorf("owner must be \"me\" or a user ID, got %q", owner)
+		}
+		query.Set("owner__id", owner)
+	}
+
+	if len(c.scope.Tags) > 0 {
+		ids := make([]int, 0, len(c.scope.Tags))
+		for _, name := range c.scope.Tags {
+			id, err := resolveID(name, func(name string) (int, error) {
+				tag, err := c.FindTagByName(name)
+				if err != nil {
+					return 0, err
+				}
+				return tag.ID, nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		query.Set("tags__id__all", joinIDs(ids))
+	}
+
+	for _, f := range []struct {
+		key, value string
+		find       func(string) (int, error)
+	}{
+		{"correspondent__id", c.scope.Correspondent, func(name string) (int, error) {
+			corr, err := c.FindCorrespondentByName(name)
+			if err != nil {
+				return 0, err
+			}
+			return corr.ID, nil
+		}},
+		{"document_type__id", c.scope.DocumentType, func(name string) (int, error) {
+			dt, err := c.FindDocumentTypeByName(name)
+			if err != nil {
+				return 0, err
+			}
+			return dt.ID, nil
+		}},
+		{"storage_path__id", c.scope.StoragePath, func(name string) (int, error) {
+			sp, err := c.FindStoragePathByName(name)
+			if err != nil {
+				return 0, err
+			}
+			return sp.ID, nil
+		}},
+	} {
+		if f.value == "" {
+			continue
+		}
+		id, err := resolveID(f.value, f.find)
+		if err != nil {
+			return nil, err
+		}
+		query.Set(f.key, strconv.Itoa(id))
+	}
+
+	return query, nil
+}
+
+// resolveID returns s as an ID if it's numeric and looks it up otherwise
+func resolveID(s string, find func(string) (int, error)) (int, error) {
+	if id, err := strconv.Atoi(s); err == nil {
+		return id, nil
+	}
+	return find(s)
+}
+
+// CurrentUserID returns the ID of the user the token belongs to
+func (c *Client) CurrentUserID() (int, error) {
+	resp, err := c.get("/api/ui_settings/")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, newAPIError("", resp)
+	}
+
+	var settings struct {
+		User struct {
+			ID int `json:"id"`
+		} `json:"user"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return 0, err
+	}
+	if settings.User.ID == 0 {
+		return 0, fmt.Errorf("server didn't report the current user")
+	}
+
+	return settings.User.ID, nil
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestApplyScope(t *testing.T) {
+	// IDs need no lookups on the server
+	scope := DocumentScope{Owner: "3", Tags: []string{"5", "6"}, Correspondent: "7"}
+
+	tests := []struct {
+		name    string
+		query   url.Values
+		want    url.Values
+		wantErr bool
+	}{
+		{
+			name:  "empty query",
+			query: url.Values{},
+			want:  url.Values{"owner__id": {"3"}, "tags__id__all": {"5,6"}, "correspondent__id": {"7"}},
+		},
+		{
+			name:  "other filters are kept",
+			query: url.Values{"title__icontains": {"tax"}, "document_type__id": {"2"}},
+			want: url.Values{"title__icontains": {"tax"}, "document_type__id": {"2"},
+				"owner__id": {"3"}, "tags__id__all": {"5,6"}, "correspondent__id": {"7"}},
+		},
+		{
+			name:  "tags are combined",
+			query: url.Values{"tags__id__all": {"1"}},
+			want:  url.Values{"owner__id": {"3"}, "tags__id__all": {"1,5,6"}, "correspondent__id": {"7"}},
+		},
+		{
+			name:  "same value",
+			query: url.Values{"owner__id": {"3"}, "correspondent__id": {"7"}},
+			want:  url.Values{"owner__id": {"3"}, "tags__id__all": {"5,6"}, "correspondent__id": {"7"}},
+		},
+		{
+			name:    "other owner",
+			query:   url.Values{"owner__id": {"9"}},
+			wantErr: true,
+		},
+		{
+			name:    "other correspondent",
+			query:   url.Values{"correspondent__id": {"8"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		c := &Client{scope: scope}
+		err := c.applyScope(tt.query)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: got %v, want an error", tt.name, tt.query)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if tt.query.Encode() != tt.want.Encode() {
+			t.Errorf("%s: got %s, want %s", tt.name, tt.query.Encode(), tt.want.Encode())
+		}
+	}
+}
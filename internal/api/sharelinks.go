@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Share link file versions
+const (
+	ShareArchive  = "archive"
+	ShareOriginal = "original"
+)
+
+// ShareLink is a public link to a document's file
+type ShareLink struct {
+	ID          int        `json:"id"`
+	Created     time.Time  `json:"created"`
+	Expiration  *time.Time `json:"expiration"`
+	Slug        string     `json:"slug"`
+	Document    int        `json:"document"`
+	FileVersion string     `json:"file_version"`
+}
+
+func (c *Client) shareLinks() resource[ShareLink] {
+	return resource[ShareLink]{client: c, path: "/api/share_links/", name: "share link",
+		nameOf: func(l ShareLink) string { return l.Slug }}
+}
+
+// ShareURL returns the public URL of a share link
+func (c *Client) ShareURL(link ShareLink) string {
+	return c.baseURL + "/share/" + link.Slug
+}
+
+// ListShareLinks lists the share links of all documents
+func (c *Client) ListShareLinks(params ListParams) (*PaginatedResponse[ShareLink], error) {
+	if err := c.RequireFeature(FeatureShareLinks); err != nil {
+		return nil, err
+	}
+	return c.shareLinks().list(params)
+}
+
+// DocumentShareLinks lists the share links of one document
+func (c *Client) DocumentShareLinks(docID int) ([]ShareLink, error) {
+	if err := c.RequireFeature(FeatureShareLinks); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.get(fmt.Sprintf("/api/documents/%d/share_links/", docID))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &DocumentNotFoundError{ID: docID}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("", resp)
+	}
+
+	var links []ShareLink
+	if err := json.NewDecoder(resp.Body).Decode(&links); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+// CreateShareLink shares a document's archived or original file. A nil
+// expiration creates a link that never expires.
+func (c *Client) CreateShareLink(docID int, expiration *time.Time, fileVersion string) (*ShareLink, error) {
+	if err := c.RequireFeature(FeatureShareLinks); err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{
+		"document":     docID,
+		"file_version": fileVersion,
+		"expiration":   nil,
+	}
+	if expiration != nil {
+		data["expiration"] = expiration.UTC().Format(time.RFC3339)
+	}
+	return c.shareLinks().create(data)
+}
+
+// DeleteShareLink revokes a share link
+func (c *Client) DeleteShareLink(id int) error {
+	return c.shareLinks().delete(id)
+}
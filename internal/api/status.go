@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ConsumerStatus is a progress update of a file being consumed, as pushed
+// by the server's status websocket
+type ConsumerStatus struct {
+	Filename        string `json:"filename"`
+	TaskID          string `json:"task_id"`
+	CurrentProgress int    `json:"current_progress"`
+	MaxProgress     int    `json:"max_progress"`
+	// Status is STARTED, WORKING, SUCCESS or FAILED
+	Status string `json:"status"`
+	// Message names the stage, like "parsing_document", or holds the error
+	// of a failed file
+	Message    string `json:"message"`
+	DocumentID *int   `json:"document_id"`
+}
+
+// Finished reports whether the file is done, successfully or not
+func (s ConsumerStatus) Finished() bool {
+	return s.Status == "SUCCESS" || s.Status == "FAILED"
+}
+
+// StatusStream receives consumption updates of all files the token's user
+// may see
+type StatusStream struct {
+	ws *wsConn
+}
+
+// OpenStatusStream subscribes to consumption updates until ctx is done or
+// the stream is closed. Servers that only accept session logins on the
+// websocket refuse it with an *APIError.
+func (c *Client) OpenStatusStream(ctx context.Context) (*StatusStream, error) {
+	ws, err := c.dialWebSocket(ctx, "/ws/status/")
+	if err != nil {
+		return nil, err
+	}
+	return &StatusStream{ws: ws}, nil
+}
+
+// Next waits for the next consumption update. Other messages, like
+// notices about deleted documents, are skipped.
+func (s *StatusStream) Next() (*ConsumerStatus, error) {
+	for {
+		msg, err := s.ws.readMessage()
+		if err != nil {
+			return nil, err
+		}
+
+		var envelope struct {
+			Type string          `json:"type"`
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(msg, &envelope); err != nil {
+			continue
+		}
+
+		// Older versions send the update without an envelope
+		data := msg
+		if envelope.Type != "" {
+			if envelope.Type != "status_update" {
+				continue
+			}
+			data = envelope.Data
+		}
+
+		var status ConsumerStatus
+		if err := json.Unmarshal(data, &status); err != nil || status.TaskID == "" {
+			continue
+		}
+		return &status, nil
+	}
+}
+
+// Close ends the subscription
+func (s *StatusStream) Close() error {
+	return s.ws.close()
+}
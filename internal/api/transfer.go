@@ -0,0 +1,34 @@
+package api
+
+import "io"
+
+// ProgressFunc receives the number of bytes transferred so far and the
+// expected total (-1 if unknown)
+type ProgressFunc func(done, total int64)
+
+// progressReader reports read progress to fn
+type progressReader struct {
+	r     io.Reader
+	total int64
+	done  int64
+	fn    ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.done += int64(n)
+	if p.fn != nil && n > 0 {
+		p.fn(p.done, p.total)
+	}
+	return n, err
+}
+
+// countingWriter counts the bytes written to it
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	w.n += int64(len(b))
+	return len(b), nil
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// WebSocket opcodes used by wsConn
+const (
+	wsText  = 0x1
+	wsClose = 0x8
+	wsPing  = 0x9
+	wsPong  = 0xA
+)
+
+// wsMaxMessage caps the size of a message read from the server
+const wsMaxMessage = 1 << 20
+
+// wsGUID is appended to the handshake key to prove the server speaks
+// WebSocket
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsConn is the client side of a WebSocket connection (RFC 6455), enough
+// to read the JSON messages Paperless pushes
+type wsConn struct {
+	rw io.ReadWriteCloser
+	r  *bufio.Reader
+
+	// mu serializes writes, as pongs are sent while reading
+	mu sync.Mutex
+}
+
+// upgradeTransport returns a transport for WebSocket handshakes. HTTP/2
+// has no Upgrade, so only HTTP/1.1 is offered.
+func (c *Client) upgradeTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.ForceAttemptHTTP2 = false
+	t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	if c.tlsConfig != nil {
+		t.TLSClientConfig = c.tlsConfig.Clone()
+	} else {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	t.TLSClientConfig.NextProtos = []string{"http/1.1"}
+	return t
+}
+
+// dialWebSocket opens a WebSocket at path, authenticated like API
+// requests. The connection is closed when ctx is done.
+func (c *Client) dialWebSocket(ctx context.Context, path string) (*wsConn, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req, err := c.newRequest("GET", path, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	// Paperless checks the origin against its allowed hosts
+	req.Header.Set("Origin", c.baseURL)
+
+	resp, err := (&http.Client{Transport: c.upgradeTransport()}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer resp.Body.Close()
+		return nil, newAPIError("websocket", resp)
+	}
+
+	rw, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok || resp.Header.Get("Sec-WebSocket-Accept") != wsAccept(key) {
+		resp.Body.Close()
+		return nil, fmt.Errorf("websocket: invalid handshake response")
+	}
+	ws := &wsConn{rw: rw, r: bufio.NewReader(rw)}
+	context.AfterFunc(ctx, func() { rw.Close() })
+	return ws, nil
+}
+
+// wsAccept returns the Sec-WebSocket-Accept value the server must send
+// for key
+func wsAccept(key string) string {
+	sum := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// readMessage returns the next data message, answering pings on the way.
+// It returns io.EOF once the server closes the connection.
+func (ws *wsConn) readMessage() ([]byte, error) {
+	var msg []byte
+	for {
+		fin, opcode, payload, err := ws.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case wsPing:
+			if err := ws.writeFrame(wsPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			ws.writeFrame(wsClose, payload[:min(2, len(payload))])
+			return nil, io.EOF
+		}
+
+		msg = append(msg, payload...)
+		if len(msg) > wsMaxMessage {
+			return nil, fmt.Errorf("websocket: message exceeds %d bytes", wsMaxMessage)
+		}
+		if fin {
+			return msg, nil
+		}
+	}
+}
+
+// readFrame reads one frame and unmasks its payload
+func (ws *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(ws.r, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0f
+	masked := header[1]&0x80 != 0
+
+	n := uint64(header[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(ws.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(ws.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > wsMaxMessage {
+		return false, 0, nil, fmt.Errorf("websocket: frame exceeds %d bytes", wsMaxMessage)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(ws.r, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, n)
+	if _, err := io.ReadFull(ws.r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeFrame sends a single masked frame, as clients must
+func (ws *wsConn) writeFrame(opcode byte, payload []byte) error {
+	frame := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, 0x80|byte(n))
+	case n <= 0xffff:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	_, err := ws.rw.Write(frame)
+	return err
+}
+
+// close says goodbye to the server and closes the connection
+func (ws *wsConn) close() error {
+	ws.writeFrame(wsClose, []byte{0x03, 0xe8})
+	return ws.rw.Close()
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Dir is a simple key/value store keeping one file per key in a directory
+type Dir struct {
+	path string
+}
+
+// New returns a store rooted at path. The directory is created on first write.
+func New(path string) *Dir {
+	return &Dir{path: path}
+}
+
+// DefaultDir returns the cache directory path
+func DefaultDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cache", "paperless-cli"), nil
+}
+
+// Path returns the directory the store writes to
+func (d *Dir) Path() string {
+	return d.path
+}
+
+// Get returns the data stored under key
+func (d *Dir) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(d.file(key))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Put stores data under key, replacing any previous value atomically
+func (d *Dir) Put(key string, data []byte) error {
+	if err := os.MkdirAll(d.path, 0700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(d.path, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), d.file(key))
+}
+
+// Delete removes the data stored under key
+func (d *Dir) Delete(key string) error {
+	err := os.Remove(d.file(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Clear removes all stored entries and returns how many there were
+func (d *Dir) Clear() (int, error) {
+	files, err := filepath.Glob(filepath.Join(d.path, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+	for _, f := range files {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+	}
+	return len(files), nil
+}
+
+func (d *Dir) file(key string) string {
+	return filepath.Join(d.path, key+".json")
+}
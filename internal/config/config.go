@@ -4,16 +4,205 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
-// Config holds the CLI configuration
+// Profile holds the settings for connecting to one Paperless instance
+type Profile struct {
+	URL         string `yaml:"url"`
+	Token       string `yaml:"token"`
+	CAFile      string `yaml:"ca_file,omitempty"`
+	Fingerprint string `yaml:"fingerprint,omitempty"`
+	Insecure    bool   `yaml:"insecure,omitempty"`
+	ClientCert  string `yaml:"client_cert,omitempty"`
+	ClientKey   string `yaml:"client_key,omitempty"`
+
+	// Filter is ANDed into every document query, e.g. "owner:me" or
+	// "tag:business"
+	Filter []string `yaml:"filter,omitempty"`
+
+	// Hold names the tag and boolean custom field that put documents on
+	// legal hold, DefaultHold if empty
+	Hold string `yaml:"hold,omitempty"`
+}
+
+// FilterKeys are the fields a profile filter can restrict
+var FilterKeys = []string{"owner", "tag", "correspondent", "type", "storage_path"}
+
+// ParseFilter splits a filter like "tag:business" or "owner=me" into its
+// key and value
+func ParseFilter(filter string) (key, value string, err error) {
+	i := strings.IndexAny(filter, ":=")
+	if i < 1 || strings.TrimSpace(filter[i+1:]) == "" {
+		return "", "", fmt.Errorf("invalid filter %q, expected key:value with key one of %s", filter, strings.Join(FilterKeys, ", "))
+	}
+	key = strings.ToLower(strings.TrimSpace(filter[:i]))
+	value = strings.TrimSpace(filter[i+1:])
+	if key == "document_type" {
+		key = "type"
+	}
+	for _, k := range FilterKeys {
+		if k == key {
+			return key, value, nil
+		}
+	}
+	return "", "", fmt.Errorf("invalid filter %q: unknown key %s (valid keys: %s)", filter, key, strings.Join(FilterKeys, ", "))
+}
+
+// Config holds the CLI configuration. The top-level connection settings
+// form the default profile.
 type Config struct {
-	URL   string `yaml:"url"`
-	Token string `yaml:"token"`
+	Profile  `yaml:",inline"`
+	CacheTTL string `yaml:"cache_ttl,omitempty"`
+
+	// Profiles are further instances, selected with --profile
+	Profiles map[string]*Profile `yaml:"profiles,omitempty"`
+
+	// Implications are rules like "tag:insurance implies tag:finance"
+	Implications []string `yaml:"implications,omitempty"`
+
+	// WatchRules pick upload metadata for files found by the watch command
+	WatchRules []WatchRule `yaml:"watch_rules,omitempty"`
+}
+
+// WatchRule applies metadata to watched files whose path relative to the
+// watched directory matches Match
+type WatchRule struct {
+	Match         string   `yaml:"match"`
+	Correspondent string   `yaml:"correspondent,omitempty"`
+	DocumentType  string   `yaml:"document_type,omitempty"`
+	StoragePath   string   `yaml:"storage_path,omitempty"`
+	Tags          []string `yaml:"tags,omitempty"`
+}
+
+// DefaultProfile names the top-level connection settings
+const DefaultProfile = "default"
+
+// activeProfile is the profile selected with UseProfile, "" for the default
+var activeProfile string
+
+// UseProfile selects the profile that the getters and setters work on. An
+// empty name selects the default profile.
+func UseProfile(name string) {
+	if name == DefaultProfile {
+		name = ""
+	}
+	activeProfile = name
+}
+
+// ActiveProfile returns the name of the selected profile
+func ActiveProfile() string {
+	if activeProfile == "" {
+		return DefaultProfile
+	}
+	return activeProfile
+}
+
+// Current returns the settings of the selected profile, creating it if it
+// doesn't exist yet
+func (cfg *Config) Current() *Profile {
+	if activeProfile == "" {
+		return &cfg.Profile
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]*Profile)
+	}
+	p, ok := cfg.Profiles[activeProfile]
+	if !ok || p == nil {
+		p = &Profile{}
+		cfg.Profiles[activeProfile] = p
+	}
+	return p
+}
+
+// Settings returns the connection settings of a profile. The environment
+// variables override the default profile only.
+func Settings(name string) (Profile, error) {
+	cfg, err := Load()
+	if err != nil {
+		return Profile{}, err
+	}
+
+	if name != "" && name != DefaultProfile {
+		p, ok := cfg.Profiles[name]
+		if !ok || p == nil {
+			return Profile{}, fmt.Errorf("unknown profile: %s", name)
+		}
+		return *p, nil
+	}
+
+	p := cfg.Profile
+	for env, field := range map[string]*string{
+		"PAPERLESS_URL":         &p.URL,
+		"PAPERLESS_TOKEN":       &p.Token,
+		"PAPERLESS_CA_FILE":     &p.CAFile,
+		"PAPERLESS_CLIENT_CERT": &p.ClientCert,
+		"PAPERLESS_CLIENT_KEY":  &p.ClientKey,
+	} {
+		if v := os.Getenv(env); v != "" {
+			*field = v
+		}
+	}
+	return p, nil
+}
+
+// ProfileNames lists the configured profiles, the default profile first if
+// it has a URL
+func ProfileNames() ([]string, error) {
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if def, err := Settings(DefaultProfile); err == nil && def.URL != "" {
+		names = append(names, DefaultProfile)
+	}
+	named := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		named = append(named, name)
+	}
+	sort.Strings(named)
+	return append(names, named...), nil
+}
+
+// RemoveProfile deletes a named profile from config
+func RemoveProfile(name string) error {
+	if name == DefaultProfile {
+		return fmt.Errorf("the default profile can't be removed")
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Profiles[name]; !ok {
+		return fmt.Errorf("unknown profile: %s", name)
+	}
+	delete(cfg.Profiles, name)
+	return Save(cfg)
 }
 
+// current returns the settings of the selected profile
+func current() Profile {
+	p, _ := Settings(activeProfile)
+	return p
+}
+
+// DefaultHold is the tag and custom field name marking documents on legal
+// hold
+const DefaultHold = "legal-hold"
+
+// DefaultCacheTTL is how long cached tags, correspondents and types are used
+// without asking the server
+const DefaultCacheTTL = 5 * time.Minute
+
 // configDir returns the config directory path
 func configDir() (string, error) {
 	home, err := os.UserHomeDir()
@@ -32,8 +221,17 @@ func configPath() (string, error) {
 	return filepath.Join(dir, "config.yaml"), nil
 }
 
-// Load loads the configuration from file
+// loaded is the config file as read by the first Load, so that the many
+// getters don't each read and parse it again. Save replaces it.
+var loaded *Config
+
+// Load loads the configuration from file. The file is read once per process;
+// each call returns a copy that may be modified and saved.
 func Load() (*Config, error) {
+	if loaded != nil {
+		return loaded.clone(), nil
+	}
+
 	path, err := configPath()
 	if err != nil {
 		return nil, err
@@ -52,7 +250,31 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
 
-	return &cfg, nil
+	loaded = &cfg
+	return cfg.clone(), nil
+}
+
+// clone returns a deep copy of cfg
+func (cfg *Config) clone() *Config {
+	c := *cfg
+	c.Filter = slices.Clone(cfg.Filter)
+	if cfg.Profiles != nil {
+		c.Profiles = make(map[string]*Profile, len(cfg.Profiles))
+		for name, p := range cfg.Profiles {
+			if p != nil {
+				cp := *p
+				cp.Filter = slices.Clone(p.Filter)
+				p = &cp
+			}
+			c.Profiles[name] = p
+		}
+	}
+	c.Implications = slices.Clone(cfg.Implications)
+	c.WatchRules = slices.Clone(cfg.WatchRules)
+	for i := range c.WatchRules {
+		c.WatchRules[i].Tags = slices.Clone(c.WatchRules[i].Tags)
+	}
+	return &c
 }
 
 // Save saves the configuration to file
@@ -76,49 +298,272 @@ func Save(cfg *Config) error {
 		return err
 	}
 
-	return os.WriteFile(path, data, 0600)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	loaded = cfg.clone()
+	return nil
 }
 
 // GetURL returns the Paperless URL from env or config
 func GetURL() string {
-	if url := os.Getenv("PAPERLESS_URL"); url != "" {
-		return url
+	return current().URL
+}
+
+// GetToken returns the API token from env or config
+func GetToken() string {
+	return current().Token
+}
+
+// SetURL saves the URL to config
+func SetURL(url string) error {
+	cfg, err := Load()
+	if err != nil {
+		cfg = &Config{}
 	}
+	cfg.Current().URL = url
+	return Save(cfg)
+}
+
+// SetToken saves the token to config
+func SetToken(token string) error {
 	cfg, err := Load()
 	if err != nil {
-		return ""
+		cfg = &Config{}
 	}
-	return cfg.URL
+	cfg.Current().Token = token
+	return Save(cfg)
 }
 
-// GetToken returns the API token from env or config
-func GetToken() string {
-	if token := os.Getenv("PAPERLESS_TOKEN"); token != "" {
-		return token
+// GetCAFile returns the custom CA bundle path from env or config
+func GetCAFile() string {
+	return current().CAFile
+}
+
+// GetFingerprint returns the pinned server certificate fingerprint from config
+func GetFingerprint() string {
+	return current().Fingerprint
+}
+
+// GetInsecure reports whether TLS verification is disabled in config
+func GetInsecure() bool {
+	return current().Insecure
+}
+
+// GetClientCert returns the mTLS client certificate path from env or config
+func GetClientCert() string {
+	return current().ClientCert
+}
+
+// GetClientKey returns the mTLS client key path from env or config
+func GetClientKey() string {
+	return current().ClientKey
+}
+
+// GetHold returns the name of the legal hold tag and custom field
+func GetHold() string {
+	if hold := current().Hold; hold != "" {
+		return hold
 	}
-	cfg, err := Load()
+	return DefaultHold
+}
+
+// GetCacheTTL returns the metadata cache TTL from env or config
+func GetCacheTTL() time.Duration {
+	value := os.Getenv("PAPERLESS_CACHE_TTL")
+	if value == "" {
+		cfg, err := Load()
+		if err != nil || cfg.CacheTTL == "" {
+			return DefaultCacheTTL
+		}
+		value = cfg.CacheTTL
+	}
+
+	ttl, err := time.ParseDuration(value)
 	if err != nil {
-		return ""
+		return DefaultCacheTTL
 	}
-	return cfg.Token
+	return ttl
 }
 
-// SetURL saves the URL to config
-func SetURL(url string) error {
+// Keys lists the settings accepted by Set
+var Keys = []string{"url", "token", "ca-file", "fingerprint", "insecure", "client-cert", "client-key", "cache-ttl", "filter", "hold"}
+
+// Set saves a single setting by key
+func Set(key, value string) error {
 	cfg, err := Load()
 	if err != nil {
 		cfg = &Config{}
 	}
-	cfg.URL = url
+
+	p := cfg.Current()
+	switch key {
+	case "url":
+		p.URL = value
+	case "token":
+		p.Token = value
+	case "ca-file":
+		p.CAFile = value
+	case "fingerprint":
+		p.Fingerprint = value
+	case "insecure":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for insecure: %s", value)
+		}
+		p.Insecure = b
+	case "client-cert":
+		p.ClientCert = value
+	case "client-key":
+		p.ClientKey = value
+	case "filter":
+		var filters []string
+		for _, f := range strings.Split(value, ",") {
+			if f = strings.TrimSpace(f); f == "" {
+				continue
+			}
+			if _, _, err := ParseFilter(f); err != nil {
+				return err
+			}
+			filters = append(filters, f)
+		}
+		p.Filter = filters
+	case "hold":
+		p.Hold = strings.TrimSpace(value)
+	case "cache-ttl":
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("invalid value for cache-ttl: %s (use a duration like 10m or 0 to always revalidate)", value)
+		}
+		cfg.CacheTTL = value
+	default:
+		return fmt.Errorf("unknown config key: %s (valid keys: %s)", key, strings.Join(Keys, ", "))
+	}
+
 	return Save(cfg)
 }
 
-// SetToken saves the token to config
-func SetToken(token string) error {
+// Implication says that documents tagged Tag should also be tagged Implies
+type Implication struct {
+	Tag     string
+	Implies string
+}
+
+func (i Implication) String() string {
+	return "tag:" + i.Tag + " implies tag:" + i.Implies
+}
+
+// ParseImplication parses a rule of the form "tag:A implies tag:B". The
+// "tag:" prefixes are optional.
+func ParseImplication(rule string) (Implication, error) {
+	left, right, ok := strings.Cut(rule, " implies ")
+	if !ok {
+		return Implication{}, fmt.Errorf("invalid implication %q, expected \"tag:A implies tag:B\"", rule)
+	}
+
+	parse := func(s string) (string, error) {
+		s = strings.TrimSpace(s)
+		if kind, name, ok := strings.Cut(s, ":"); ok {
+			switch kind {
+			case "tag":
+				s = strings.TrimSpace(name)
+			case "correspondent", "type", "document_type", "storage_path":
+				return "", fmt.Errorf("invalid implication %q: only tag implications are supported", rule)
+			}
+		}
+		if s == "" {
+			return "", fmt.Errorf("invalid implication %q: empty tag name", rule)
+		}
+		return s, nil
+	}
+
+	var imp Implication
+	var err error
+	if imp.Tag, err = parse(left); err != nil {
+		return Implication{}, err
+	}
+	if imp.Implies, err = parse(right); err != nil {
+		return Implication{}, err
+	}
+	if strings.EqualFold(imp.Tag, imp.Implies) {
+		return Implication{}, fmt.Errorf("invalid implication %q: a tag can't imply itself", rule)
+	}
+	return imp, nil
+}
+
+// GetImplications returns the tag implication rules from config
+func GetImplications() ([]Implication, error) {
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]Implication, 0, len(cfg.Implications))
+	for _, rule := range cfg.Implications {
+		imp, err := ParseImplication(rule)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, imp)
+	}
+	return rules, nil
+}
+
+// AddImplication validates a rule and saves it to config
+func AddImplication(rule string) (Implication, error) {
+	imp, err := ParseImplication(rule)
+	if err != nil {
+		return Implication{}, err
+	}
+
 	cfg, err := Load()
 	if err != nil {
 		cfg = &Config{}
 	}
-	cfg.Token = token
+	for _, existing := range cfg.Implications {
+		if other, err := ParseImplication(existing); err == nil && strings.EqualFold(other.String(), imp.String()) {
+			return imp, nil
+		}
+	}
+	cfg.Implications = append(cfg.Implications, imp.String())
+	return imp, Save(cfg)
+}
+
+// RemoveImplication deletes a rule from config
+func RemoveImplication(rule string) error {
+	imp, err := ParseImplication(rule)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		return err
+	}
+
+	kept := cfg.Implications[:0]
+	for _, existing := range cfg.Implications {
+		if other, err := ParseImplication(existing); err == nil && strings.EqualFold(other.String(), imp.String()) {
+			continue
+		}
+		kept = append(kept, existing)
+	}
+	if len(kept) == len(cfg.Implications) {
+		return fmt.Errorf("no such implication: %s", imp)
+	}
+	cfg.Implications = kept
 	return Save(cfg)
 }
+
+// GetWatchRules returns the watch rules from config, in order
+func GetWatchRules() ([]WatchRule, error) {
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	for i, rule := range cfg.WatchRules {
+		if strings.TrimSpace(rule.Match) == "" {
+			return nil, fmt.Errorf("watch rule %d: match is empty", i+1)
+		}
+	}
+	return cfg.WatchRules, nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WorkspaceFile is the name of the file with project settings, looked up
+// from the current directory upward
+const WorkspaceFile = ".paperless.yaml"
+
+// Workspace holds the settings of a project directory, like the profile
+// and upload defaults for a folder of tax documents
+type Workspace struct {
+	// Path is the file the settings were read from
+	Path string `yaml:"-" json:"path"`
+
+	Profile       string   `yaml:"profile,omitempty" json:"profile,omitempty"`
+	Tags          []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Correspondent string   `yaml:"correspondent,omitempty" json:"correspondent,omitempty"`
+	DocumentType  string   `yaml:"document_type,omitempty" json:"document_type,omitempty"`
+	StoragePath   string   `yaml:"storage_path,omitempty" json:"storage_path,omitempty"`
+
+	// Title is the title template of uploads, e.g. "Taxes 2024: {name}"
+	Title string `yaml:"title,omitempty" json:"title,omitempty"`
+}
+
+// FindWorkspace reads the nearest WorkspaceFile in dir or one of its
+// parents. It returns nil if there is none.
+func FindWorkspace(dir string) (*Workspace, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		path := filepath.Join(dir, WorkspaceFile)
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return parseWorkspace(path, data)
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// parseWorkspace parses a workspace file, rejecting unknown keys so typos
+// don't go unnoticed
+func parseWorkspace(path string, data []byte) (*Workspace, error) {
+	ws := &Workspace{Path: path}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(ws); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return ws, nil
+}